@@ -27,8 +27,8 @@ func (m *psqlCampaignRepository) CreateCampaign(c echo.Context, campaign *models
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	now := time.Now()
-	query := `INSERT INTO campaigns (name, description, start_date, end_date, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	query := `INSERT INTO campaigns (name, description, start_date, end_date, status, stacking_policy, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 	stmt, err := m.Conn.Prepare(query)
 
 	if err != nil {
@@ -45,7 +45,9 @@ func (m *psqlCampaignRepository) CreateCampaign(c echo.Context, campaign *models
 		return err
 	}
 
-	err = stmt.QueryRow(campaign.Name, campaign.Description, campaign.StartDate, campaign.EndDate, campaign.Status, &now).Scan(&lastID)
+	stackingPolicy := campaign.GetStackingPolicy()
+	err = stmt.QueryRow(campaign.Name, campaign.Description, campaign.StartDate, campaign.EndDate, campaign.Status,
+		stackingPolicy, &now).Scan(&lastID)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -54,6 +56,7 @@ func (m *psqlCampaignRepository) CreateCampaign(c echo.Context, campaign *models
 	}
 
 	campaign.ID = lastID
+	campaign.StackingPolicy = &stackingPolicy
 	campaign.CreatedAt = &now
 	return nil
 }
@@ -136,7 +139,8 @@ func (m *psqlCampaignRepository) GetCampaign(c echo.Context, payload map[string]
 	requestLogger := logger.GetRequestLogger(c, nil)
 	paging := ""
 	where := ""
-	query := `SELECT id, name, description, start_date, end_date, status, type, validators, updated_at, created_at FROM campaigns WHERE id IS NOT NULL`
+	query := `SELECT id, name, description, start_date, end_date, status, stacking_policy, updated_at, created_at
+		FROM campaigns WHERE id IS NOT NULL`
 
 	if payload["page"].(int) > 0 || payload["limit"].(int) > 0 {
 		paging = fmt.Sprintf(" LIMIT %d OFFSET %d", payload["limit"].(int), ((payload["page"].(int) - 1) * payload["limit"].(int)))
@@ -196,6 +200,7 @@ func (m *psqlCampaignRepository) getCampaign(c echo.Context, query string) ([]*m
 			&t.StartDate,
 			&t.EndDate,
 			&t.Status,
+			&t.StackingPolicy,
 			&updateDate,
 			&createDate,
 		)
@@ -230,9 +235,9 @@ func (m *psqlCampaignRepository) GetCampaignAvailable(c echo.Context, today stri
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 
-	query := fmt.Sprintf(`SELECT id, name, description, start_date, end_date, status, updated_at, created_at
-		FROM campaigns WHERE status = 1 AND start_date::date <= '%s'
-		AND end_date::date >= '%s' ORDER BY start_date DESC`, today, today)
+	query := fmt.Sprintf(`SELECT id, name, description, start_date, end_date, status, stacking_policy, updated_at,
+		created_at FROM campaigns WHERE status = 1 AND start_date::date <= '%s'
+		AND end_date::date >= '%s' ORDER BY start_date DESC, id ASC`, today, today)
 
 	res, err := m.getCampaign(c, query)
 
@@ -327,7 +332,8 @@ func (m *psqlCampaignRepository) GetCampaignDetail(c echo.Context, id int64) (*m
 	var createDate, updateDate pq.NullTime
 	result := new(models.Campaign)
 
-	query := `SELECT id, name, description, start_date, end_date, status, type, validators, updated_at, created_at FROM campaigns WHERE id = $1`
+	query := `SELECT id, name, description, start_date, end_date, status, stacking_policy, updated_at, created_at
+		FROM campaigns WHERE id = $1`
 
 	err := m.Conn.QueryRow(query, id).Scan(
 		&result.ID,
@@ -336,8 +342,9 @@ func (m *psqlCampaignRepository) GetCampaignDetail(c echo.Context, id int64) (*m
 		&result.StartDate,
 		&result.EndDate,
 		&result.Status,
-		&createDate,
+		&result.StackingPolicy,
 		&updateDate,
+		&createDate,
 	)
 
 	if err != nil {
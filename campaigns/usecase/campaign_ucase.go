@@ -28,6 +28,38 @@ func NewCampaignUseCase(cmpgn campaigns.Repository, rwd rewards.UseCase) campaig
 func (cmpgn *campaignUseCase) CreateCampaign(c echo.Context, campaign *models.Campaign) error {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if !campaign.IsValidStackingPolicy() {
+		requestLogger.Debug(models.ErrStackingPolicy)
+
+		return models.ErrStackingPolicy
+	}
+
+	// make sure the rewards rules, formula, custom period and point expiry is well formed before storing anything
+	for _, reward := range *campaign.Rewards {
+		if err := reward.Validators.Check(); err != nil {
+			requestLogger.Debug(err)
+
+			return err
+		}
+
+		if reward.CustomPeriod != nil {
+			if err := reward.CustomPeriod.Check(); err != nil {
+				requestLogger.Debug(err)
+
+				return err
+			}
+		}
+
+		if reward.PointExpiry != nil {
+			if err := reward.PointExpiry.Check(); err != nil {
+				requestLogger.Debug(err)
+
+				return err
+			}
+		}
+	}
+
 	err := cmpgn.campaignRepo.CreateCampaign(c, campaign)
 
 	if err != nil {
@@ -0,0 +1,13 @@
+package goldprices
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the gold prices repository contract
+type Repository interface {
+	GetGoldPrice(echo.Context, time.Time) (*models.GoldPrice, error)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"encoding/json"
+	"gade/srv-gade-point/goldprices"
+	"gade/srv-gade-point/models"
+	"io/ioutil"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+type fileGoldPriceRepository struct {
+	Path string
+}
+
+// NewFileGoldPriceRepository will create an object that represent the goldprices.Repository interface,
+// the gold price is read from a json file and meant to be used for local development
+func NewFileGoldPriceRepository(path string) goldprices.Repository {
+	return &fileGoldPriceRepository{path}
+}
+
+func (gpRepo *fileGoldPriceRepository) GetGoldPrice(c echo.Context, priceDate time.Time) (*models.GoldPrice, error) {
+	var goldPrice models.GoldPrice
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	content, err := ioutil.ReadFile(gpRepo.Path)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	if err = json.Unmarshal(content, &goldPrice); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	if goldPrice.PriceDate == nil {
+		goldPrice.PriceDate = &priceDate
+	}
+
+	goldPrice.Source = models.GoldPriceSourceFile
+
+	return &goldPrice, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"database/sql"
+	"gade/srv-gade-point/goldprices"
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/lib/pq"
+)
+
+type psqlGoldPriceRepository struct {
+	Conn *sql.DB
+}
+
+// NewPsqlGoldPriceRepository will create an object that represent the goldprices.Repository interface
+func NewPsqlGoldPriceRepository(Conn *sql.DB) goldprices.Repository {
+	return &psqlGoldPriceRepository{Conn}
+}
+
+func (gpRepo *psqlGoldPriceRepository) GetGoldPrice(c echo.Context, priceDate time.Time) (*models.GoldPrice, error) {
+	var createDate, goldPriceDate pq.NullTime
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	goldPrice := models.GoldPrice{Source: models.GoldPriceSourceDB}
+	query := `SELECT id, price, price_date, created_at FROM gold_prices WHERE price_date <= $1
+		ORDER BY price_date DESC LIMIT 1`
+	err := gpRepo.Conn.QueryRow(query, priceDate).Scan(&goldPrice.ID, &goldPrice.Price, &goldPriceDate, &createDate)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	goldPrice.PriceDate = &goldPriceDate.Time
+	goldPrice.CreatedAt = &createDate.Time
+
+	return &goldPrice, nil
+}
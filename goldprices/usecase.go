@@ -0,0 +1,13 @@
+package goldprices
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the gold prices usecases
+type UseCase interface {
+	GetGoldback(echo.Context, float64, time.Time) (float64, *models.GoldPrice, error)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"gade/srv-gade-point/goldprices"
+	"gade/srv-gade-point/models"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+type goldPriceUseCase struct {
+	goldPriceRepo goldprices.Repository
+}
+
+// NewGoldPriceUseCase will create new an goldPriceUseCase object representation of goldprices.UseCase interface
+func NewGoldPriceUseCase(gpRepo goldprices.Repository) goldprices.UseCase {
+	return &goldPriceUseCase{
+		goldPriceRepo: gpRepo,
+	}
+}
+
+func (gp *goldPriceUseCase) GetGoldback(c echo.Context, value float64, trxDate time.Time) (float64, *models.GoldPrice, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	goldPrice, err := gp.goldPriceRepo.GetGoldPrice(c, trxDate)
+
+	if err != nil || goldPrice.Price <= 0 {
+		requestLogger.Debug(models.ErrGoldPriceUnavailable)
+
+		return 0, nil, models.ErrGoldPriceUnavailable
+	}
+
+	precision, err := strconv.Atoi(os.Getenv(`GOLDBACK_PRECISION`))
+
+	if err != nil || precision < 0 {
+		precision = models.GoldbackDefaultPrecision
+	}
+
+	return goldPrice.ToGram(value, precision, os.Getenv(`GOLDBACK_ROUNDING`)), goldPrice, nil
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"gade/srv-gade-point/leaderboards"
+	"gade/srv-gade-point/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+var response models.Response
+
+// LeaderboardsHandler represent the httphandler for leaderboards
+type LeaderboardsHandler struct {
+	LeaderboardUseCase leaderboards.UseCase
+}
+
+// NewLeaderboardsHandler represent to register leaderboards endpoint
+func NewLeaderboardsHandler(echoGroup models.EchoGroup, ldbUs leaderboards.UseCase) {
+	handler := &LeaderboardsHandler{
+		LeaderboardUseCase: ldbUs,
+	}
+
+	// End Point For CMS
+	echoGroup.Admin.GET("/leaderboards", handler.getLeaderboard)
+
+	// End Point For External
+	echoGroup.API.GET("/leaderboards/rank", handler.getRank)
+}
+
+func (ldb *LeaderboardsHandler) getLeaderboard(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get leaderboard.")
+	ldbQuery, err := getLeaderboardQuery(echTx)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		response.Status = models.StatusError
+		response.Message = http.StatusText(http.StatusBadRequest)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	if err = echTx.Validate(ldbQuery); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	data, counter, err := ldb.LeaderboardUseCase.GetLeaderboard(echTx, ldbQuery)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.TotalCount = counter
+	requestLogger.Info("End of get leaderboard.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (ldb *LeaderboardsHandler) getRank(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get leaderboard rank.")
+	ldbQuery, err := getLeaderboardQuery(echTx)
+
+	if err != nil || ldbQuery.CIF == "" {
+		response.Status = models.StatusError
+		response.Message = models.ErrBadParamInput.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	if err = echTx.Validate(ldbQuery); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	data, err := ldb.LeaderboardUseCase.GetRank(echTx, ldbQuery)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = data
+	requestLogger.Info("End of get leaderboard rank.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func getLeaderboardQuery(echTx echo.Context) (*models.LeaderboardQuery, error) {
+	ldbQuery := &models.LeaderboardQuery{
+		Period:  echTx.QueryParam("period"),
+		Date:    echTx.QueryParam("date"),
+		Product: echTx.QueryParam("product"),
+		CIF:     echTx.QueryParam("CIF"),
+	}
+
+	campaignID, err := strconv.ParseInt("0"+echTx.QueryParam("campaignId"), 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ldbQuery.CampaignID = campaignID
+
+	if ldbQuery.Page, err = strconv.Atoi("0" + echTx.QueryParam("page")); err != nil {
+		return nil, err
+	}
+
+	if ldbQuery.Limit, err = strconv.Atoi("0" + echTx.QueryParam("limit")); err != nil {
+		return nil, err
+	}
+
+	return ldbQuery, nil
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	if strings.Contains(err.Error(), "400") {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case models.ErrLeaderboardPeriod, models.ErrLeaderboardCampaign, models.ErrLeaderboardDate:
+		return http.StatusBadRequest
+	case models.ErrNotFound, models.ErrLeaderboardRankNA:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,17 @@
+package leaderboards
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the leaderboards repository contract
+type Repository interface {
+	GetCampaignWindows(echo.Context, time.Time) ([]models.LeaderboardWindow, error)
+	Compute(echo.Context, models.LeaderboardWindow, time.Time) (int64, error)
+	GetEntries(echo.Context, *models.LeaderboardQuery, string) ([]models.LeaderboardEntry, error)
+	CountEntries(echo.Context, *models.LeaderboardQuery, string) (string, error)
+	GetRank(echo.Context, *models.LeaderboardQuery, string) (*models.LeaderboardEntry, error)
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"gade/srv-gade-point/leaderboards"
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+type psqlLeaderboardRepository struct {
+	Conn *sql.DB
+}
+
+// NewPsqlLeaderboardRepository will create an object that represent the leaderboards.Repository interface
+func NewPsqlLeaderboardRepository(Conn *sql.DB) leaderboards.Repository {
+	return &psqlLeaderboardRepository{Conn}
+}
+
+func (ldbRepo *psqlLeaderboardRepository) GetCampaignWindows(c echo.Context, now time.Time) ([]models.LeaderboardWindow, error) {
+	var windows []models.LeaderboardWindow
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// a campaign that ended since the previous day is computed once more to count its last points
+	query := `SELECT id, start_date, end_date FROM campaigns WHERE start_date <= $1 AND end_date >= $2`
+	rows, err := ldbRepo.Conn.Query(query, now, now.AddDate(0, 0, -1))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var campaignID int64
+		var startDate, endDate time.Time
+
+		if err = rows.Scan(&campaignID, &startDate, &endDate); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		windows = append(windows, models.GetCampaignLeaderboardWindow(campaignID, startDate, endDate))
+	}
+
+	return windows, nil
+}
+
+func (ldbRepo *psqlLeaderboardRepository) Compute(c echo.Context, window models.LeaderboardWindow, now time.Time) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	tx, err := ldbRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	query := `DELETE FROM leaderboard_entries WHERE period = $1 AND period_key = $2`
+
+	if _, err = tx.Exec(query, window.Period, window.Key); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	// the earned points of a calendar window are counted on the overall board (campaign 0) and on the board
+	// of their campaign, the product boards leave out the transactions without a product. A reversed reward
+	// only counts the ratio that is not reversed.
+	query = `INSERT INTO leaderboard_entries (period, period_key, campaign_id, product, cif, point_amount, rank, computed_at)
+		SELECT $1, $2, board.campaign_id, board.product, board.cif, board.point_amount,
+			RANK() OVER (PARTITION BY board.campaign_id, board.product ORDER BY board.point_amount DESC), $3
+		FROM (
+			SELECT earned.campaign_id, coalesce(earned.product, '') AS product, earned.cif,
+				sum(earned.point_amount) AS point_amount
+			FROM (
+				SELECT ph.cif, board_campaign.id AS campaign_id,
+					nullif(rt.request_data->'validators'->>'product', '') AS product,
+					floor(ph.point_amount * (1 - coalesce(rt.reversed_ratio, 0))) AS point_amount
+				FROM point_histories ph
+				JOIN rewards r ON r.id = ph.reward_id
+				LEFT JOIN reward_transactions rt ON rt.ref_id = ph.ref_id AND rt.reward_id = ph.reward_id
+				CROSS JOIN LATERAL unnest(CASE WHEN $4::INTEGER = 0 THEN ARRAY[0, r.campaign_id]
+					ELSE ARRAY[r.campaign_id] END) AS board_campaign(id)
+				WHERE ph.transaction_type = $5 AND ph.status = $6 AND ph.transaction_date >= $7
+				AND ph.transaction_date < $8 AND ($4::INTEGER = 0 OR r.campaign_id = $4::INTEGER)
+				AND board_campaign.id IS NOT NULL
+			) earned
+			GROUP BY GROUPING SETS ((earned.campaign_id, earned.cif), (earned.campaign_id, earned.product, earned.cif))
+			HAVING GROUPING(earned.product) = 1 OR earned.product IS NOT NULL
+		) board
+		WHERE board.point_amount > 0`
+	result, err := tx.Exec(query, window.Period, window.Key, &now, window.CampaignID, models.TransactionPointTypeDebet,
+		models.PointHistoryStatusSuccess, window.From, window.Until)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (ldbRepo *psqlLeaderboardRepository) GetEntries(c echo.Context, ldbQuery *models.LeaderboardQuery,
+	key string) ([]models.LeaderboardEntry, error) {
+	var result []models.LeaderboardEntry
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	args := []interface{}{ldbQuery.Period, key, ldbQuery.CampaignID, ldbQuery.Product}
+	paging := ""
+
+	if ldbQuery.Page > 0 && ldbQuery.Limit > 0 {
+		args = append(args, ldbQuery.Limit, (ldbQuery.Page-1)*ldbQuery.Limit)
+		paging = fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	query := `SELECT period, period_key, campaign_id, product, cif, point_amount, rank, computed_at
+		FROM leaderboard_entries WHERE period = $1 AND period_key = $2 AND campaign_id = $3 AND product = $4
+		ORDER BY rank, cif` + paging
+	rows, err := ldbRepo.Conn.Query(query, args...)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+
+		err = rows.Scan(&entry.Period, &entry.PeriodKey, &entry.CampaignID, &entry.Product, &entry.CIF,
+			&entry.PointAmount, &entry.Rank, &entry.ComputedAt)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+func (ldbRepo *psqlLeaderboardRepository) CountEntries(c echo.Context, ldbQuery *models.LeaderboardQuery,
+	key string) (string, error) {
+	var counter string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT COUNT(id) FROM leaderboard_entries
+		WHERE period = $1 AND period_key = $2 AND campaign_id = $3 AND product = $4`
+	err := ldbRepo.Conn.QueryRow(query, ldbQuery.Period, key, ldbQuery.CampaignID, ldbQuery.Product).Scan(&counter)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return "", err
+	}
+
+	return counter, nil
+}
+
+func (ldbRepo *psqlLeaderboardRepository) GetRank(c echo.Context, ldbQuery *models.LeaderboardQuery,
+	key string) (*models.LeaderboardEntry, error) {
+	entry := &models.LeaderboardEntry{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT le.period, le.period_key, le.campaign_id, le.product, le.cif, le.point_amount, le.rank,
+			le.computed_at, (SELECT COUNT(id) FROM leaderboard_entries WHERE period = le.period
+			AND period_key = le.period_key AND campaign_id = le.campaign_id AND product = le.product)
+		FROM leaderboard_entries le
+		WHERE le.period = $1 AND le.period_key = $2 AND le.campaign_id = $3 AND le.product = $4 AND le.cif = $5`
+	err := ldbRepo.Conn.QueryRow(query, ldbQuery.Period, key, ldbQuery.CampaignID, ldbQuery.Product,
+		ldbQuery.CIF).Scan(&entry.Period, &entry.PeriodKey, &entry.CampaignID, &entry.Product, &entry.CIF,
+		&entry.PointAmount, &entry.Rank, &entry.ComputedAt, &entry.Participants)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return entry, nil
+}
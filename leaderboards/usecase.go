@@ -0,0 +1,15 @@
+package leaderboards
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the leaderboards usecases
+type UseCase interface {
+	GetLeaderboard(echo.Context, *models.LeaderboardQuery) ([]models.LeaderboardEntry, string, error)
+	GetRank(echo.Context, *models.LeaderboardQuery) (*models.LeaderboardEntry, error)
+	Compute(time.Time) (int64, error)
+}
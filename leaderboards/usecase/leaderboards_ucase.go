@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"database/sql"
+	"gade/srv-gade-point/leaderboards"
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+)
+
+type leaderboardUseCase struct {
+	leaderboardRepo leaderboards.Repository
+}
+
+// NewLeaderboardUseCase will create new an leaderboardUseCase object representation of leaderboards.UseCase interface
+func NewLeaderboardUseCase(ldbRepo leaderboards.Repository) leaderboards.UseCase {
+	return &leaderboardUseCase{
+		leaderboardRepo: ldbRepo,
+	}
+}
+
+func (ldb *leaderboardUseCase) GetLeaderboard(c echo.Context, ldbQuery *models.LeaderboardQuery) ([]models.LeaderboardEntry, string, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	key, err := ldbQuery.GetKey(time.Now(), models.GetBusinessLocation())
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, "", err
+	}
+
+	counter, err := ldb.leaderboardRepo.CountEntries(c, ldbQuery, key)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetLeaderboard)
+
+		return nil, "", models.ErrGetLeaderboard
+	}
+
+	data, err := ldb.leaderboardRepo.GetEntries(c, ldbQuery, key)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetLeaderboard)
+
+		return nil, "", models.ErrGetLeaderboard
+	}
+
+	return data, counter, nil
+}
+
+func (ldb *leaderboardUseCase) GetRank(c echo.Context, ldbQuery *models.LeaderboardQuery) (*models.LeaderboardEntry, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	key, err := ldbQuery.GetKey(time.Now(), models.GetBusinessLocation())
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	entry, err := ldb.leaderboardRepo.GetRank(c, ldbQuery, key)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrLeaderboardRankNA)
+
+		return nil, models.ErrLeaderboardRankNA
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetLeaderboard)
+
+		return nil, models.ErrGetLeaderboard
+	}
+
+	return entry, nil
+}
+
+func (ldb *leaderboardUseCase) Compute(now time.Time) (int64, error) {
+	var computed int64
+	windows := models.GetLeaderboardWindows(now, models.GetBusinessLocation())
+	campaignWindows, err := ldb.leaderboardRepo.GetCampaignWindows(nil, now)
+
+	if err != nil {
+		logrus.Debug("Compute Leaderboards: ", err)
+
+		return 0, models.ErrComputeLeaderboard
+	}
+
+	// a failing window keeps its previous entries until the next run
+	for _, window := range append(windows, campaignWindows...) {
+		counter, err := ldb.leaderboardRepo.Compute(nil, window, now)
+
+		if err != nil {
+			logrus.Debug("Compute Leaderboard "+window.Period+" "+window.Key+": ", err)
+
+			continue
+		}
+
+		computed += counter
+	}
+
+	return computed, nil
+}
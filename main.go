@@ -4,8 +4,18 @@ import (
 	"database/sql"
 	"fmt"
 	"gade/srv-gade-point/campaigns"
+	"gade/srv-gade-point/leaderboards"
+	_leaderboardHttpDelivery "gade/srv-gade-point/leaderboards/delivery/http"
+	_leaderboardRepository "gade/srv-gade-point/leaderboards/repository"
+	_leaderboardUseCase "gade/srv-gade-point/leaderboards/usecase"
+	"gade/srv-gade-point/membershiptiers"
+	_tierHttpDelivery "gade/srv-gade-point/membershiptiers/delivery/http"
+	_tierRepository "gade/srv-gade-point/membershiptiers/repository"
+	_tierUseCase "gade/srv-gade-point/membershiptiers/usecase"
 	"gade/srv-gade-point/middleware"
 	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointhistories"
+	"gade/srv-gade-point/rewardtrxs"
 	"gade/srv-gade-point/vouchers"
 	"net/http"
 	"os"
@@ -20,16 +30,29 @@ import (
 	_campaignTrxHttpDelivery "gade/srv-gade-point/campaigntrxs/delivery/http"
 	_campaignTrxRepository "gade/srv-gade-point/campaigntrxs/repository"
 	_campaignTrxUseCase "gade/srv-gade-point/campaigntrxs/usecase"
+	_goldPriceRepository "gade/srv-gade-point/goldprices/repository"
+	_goldPriceUseCase "gade/srv-gade-point/goldprices/usecase"
 	_metricRepository "gade/srv-gade-point/metrics/repository"
 	_metricUseCase "gade/srv-gade-point/metrics/usecase"
+	_pAdjustmentHttpDelivery "gade/srv-gade-point/pointadjustments/delivery/http"
+	_pAdjustmentRepository "gade/srv-gade-point/pointadjustments/repository"
+	_pAdjustmentUseCase "gade/srv-gade-point/pointadjustments/usecase"
 	_pHistoryHttpDelivery "gade/srv-gade-point/pointhistories/delivery/http"
 	_pHistoryRepository "gade/srv-gade-point/pointhistories/repository"
 	_pHistoryUseCase "gade/srv-gade-point/pointhistories/usecase"
+	_pTransferHttpDelivery "gade/srv-gade-point/pointtransfers/delivery/http"
+	_pTransferRepository "gade/srv-gade-point/pointtransfers/repository"
+	_pTransferUseCase "gade/srv-gade-point/pointtransfers/usecase"
 	_quotaRepository "gade/srv-gade-point/quotas/repository"
 	_quotaUseCase "gade/srv-gade-point/quotas/usecase"
 	_rewardHttpDelivery "gade/srv-gade-point/rewards/delivery/http"
 	_rewardRepository "gade/srv-gade-point/rewards/repository"
 	_rewardUseCase "gade/srv-gade-point/rewards/usecase"
+	_rewardTrxRepository "gade/srv-gade-point/rewardtrxs/repository"
+	_rewardTrxUseCase "gade/srv-gade-point/rewardtrxs/usecase"
+	_segmentHttpDelivery "gade/srv-gade-point/segments/delivery/http"
+	_segmentRepository "gade/srv-gade-point/segments/repository"
+	_segmentUseCase "gade/srv-gade-point/segments/usecase"
 	_metricService "gade/srv-gade-point/services"
 	_tagRepository "gade/srv-gade-point/tags/repository"
 	_tagUseCase "gade/srv-gade-point/tags/usecase"
@@ -58,6 +81,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultSweepInterval       = 5  // in minutes
+	defaultRewardTrxTimeout    = 30 // in minutes
+	defaultLeaderboardInterval = 60 // in minutes
+)
+
 var ech *echo.Echo
 var metricService services.MetricService
 
@@ -124,16 +153,42 @@ func main() {
 	pHistoryUseCase := _pHistoryUseCase.NewPointHistoryUseCase(pHistoryRepository)
 	_pHistoryHttpDelivery.NewPointHistoriesHandler(echoGroup, pHistoryUseCase)
 
+	// SEGMENT
+	segmentRepository := _segmentRepository.NewPsqlSegmentRepository(dbConn)
+	segmentUseCase := _segmentUseCase.NewSegmentUseCase(segmentRepository)
+	_segmentHttpDelivery.NewSegmentsHandler(echoGroup, segmentUseCase)
+
+	// MEMBERSHIP TIER
+	tierRepository := _tierRepository.NewPsqlMembershipTierRepository(dbConn)
+	tierUseCase := _tierUseCase.NewMembershipTierUseCase(tierRepository)
+	_tierHttpDelivery.NewMembershipTiersHandler(echoGroup, tierUseCase)
+
 	// VOUCHER
-	voucherRepository := _voucherRepository.NewPsqlVoucherRepository(dbConn)
+	voucherRepository := _voucherRepository.NewPsqlVoucherRepository(dbConn, pHistoryRepository)
+
+	// REWARDTRX
+	rewardTrxRepository := _rewardTrxRepository.NewPsqlRewardTrxRepository(dbConn, pHistoryRepository, voucherRepository,
+		quotaRepository)
+	rewardTrxUseCase := _rewardTrxUseCase.NewRewardtrxUseCase(rewardTrxRepository, tierUseCase)
+
+	// GOLDPRICE
+	goldPriceRepository := _goldPriceRepository.NewPsqlGoldPriceRepository(dbConn)
+
+	if os.Getenv(`GOLD_PRICE_SOURCE`) == models.GoldPriceSourceFile {
+		goldPriceRepository = _goldPriceRepository.NewFileGoldPriceRepository(os.Getenv(`GOLD_PRICE_FILE`))
+	}
+
+	goldPriceUseCase := _goldPriceUseCase.NewGoldPriceUseCase(goldPriceRepository)
 
 	// REWARD
 	rewardRepository := _rewardRepository.NewPsqlRewardRepository(dbConn)
 	campaignRepository := _campaignRepository.NewPsqlCampaignRepository(dbConn, rewardRepository)
-	voucherUseCase := _voucherUseCase.NewVoucherUseCase(voucherRepository, campaignRepository, pHistoryRepository)
+	voucherUseCase := _voucherUseCase.NewVoucherUseCase(voucherRepository, campaignRepository, pHistoryRepository, segmentUseCase,
+		tierUseCase)
 	_voucherHttpDelivery.NewVouchersHandler(echoGroup, voucherUseCase)
-	rewardUseCase := _rewardUseCase.NewRewardUseCase(rewardRepository, campaignRepository, tagUseCase, quotaUseCase, voucherUseCase)
-	_rewardHttpDelivery.NewRewardHandler(echoGroup, rewardUseCase)
+	rewardUseCase := _rewardUseCase.NewRewardUseCase(rewardRepository, campaignRepository, tagUseCase, quotaUseCase, voucherUseCase, rewardTrxUseCase,
+		goldPriceUseCase, segmentUseCase, tierUseCase)
+	_rewardHttpDelivery.NewRewardHandler(echoGroup, rewardUseCase, rewardTrxUseCase)
 
 	// CAMPAIGN
 	campaignUseCase := _campaignUseCase.NewCampaignUseCase(campaignRepository, rewardUseCase)
@@ -154,6 +209,21 @@ func main() {
 	userUseCase := _userUseCase.NewUserUseCase(userRepository, timeoutContext)
 	_userHttpDelivery.NewUserHandler(echoGroup, userUseCase)
 
+	// POINT ADJUSTMENT
+	pAdjustmentRepository := _pAdjustmentRepository.NewPsqlPointAdjustmentRepository(dbConn, pHistoryRepository)
+	pAdjustmentUseCase := _pAdjustmentUseCase.NewPointAdjustmentUseCase(pAdjustmentRepository, userRepository)
+	_pAdjustmentHttpDelivery.NewPointAdjustmentsHandler(echoGroup, pAdjustmentUseCase)
+
+	// POINT TRANSFER
+	pTransferRepository := _pTransferRepository.NewPsqlPointTransferRepository(dbConn, pHistoryRepository)
+	pTransferUseCase := _pTransferUseCase.NewPointTransferUseCase(pTransferRepository, userRepository)
+	_pTransferHttpDelivery.NewPointTransfersHandler(echoGroup, pTransferUseCase)
+
+	// LEADERBOARD
+	leaderboardRepository := _leaderboardRepository.NewPsqlLeaderboardRepository(dbConn)
+	leaderboardUseCase := _leaderboardUseCase.NewLeaderboardUseCase(leaderboardRepository)
+	_leaderboardHttpDelivery.NewLeaderboardsHandler(echoGroup, leaderboardUseCase)
+
 	// METRIC
 	metricRepository := _metricRepository.NewPsqlMetricRepository(dbConn)
 	metricUseCase := _metricUseCase.NewMetricUseCase(metricRepository, timeoutContext)
@@ -164,6 +234,18 @@ func main() {
 	// Run every day.
 	updateStatusBasedOnStartDate(campaignUseCase, voucherUseCase)
 
+	// Run every interval.
+	updateRewardTrxTimeout(rewardTrxUseCase)
+
+	// Run every day at the point expiry time.
+	expirePoints(pHistoryUseCase)
+
+	// Run every leaderboard interval.
+	computeLeaderboards(leaderboardUseCase)
+
+	// Run every day at the membership tier time.
+	evaluateMembershipTiers(tierUseCase)
+
 	ech.Start(":" + os.Getenv(`PORT`))
 
 }
@@ -181,6 +263,92 @@ func updateStatusBasedOnStartDate(cmp campaigns.UseCase, vcr vouchers.UseCase) {
 	})
 }
 
+func updateRewardTrxTimeout(rwdTrx rewardtrxs.UseCase) {
+	interval, err := strconv.Atoi(os.Getenv(`REWARD_TRX_SWEEP_INTERVAL`))
+
+	if err != nil || interval <= 0 {
+		interval = defaultSweepInterval
+	}
+
+	ttl, err := strconv.Atoi(os.Getenv(`REWARD_TRX_TIMEOUT`))
+
+	if err != nil || ttl <= 0 {
+		ttl = defaultRewardTrxTimeout
+	}
+
+	scheduler.Every(interval).Minutes().NotImmediately().Run(func() {
+		logrus.Debug("Run Reward Transaction Sweeper! @", time.Now())
+		counter, err := rwdTrx.UpdateTimeout(time.Duration(ttl) * time.Minute)
+
+		if err != nil {
+			return
+		}
+
+		logrus.Debug("Reward transactions swept: ", counter)
+
+		if counter > 0 {
+			_metricService.AddMetricCounter("reward_trx_timeout", counter)
+		}
+	})
+}
+
+func expirePoints(pHistory pointhistories.UseCase) {
+	scheduler.Every().Day().At(os.Getenv(`POINT_EXPIRY_TIME`)).Run(func() {
+		logrus.Debug("Run Point Expiry! @", time.Now())
+		counter, err := pHistory.ExpirePoints(time.Now())
+
+		if err != nil {
+			return
+		}
+
+		logrus.Debug("Points expired: ", counter)
+
+		if counter > 0 {
+			_metricService.AddMetricCounter("point_expired", counter)
+		}
+	})
+}
+
+func computeLeaderboards(leaderboard leaderboards.UseCase) {
+	interval, err := strconv.Atoi(os.Getenv(`LEADERBOARD_INTERVAL`))
+
+	if err != nil || interval <= 0 {
+		interval = defaultLeaderboardInterval
+	}
+
+	scheduler.Every(interval).Minutes().Run(func() {
+		logrus.Debug("Run Leaderboard Computation! @", time.Now())
+		counter, err := leaderboard.Compute(time.Now())
+
+		if err != nil {
+			return
+		}
+
+		logrus.Debug("Leaderboard entries computed: ", counter)
+
+		if counter > 0 {
+			_metricService.AddMetricCounter("leaderboard_computed", counter)
+		}
+	})
+}
+
+func evaluateMembershipTiers(tier membershiptiers.UseCase) {
+	scheduler.Every().Day().At(os.Getenv(`MEMBERSHIP_TIER_TIME`)).Run(func() {
+		logrus.Debug("Run Membership Tier Evaluation! @", time.Now())
+		counter, err := tier.EvaluateDowngrades(time.Now())
+
+		if err != nil {
+			return
+		}
+
+		logrus.Debug("Membership tiers evaluated: ", counter)
+
+		if counter > 0 {
+			_metricService.AddMetricCounter("membership_tier_evaluated", counter)
+		}
+	})
+}
+
 func ping(echTx echo.Context) error {
 	res := echTx.Response()
 	rid := res.Header().Get(echo.HeaderXRequestID)
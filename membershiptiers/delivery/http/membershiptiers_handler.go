@@ -0,0 +1,192 @@
+package http
+
+import (
+	"gade/srv-gade-point/membershiptiers"
+	"gade/srv-gade-point/models"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+var response models.Response
+
+// MembershipTiersHandler represent the httphandler for membership tiers
+type MembershipTiersHandler struct {
+	MembershipTierUseCase membershiptiers.UseCase
+}
+
+// NewMembershipTiersHandler represent to register membership tiers endpoint
+func NewMembershipTiersHandler(echoGroup models.EchoGroup, tierUs membershiptiers.UseCase) {
+	handler := &MembershipTiersHandler{
+		MembershipTierUseCase: tierUs,
+	}
+
+	// End Point For CMS
+	echoGroup.Admin.POST("/membership/tiers", handler.createTier)
+	echoGroup.Admin.GET("/membership/tiers", handler.getTiers)
+	echoGroup.Admin.PUT("/membership/tiers/:id", handler.updateTier)
+
+	// End Point For External
+	echoGroup.API.GET("/membership/tier", handler.getCustomerTier)
+}
+
+func (tier *MembershipTiersHandler) createTier(echTx echo.Context) error {
+	var membershipTier models.MembershipTier
+	response = models.Response{}
+	err := echTx.Bind(&membershipTier)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(membershipTier); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, membershipTier)
+	requestLogger.Info("Start to create a membership tier.")
+
+	if err = tier.MembershipTierUseCase.Create(echTx, &membershipTier); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageSaveSuccess
+	response.Data = membershipTier
+	requestLogger.Info("End of create a membership tier.")
+
+	return echTx.JSON(http.StatusCreated, response)
+}
+
+func (tier *MembershipTiersHandler) getTiers(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get membership tiers.")
+	data, err := tier.MembershipTierUseCase.GetTiers(echTx)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	requestLogger.Info("End of get membership tiers.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (tier *MembershipTiersHandler) updateTier(echTx echo.Context) error {
+	var membershipTier models.MembershipTier
+	response = models.Response{}
+	err := echTx.Bind(&membershipTier)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(membershipTier); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, membershipTier)
+	requestLogger.Info("Start to update a membership tier.")
+
+	if err = tier.MembershipTierUseCase.Update(echTx, echTx.Param("id"), &membershipTier); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = membershipTier
+	requestLogger.Info("End of update a membership tier.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (tier *MembershipTiersHandler) getCustomerTier(echTx echo.Context) error {
+	response = models.Response{}
+	CIF := echTx.QueryParam("CIF")
+	payload := map[string]interface{}{
+		"CIF": CIF,
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, payload)
+	requestLogger.Info("Start to get customer membership tier.")
+
+	if CIF == "" {
+		response.Status = models.StatusError
+		response.Message = models.ErrBadParamInput.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	data, err := tier.MembershipTierUseCase.GetCustomerTier(echTx, CIF)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = data
+	requestLogger.Info("End of get customer membership tier.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	if strings.Contains(err.Error(), "400") {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case models.ErrNotFound, models.ErrNoMembershipTier:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
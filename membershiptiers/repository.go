@@ -0,0 +1,19 @@
+package membershiptiers
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the membership tiers repository contract
+type Repository interface {
+	Create(echo.Context, *models.MembershipTier) error
+	Update(echo.Context, *models.MembershipTier) error
+	GetTiers(echo.Context) ([]models.MembershipTier, error)
+	GetCustomerTier(echo.Context, string) (*models.CustomerTier, error)
+	GetRollingAmount(echo.Context, string, string, time.Time, time.Time) (float64, error)
+	SaveCustomerTier(echo.Context, *models.CustomerTier) error
+	GetDowngradedCIFs(echo.Context, time.Time) ([]string, error)
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"database/sql"
+	"gade/srv-gade-point/membershiptiers"
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+type psqlMembershipTierRepository struct {
+	Conn *sql.DB
+}
+
+// NewPsqlMembershipTierRepository will create an object that represent the membershiptiers.Repository interface
+func NewPsqlMembershipTierRepository(Conn *sql.DB) membershiptiers.Repository {
+	return &psqlMembershipTierRepository{Conn}
+}
+
+func (tierRepo *psqlMembershipTierRepository) Create(c echo.Context, tier *models.MembershipTier) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `INSERT INTO membership_tiers (code, name, min_amount, multiplier, created_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := tierRepo.Conn.QueryRow(query, tier.Code, tier.Name, tier.MinAmount, tier.GetMultiplier(),
+		&now).Scan(&tier.ID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	multiplier := tier.GetMultiplier()
+	tier.Multiplier = &multiplier
+	tier.CreatedAt = &now
+
+	return nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) Update(c echo.Context, tier *models.MembershipTier) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE membership_tiers SET code = $1, name = $2, min_amount = $3, multiplier = $4, updated_at = $5
+		WHERE id = $6 RETURNING created_at`
+	err := tierRepo.Conn.QueryRow(query, tier.Code, tier.Name, tier.MinAmount, tier.GetMultiplier(), &now,
+		tier.ID).Scan(&tier.CreatedAt)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	multiplier := tier.GetMultiplier()
+	tier.Multiplier = &multiplier
+	tier.UpdatedAt = &now
+
+	return nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) GetTiers(c echo.Context) ([]models.MembershipTier, error) {
+	var result []models.MembershipTier
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT id, code, name, min_amount, multiplier, updated_at, created_at
+		FROM membership_tiers ORDER BY min_amount, id`
+	rows, err := tierRepo.Conn.Query(query)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var tier models.MembershipTier
+
+		err = rows.Scan(&tier.ID, &tier.Code, &tier.Name, &tier.MinAmount, &tier.Multiplier, &tier.UpdatedAt,
+			&tier.CreatedAt)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		result = append(result, tier)
+	}
+
+	return result, nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) GetCustomerTier(c echo.Context, CIF string) (*models.CustomerTier, error) {
+	var tierID sql.NullInt64
+	customerTier := &models.CustomerTier{CIF: CIF}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT tier_id, amount, qualified_at, downgrade_date, evaluated_at FROM customer_tiers WHERE cif = $1`
+	err := tierRepo.Conn.QueryRow(query, CIF).Scan(&tierID, &customerTier.Amount, &customerTier.QualifiedAt,
+		&customerTier.DowngradeDate, &customerTier.EvaluatedAt)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	if tierID.Valid {
+		customerTier.Tier = &models.MembershipTier{ID: tierID.Int64}
+	}
+
+	return customerTier, nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) GetRollingAmount(c echo.Context, CIF, basis string, from,
+	until time.Time) (float64, error) {
+	var amount float64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// only the earned points count to a tier, a reversed reward counts the ratio that is not reversed
+	query := `SELECT coalesce(sum(floor(ph.point_amount * (1 - coalesce(rt.reversed_ratio, 0)))), 0)
+		FROM point_histories ph
+		LEFT JOIN reward_transactions rt ON rt.ref_id = ph.ref_id AND rt.reward_id = ph.reward_id
+		WHERE ph.cif = $1 AND ph.reward_id IS NOT NULL AND ph.transaction_type = $2 AND ph.status = $3
+		AND ph.transaction_date >= $4 AND ph.transaction_date < $5`
+	args := []interface{}{CIF, models.TransactionPointTypeDebet, models.PointHistoryStatusSuccess, from, until}
+
+	if basis == models.MembershipTierBasisTransactionAmount {
+		// a transaction could have many rewards, so it is counted once by its ref id
+		query = `SELECT coalesce(sum(amount), 0) FROM (
+			SELECT DISTINCT ON (ref_id) ref_id, coalesce((request_data->>'transactionAmount')::NUMERIC, 0) AS amount
+			FROM reward_transactions WHERE cif = $1 AND status = $2 AND transaction_date >= $3
+			AND transaction_date < $4) AS trx`
+		args = []interface{}{CIF, models.RewardTrxSucceeded, from, until}
+	}
+
+	if err := tierRepo.Conn.QueryRow(query, args...).Scan(&amount); err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return amount, nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) SaveCustomerTier(c echo.Context, customerTier *models.CustomerTier) error {
+	var tierID *int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if customerTier.Tier != nil {
+		tierID = &customerTier.Tier.ID
+	}
+
+	query := `INSERT INTO customer_tiers (cif, tier_id, amount, qualified_at, downgrade_date, evaluated_at)
+		VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (cif) DO UPDATE SET tier_id = EXCLUDED.tier_id,
+		amount = EXCLUDED.amount, qualified_at = EXCLUDED.qualified_at, downgrade_date = EXCLUDED.downgrade_date,
+		evaluated_at = EXCLUDED.evaluated_at`
+	_, err := tierRepo.Conn.Exec(query, customerTier.CIF, tierID, customerTier.Amount, customerTier.QualifiedAt,
+		customerTier.DowngradeDate, customerTier.EvaluatedAt)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (tierRepo *psqlMembershipTierRepository) GetDowngradedCIFs(c echo.Context, now time.Time) ([]string, error) {
+	var CIFs []string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT cif FROM customer_tiers WHERE downgrade_date <= $1`
+	rows, err := tierRepo.Conn.Query(query, now)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var CIF string
+
+		if err = rows.Scan(&CIF); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		CIFs = append(CIFs, CIF)
+	}
+
+	return CIFs, nil
+}
@@ -0,0 +1,20 @@
+package membershiptiers
+
+import (
+	"gade/srv-gade-point/models"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the membership tiers usecases
+type UseCase interface {
+	Create(echo.Context, *models.MembershipTier) error
+	Update(echo.Context, string, *models.MembershipTier) error
+	GetTiers(echo.Context) ([]models.MembershipTier, error)
+	GetCustomerTier(echo.Context, string) (*models.CustomerTier, error)
+	Evaluate(echo.Context, string) (*models.CustomerTier, error)
+	EvaluateDowngrades(time.Time) (int64, error)
+	GetMultiplier(echo.Context, string) float64
+	CheckMembershipTiers(echo.Context, *models.Validator, string) error
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"gade/srv-gade-point/membershiptiers"
+	"gade/srv-gade-point/models"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+)
+
+type membershipTierUseCase struct {
+	tierRepo membershiptiers.Repository
+}
+
+// NewMembershipTierUseCase will create new an membershipTierUseCase object representation of membershiptiers.UseCase interface
+func NewMembershipTierUseCase(tierRepo membershiptiers.Repository) membershiptiers.UseCase {
+	return &membershipTierUseCase{
+		tierRepo: tierRepo,
+	}
+}
+
+func (tier *membershipTierUseCase) Create(c echo.Context, membershipTier *models.MembershipTier) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if err := tier.tierRepo.Create(c, membershipTier); err != nil {
+		requestLogger.Debug(models.ErrMembershipTierFailed)
+
+		return models.ErrMembershipTierFailed
+	}
+
+	return nil
+}
+
+func (tier *membershipTierUseCase) Update(c echo.Context, id string, membershipTier *models.MembershipTier) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	tierID, err := strconv.ParseInt(id, 10, 64)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return errors.New("Something went wrong with input ID")
+	}
+
+	membershipTier.ID = tierID
+	err = tier.tierRepo.Update(c, membershipTier)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrNoMembershipTier)
+
+		return models.ErrNoMembershipTier
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrMembershipTierFailed)
+
+		return models.ErrMembershipTierFailed
+	}
+
+	return nil
+}
+
+func (tier *membershipTierUseCase) GetTiers(c echo.Context) ([]models.MembershipTier, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	tiers, err := tier.tierRepo.GetTiers(c)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetMembershipTier)
+
+		return nil, models.ErrGetMembershipTier
+	}
+
+	return tiers, nil
+}
+
+func (tier *membershipTierUseCase) GetCustomerTier(c echo.Context, CIF string) (*models.CustomerTier, error) {
+	// a read only computes the tier, it is stored by the scheduled job and when the points are credited
+	return tier.computeCustomerTier(c, CIF, time.Now())
+}
+
+func (tier *membershipTierUseCase) Evaluate(c echo.Context, CIF string) (*models.CustomerTier, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	customerTier, err := tier.computeCustomerTier(c, CIF, time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tier.tierRepo.SaveCustomerTier(c, customerTier); err != nil {
+		requestLogger.Debug(models.ErrEvaluateMembershipTier)
+
+		return nil, models.ErrEvaluateMembershipTier
+	}
+
+	return customerTier, nil
+}
+
+// computeCustomerTier to recompute the customer tier from its stored one and the rolling amount without storing it
+func (tier *membershipTierUseCase) computeCustomerTier(c echo.Context, CIF string, now time.Time) (*models.CustomerTier, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	tiers, err := tier.GetTiers(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	customerTier, err := tier.tierRepo.GetCustomerTier(c, CIF)
+
+	// a customer that has never been evaluated starts without any tier
+	if err == sql.ErrNoRows {
+		customerTier, err = &models.CustomerTier{CIF: CIF}, nil
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetMembershipTier)
+
+		return nil, models.ErrGetMembershipTier
+	}
+
+	customerTier.Basis = models.GetMembershipTierBasis()
+	from, until := models.GetMembershipTierWindow(now)
+	amount, err := tier.tierRepo.GetRollingAmount(c, CIF, customerTier.Basis, from, until)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetMembershipTier)
+
+		return nil, models.ErrGetMembershipTier
+	}
+
+	customerTier.Evaluate(tiers, amount, now)
+
+	return customerTier, nil
+}
+
+func (tier *membershipTierUseCase) EvaluateDowngrades(now time.Time) (int64, error) {
+	var evaluated int64
+	CIFs, err := tier.tierRepo.GetDowngradedCIFs(nil, now)
+
+	if err != nil {
+		logrus.Debug("Evaluate Membership Tiers: ", err)
+
+		return 0, err
+	}
+
+	// the upgrades are taken when the points are credited, so only the tiers that are due are evaluated
+	for _, CIF := range CIFs {
+		if _, err = tier.Evaluate(nil, CIF); err != nil {
+			logrus.Debug("Evaluate Membership Tier of "+CIF+": ", err)
+
+			continue
+		}
+
+		evaluated++
+	}
+
+	return evaluated, nil
+}
+
+func (tier *membershipTierUseCase) GetMultiplier(c echo.Context, CIF string) float64 {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	customerTier, err := tier.GetCustomerTier(c, CIF)
+
+	// a customer tier that is not available should not fail the reward, the points are given as is
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 1
+	}
+
+	return customerTier.Tier.GetMultiplier()
+}
+
+func (tier *membershipTierUseCase) CheckMembershipTiers(c echo.Context, validator *models.Validator, CIF string) error {
+	if validator == nil || len(validator.MembershipTiers) == 0 {
+		return nil
+	}
+
+	customerTier, err := tier.GetCustomerTier(c, CIF)
+
+	if err != nil {
+		return err
+	}
+
+	if !validator.IsMembershipTierTargeted(customerTier.Tier) {
+		return models.ErrMembershipTierNotTargeted
+	}
+
+	return nil
+}
@@ -3,6 +3,6 @@ package metrics
 // Repository represent the metric's repository contract
 type Repository interface {
 	FindMetric(string) (string, error)
-	CreateMetric(string) error
-	UpdateMetric(string) error
+	CreateMetric(string, int64) error
+	UpdateMetric(string, int64) error
 }
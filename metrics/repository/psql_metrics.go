@@ -34,9 +34,8 @@ func (m *psqlMetricRepository) FindMetric(job string) (string, error) {
 	return lastID, err
 }
 
-func (m *psqlMetricRepository) CreateMetric(job string) error {
+func (m *psqlMetricRepository) CreateMetric(job string, counter int64) error {
 	var lastID int64
-	counter := int8(1)
 	status := int8(0)
 	now := time.Now()
 
@@ -56,17 +55,17 @@ func (m *psqlMetricRepository) CreateMetric(job string) error {
 	return nil
 }
 
-func (m *psqlMetricRepository) UpdateMetric(job string) error {
+func (m *psqlMetricRepository) UpdateMetric(job string, counter int64) error {
 	var lastID int64
 
-	query := `UPDATE metrics SET counter = counter + 1 WHERE job = $1 RETURNING id`
+	query := `UPDATE metrics SET counter = counter + $1 WHERE job = $2 RETURNING id`
 	stmt, err := m.Conn.Prepare(query)
 
 	if err != nil {
 		return err
 	}
 
-	err = stmt.QueryRow(&job).Scan(&lastID)
+	err = stmt.QueryRow(&counter, &job).Scan(&lastID)
 
 	if err != nil {
 		return err
@@ -3,4 +3,5 @@ package metrics
 // UseCase represent the metric's usecases
 type UseCase interface {
 	AddMetric(string) error
+	AddMetricCounter(string, int64) error
 }
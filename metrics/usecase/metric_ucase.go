@@ -20,17 +20,20 @@ func NewMetricUseCase(met metrics.Repository, timeout time.Duration) metrics.Use
 }
 
 func (met *metricUseCase) AddMetric(job string) error {
+	return met.AddMetricCounter(job, 1)
+}
 
+func (met *metricUseCase) AddMetricCounter(job string, counter int64) error {
 	data, err := met.metricRepo.FindMetric(job)
 
 	if data == "" {
-		err = met.metricRepo.CreateMetric(job)
+		err = met.metricRepo.CreateMetric(job, counter)
 
 		if err != nil {
 			return models.ErrCreateMetric
 		}
 	} else {
-		err = met.metricRepo.UpdateMetric(job)
+		err = met.metricRepo.UpdateMetric(job, counter)
 
 		if err != nil {
 			return models.ErrUpdateMetric
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"fmt"
 	"gade/srv-gade-point/models"
 	"os"
+	"reflect"
+	"strings"
 
 	"gopkg.in/go-playground/validator.v9"
 
@@ -15,7 +18,51 @@ type customValidator struct {
 }
 
 func (cv *customValidator) Validate(i interface{}) error {
-	return cv.validator.Struct(i)
+	err := cv.validator.Struct(i)
+	fieldErrs, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return err
+	}
+
+	var validationErrs models.ValidationErrors
+
+	for _, fieldErr := range fieldErrs {
+		// the namespace is started by the struct name, e.g. PayloadValidator.validators.channel
+		field := fieldErr.Namespace()[strings.Index(fieldErr.Namespace(), ".")+1:]
+		expected := fieldErr.Param()
+
+		if expected == "" {
+			expected = fieldErr.Tag()
+		}
+
+		validationErrs = append(validationErrs, models.ValidationError{
+			Code:     models.ValidationCodeFieldPrefix + strings.ToUpper(fieldErr.Tag()),
+			Field:    field,
+			Message:  fmt.Sprintf("%s is not valid on the %s validation", field, fieldErr.Tag()),
+			Expected: expected,
+			Actual:   fieldErr.Value(),
+		})
+	}
+
+	return validationErrs
+}
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	// report the request fields by their json name
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+
+		if name == "" || name == "-" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	return validate
 }
 
 type customMiddleware struct {
@@ -39,7 +86,7 @@ func InitMiddleware(ech *echo.Echo, echoGroup models.EchoGroup) {
 	cm.cors()
 	cm.basicAuth()
 	cm.jwtAuth()
-	ech.Validator = &customValidator{validator: validator.New()}
+	ech.Validator = &customValidator{validator: newValidator()}
 }
 
 func (cm customMiddleware) cors() {
@@ -63,11 +110,13 @@ func (cm customMiddleware) jwtAuth() {
 	echGroup.Admin.Use(middleware.JWTWithConfig(middleware.JWTConfig{
 		SigningMethod: "HS512",
 		SigningKey:    []byte(os.Getenv(`JWT_SECRET`)),
+		Claims:        &models.Token{},
 	}))
 
 	echGroup.API.Use(middleware.JWTWithConfig(middleware.JWTConfig{
 		SigningMethod: "HS512",
 		SigningKey:    []byte(os.Getenv(`JWT_SECRET`)),
+		Claims:        &models.Token{},
 	}))
 
 }
@@ -0,0 +1,4 @@
+DROP INDEX reward_transactions_ref_id_reward_id_key;
+
+ALTER TABLE reward_transactions
+DROP COLUMN response_data;
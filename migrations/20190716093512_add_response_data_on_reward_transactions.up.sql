@@ -0,0 +1,4 @@
+ALTER TABLE reward_transactions
+ADD COLUMN response_data JSONB;
+
+CREATE UNIQUE INDEX reward_transactions_ref_id_reward_id_key ON reward_transactions (ref_id, reward_id);
@@ -0,0 +1,6 @@
+DROP INDEX index_point_histories_ref_id;
+
+DROP INDEX index_voucher_codes_ref_id;
+
+ALTER TABLE voucher_codes
+DROP COLUMN ref_id;
@@ -0,0 +1,6 @@
+ALTER TABLE voucher_codes
+ADD COLUMN ref_id VARCHAR(50);
+
+CREATE INDEX index_voucher_codes_ref_id ON voucher_codes (ref_id);
+
+CREATE INDEX index_point_histories_ref_id ON point_histories (ref_id);
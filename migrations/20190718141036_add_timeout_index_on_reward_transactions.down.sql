@@ -0,0 +1 @@
+DROP INDEX index_reward_transactions_inquired;
@@ -0,0 +1,3 @@
+-- for status        3 --> Timeout
+
+CREATE INDEX index_reward_transactions_inquired ON reward_transactions (status, inquired_date);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS quota_usages;
+
+ALTER TABLE quotas
+DROP COLUMN period_type;
@@ -0,0 +1,20 @@
+-- for period_type   0 --> rolling window
+--                   1 --> calendar window
+
+ALTER TABLE quotas
+ADD COLUMN period_type SMALLINT NOT NULL DEFAULT 0;
+
+-- Table: quota_usages
+
+CREATE TABLE IF NOT EXISTS quota_usages (
+    id SERIAL PRIMARY KEY NOT NULL,
+    quota_id INTEGER NOT NULL REFERENCES quotas(id) ON DELETE CASCADE,
+    cif VARCHAR(50) NOT NULL,
+    ref_id VARCHAR(50) NOT NULL,
+    used_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_quota_usages ON quota_usages (quota_id, used_date, cif);
+
+CREATE INDEX index_quota_usages_ref_id ON quota_usages (ref_id);
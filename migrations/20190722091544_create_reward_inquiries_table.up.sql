@@ -0,0 +1,15 @@
+-- Table: reward_inquiries
+-- response_data is null while the inquiry is still being processed
+
+CREATE TABLE IF NOT EXISTS reward_inquiries (
+    id SERIAL PRIMARY KEY NOT NULL,
+    ref_channel VARCHAR(50) NOT NULL,
+    cif VARCHAR(50) NOT NULL,
+    ref_id VARCHAR(50),
+    request_hash VARCHAR(64) NOT NULL,
+    response_data JSONB,
+    created_at TIMESTAMP DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX reward_inquiries_ref_channel_cif_key ON reward_inquiries (ref_channel, cif);
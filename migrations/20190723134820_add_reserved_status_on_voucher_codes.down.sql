@@ -0,0 +1,3 @@
+UPDATE voucher_codes SET status = 0, user_id = NULL, ref_id = NULL WHERE status = 4;
+
+DROP INDEX index_voucher_codes_status;
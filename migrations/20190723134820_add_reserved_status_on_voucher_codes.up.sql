@@ -0,0 +1,5 @@
+-- Table: voucher_codes
+/*  voucher given by a reward inquiry   = 4 -> reserved, tied to reward_transactions by ref_id
+    until the transaction is succeeded (bought) or rejected/timeout (available)*/
+
+CREATE INDEX index_voucher_codes_status ON voucher_codes (voucher_id, status);
@@ -0,0 +1,5 @@
+ALTER TABLE rewards
+DROP COLUMN IF EXISTS priority;
+
+ALTER TABLE campaigns
+DROP COLUMN IF EXISTS stacking_policy;
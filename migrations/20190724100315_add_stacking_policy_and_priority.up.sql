@@ -0,0 +1,14 @@
+-- Table: campaigns
+/*  for stacking_policy  0 --> all, every eligible rewards is given
+                         1 --> best, a single reward with the highest value
+                         2 --> priority, a single reward with the highest priority
+                         3 --> exclusive, every eligible rewards and block the other campaigns */
+
+ALTER TABLE campaigns
+ADD COLUMN stacking_policy SMALLINT NOT NULL DEFAULT 0;
+
+-- Table: rewards
+-- for priority, the lower number will be evaluated first
+
+ALTER TABLE rewards
+ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
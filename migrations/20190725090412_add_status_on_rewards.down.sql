@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS index_reward_transactions_reward_id;
+
+ALTER TABLE rewards
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,8 @@
+-- Table: rewards
+/*  for status  0 --> inactive, the reward is disabled and skipped on inquiry
+                1 --> active */
+
+ALTER TABLE rewards
+ADD COLUMN status SMALLINT NOT NULL DEFAULT 1;
+
+CREATE INDEX index_reward_transactions_reward_id ON reward_transactions (reward_id);
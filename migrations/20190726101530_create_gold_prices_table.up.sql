@@ -0,0 +1,12 @@
+-- Table: gold_prices
+-- price is the rupiah value of one gram gold, used to convert goldback rewards into gram
+
+CREATE TABLE IF NOT EXISTS gold_prices (
+    id SERIAL PRIMARY KEY NOT NULL,
+    price NUMERIC(15,2) NOT NULL,
+    price_date TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_gold_prices_price_date ON gold_prices (price_date);
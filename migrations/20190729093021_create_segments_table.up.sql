@@ -0,0 +1,21 @@
+-- Table: segments
+
+CREATE TABLE IF NOT EXISTS segments (
+    id SERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+-- Table: segment_members
+-- rewards and vouchers refer to the segments by includeSegments and excludeSegments on their validators
+
+CREATE TABLE IF NOT EXISTS segment_members (
+    segment_id INTEGER NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    cif VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT segment_members_pkey PRIMARY KEY (segment_id, cif)
+);
+
+CREATE INDEX index_segment_members_cif ON segment_members (cif, segment_id);
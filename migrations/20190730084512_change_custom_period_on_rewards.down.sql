@@ -0,0 +1,3 @@
+ALTER TABLE rewards
+ALTER COLUMN custom_period TYPE VARCHAR
+USING custom_period::VARCHAR;
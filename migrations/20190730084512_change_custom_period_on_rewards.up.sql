@@ -0,0 +1,9 @@
+-- Table: rewards
+/*  custom_period --> schedule of a reward on the business timezone, null means the whole campaign period
+    {"weekly": [{"days": [5], "startTime": "12:00", "endTime": "14:00"}],
+        "monthly": [{"startDay": 1, "endDay": 7}], "blackouts": ["2019-08-17"]}
+    days    --> 0 sunday until 6 saturday */
+
+ALTER TABLE rewards
+ALTER COLUMN custom_period TYPE JSONB
+USING CASE WHEN custom_period ~ '^\s*\{' THEN custom_period::JSONB ELSE NULL END;
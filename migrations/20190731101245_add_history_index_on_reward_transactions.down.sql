@@ -0,0 +1 @@
+DROP INDEX IF EXISTS index_reward_transactions_history;
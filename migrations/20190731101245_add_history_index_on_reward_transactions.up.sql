@@ -0,0 +1,5 @@
+-- Table: reward_transactions
+/*  history rules read the succeeded transactions of a cif within a transaction date window,
+    the product of the transaction is read from request_data->'validators'->>'product' */
+
+CREATE INDEX index_reward_transactions_history ON reward_transactions (cif, status, transaction_date);
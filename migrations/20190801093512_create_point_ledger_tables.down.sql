@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS point_balances;
+DROP TABLE IF EXISTS point_entries;
+DROP TABLE IF EXISTS point_journals;
+DROP TABLE IF EXISTS point_accounts;
+DROP FUNCTION IF EXISTS check_point_journal_balanced();
+DROP FUNCTION IF EXISTS reject_point_entry_change();
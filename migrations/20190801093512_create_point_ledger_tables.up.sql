@@ -0,0 +1,116 @@
+-- Table: point_accounts
+/*  type    0 --> customer, the code is the cif
+            1 --> system, the counter account of the customer entries */
+
+CREATE TABLE IF NOT EXISTS point_accounts (
+    id SERIAL PRIMARY KEY NOT NULL,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    type SMALLINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+INSERT INTO point_accounts (code, type, created_at) VALUES
+    ('SYSTEM-REWARD', 1, now()),
+    ('SYSTEM-VOUCHER', 1, now());
+
+-- Table: point_journals
+-- source and source_id point to the row that is posted by the journal
+
+CREATE TABLE IF NOT EXISTS point_journals (
+    id SERIAL PRIMARY KEY NOT NULL,
+    journal_type VARCHAR(20) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    source_id INTEGER NOT NULL,
+    ref_id VARCHAR NULL,
+    ref_core VARCHAR NULL,
+    transaction_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_point_journals_source ON point_journals (source, source_id);
+
+-- Table: point_entries
+-- a positive amount adds points to the account, the entries of a journal always sum up to zero
+
+CREATE TABLE IF NOT EXISTS point_entries (
+    id SERIAL PRIMARY KEY NOT NULL,
+    journal_id INTEGER NOT NULL REFERENCES point_journals(id),
+    account_id INTEGER NOT NULL REFERENCES point_accounts(id),
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_point_entries_journal_id ON point_entries (journal_id);
+CREATE INDEX index_point_entries_account_id ON point_entries (account_id, created_at);
+
+CREATE OR REPLACE FUNCTION reject_point_entry_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'point entries are immutable, post a new journal instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER point_entries_immutable BEFORE UPDATE OR DELETE ON point_entries
+    FOR EACH ROW EXECUTE PROCEDURE reject_point_entry_change();
+
+CREATE OR REPLACE FUNCTION check_point_journal_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT sum(amount) FROM point_entries WHERE journal_id = NEW.journal_id) <> 0 THEN
+        RAISE EXCEPTION 'point journal % is not balanced', NEW.journal_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER point_entries_balanced AFTER INSERT ON point_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE check_point_journal_balanced();
+
+-- Table: point_balances
+-- updated in the same transaction as the entries of the account
+
+CREATE TABLE IF NOT EXISTS point_balances (
+    account_id INTEGER PRIMARY KEY NOT NULL REFERENCES point_accounts(id),
+    balance BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NULL
+);
+
+-- backfill the succeeded point histories and the legacy campaign transactions
+
+INSERT INTO point_accounts (code, type, created_at)
+SELECT DISTINCT customers.cif, 0, now() FROM (
+    SELECT cif FROM point_histories WHERE status = 1
+    UNION SELECT user_id FROM campaign_transactions
+) AS customers
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO point_journals (journal_type, source, source_id, ref_id, ref_core, transaction_date, created_at)
+SELECT CASE WHEN transaction_type = 'K' THEN 'redeem' ELSE 'earn' END, 'point_histories', id, ref_id, ref_core,
+    transaction_date, now()
+FROM point_histories WHERE status = 1;
+
+INSERT INTO point_journals (journal_type, source, source_id, ref_id, ref_core, transaction_date, created_at)
+SELECT CASE WHEN transaction_type = 'K' THEN 'redeem' ELSE 'earn' END, 'campaign_transactions', id, ref_id, ref_core,
+    transaction_date, now()
+FROM campaign_transactions;
+
+INSERT INTO point_entries (journal_id, account_id, amount, created_at)
+SELECT pj.id, pa.id, CASE WHEN trx.transaction_type = 'K' THEN -trx.point_amount ELSE trx.point_amount END, now()
+FROM point_journals pj
+JOIN (
+    SELECT 'point_histories' AS source, id, cif, point_amount, transaction_type FROM point_histories
+    UNION ALL SELECT 'campaign_transactions', id, user_id, point_amount, transaction_type FROM campaign_transactions
+) AS trx ON trx.source = pj.source AND trx.id = pj.source_id
+JOIN point_accounts pa ON pa.code = trx.cif;
+
+INSERT INTO point_entries (journal_id, account_id, amount, created_at)
+SELECT pj.id, pa.id, CASE WHEN trx.transaction_type = 'K' THEN trx.point_amount ELSE -trx.point_amount END, now()
+FROM point_journals pj
+JOIN (
+    SELECT 'point_histories' AS source, id, point_amount, transaction_type FROM point_histories
+    UNION ALL SELECT 'campaign_transactions', id, point_amount, transaction_type FROM campaign_transactions
+) AS trx ON trx.source = pj.source AND trx.id = pj.source_id
+JOIN point_accounts pa ON pa.code = CASE WHEN trx.transaction_type = 'K' THEN 'SYSTEM-VOUCHER' ELSE 'SYSTEM-REWARD' END;
+
+INSERT INTO point_balances (account_id, balance, updated_at)
+SELECT account_id, sum(amount), now() FROM point_entries GROUP BY account_id;
@@ -0,0 +1,9 @@
+DELETE FROM point_accounts WHERE code = 'SYSTEM-EXPIRY'
+AND NOT EXISTS (SELECT 1 FROM point_entries pe WHERE pe.account_id = point_accounts.id);
+
+DROP INDEX IF EXISTS index_point_journals_expired_date;
+
+ALTER TABLE point_journals DROP COLUMN IF EXISTS expired_date;
+ALTER TABLE point_accounts DROP COLUMN IF EXISTS expired_at;
+ALTER TABLE point_histories DROP COLUMN IF EXISTS expired_date;
+ALTER TABLE rewards DROP COLUMN IF EXISTS point_expiry;
@@ -0,0 +1,24 @@
+ALTER TABLE rewards
+    ADD COLUMN point_expiry JSONB NULL;
+
+ALTER TABLE point_histories
+    ADD COLUMN expired_date TIMESTAMP NULL;
+
+-- expired_at of an account is the last moment its points have been expired
+ALTER TABLE point_accounts
+    ADD COLUMN expired_at TIMESTAMP NULL;
+
+ALTER TABLE point_journals
+    ADD COLUMN expired_date TIMESTAMP NULL;
+
+CREATE INDEX index_point_journals_expired_date ON point_journals (expired_date);
+
+INSERT INTO point_accounts (code, type, created_at) VALUES ('SYSTEM-EXPIRY', 1, now())
+ON CONFLICT (code) DO NOTHING;
+
+-- the points earned before the expiry policy expire at the end of the following year, the default policy
+UPDATE point_journals SET expired_date = date_trunc('year', transaction_date) + INTERVAL '2 years'
+WHERE journal_type = 'earn';
+
+UPDATE point_histories SET expired_date = date_trunc('year', transaction_date) + INTERVAL '2 years'
+WHERE transaction_type = 'D';
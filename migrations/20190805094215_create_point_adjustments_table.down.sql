@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS point_adjustment_logs;
+DROP TABLE IF EXISTS point_adjustments;
@@ -0,0 +1,40 @@
+-- Table: point_adjustments
+/*  status  0 --> pending
+            1 --> approved, posted to point_histories
+            2 --> rejected
+    transaction_type D --> adds points and K --> deducts points */
+
+CREATE TABLE IF NOT EXISTS point_adjustments (
+    id SERIAL PRIMARY KEY NOT NULL,
+    cif VARCHAR(50) NOT NULL,
+    transaction_type VARCHAR(2) NOT NULL,
+    point_amount INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    ticket_ref VARCHAR(100) NOT NULL,
+    status SMALLINT NOT NULL DEFAULT 0,
+    created_by VARCHAR(50) NOT NULL,
+    reviewed_by VARCHAR(50) NULL,
+    review_note TEXT NULL,
+    reviewed_at TIMESTAMP NULL,
+    point_history_id INTEGER REFERENCES point_histories(id),
+    updated_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT point_adjustments_maker_checker CHECK (reviewed_by IS NULL OR reviewed_by <> created_by)
+);
+
+CREATE INDEX index_point_adjustments ON point_adjustments (status, created_at);
+CREATE INDEX index_point_adjustments_cif ON point_adjustments (cif, created_at);
+
+-- Table: point_adjustment_logs
+-- the audit trail of the adjustments, action is submit, approve or reject
+
+CREATE TABLE IF NOT EXISTS point_adjustment_logs (
+    id SERIAL PRIMARY KEY NOT NULL,
+    adjustment_id INTEGER NOT NULL REFERENCES point_adjustments(id),
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(50) NOT NULL,
+    note TEXT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_point_adjustment_logs ON point_adjustment_logs (adjustment_id, created_at);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS point_reversals;
+
+UPDATE voucher_codes SET status = 1 WHERE status = 5;
+
+UPDATE reward_transactions SET status = 1 WHERE status = 4;
+
+DROP INDEX IF EXISTS index_reward_transactions_ref_core;
+
+ALTER TABLE reward_transactions DROP COLUMN IF EXISTS reversed_ratio;
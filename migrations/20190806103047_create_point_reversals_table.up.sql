@@ -0,0 +1,27 @@
+-- Table: reward_transactions
+/*  status  4 --> reversed, the whole transaction has been reversed by the core
+    reversed_ratio is the part of the transaction that has been reversed, from 0 until 1 */
+
+ALTER TABLE reward_transactions ADD COLUMN reversed_ratio DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX index_reward_transactions_ref_core ON reward_transactions (ref_core, cif);
+
+-- Table: voucher_codes
+/*  voucher given by a reward that is reversed before it is redeemed   = 5 -> void */
+
+-- Table: point_reversals
+-- ref_reversal is the core reference of the reversal, a retried one is never processed twice
+
+CREATE TABLE IF NOT EXISTS point_reversals (
+    id SERIAL PRIMARY KEY NOT NULL,
+    ref_reversal VARCHAR(100) NOT NULL UNIQUE,
+    ref_core VARCHAR(100) NOT NULL,
+    cif VARCHAR(50) NOT NULL,
+    reversed_amount NUMERIC NULL,
+    ratio DOUBLE PRECISION NOT NULL,
+    point_amount INTEGER NOT NULL DEFAULT 0,
+    point_shortfall INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_point_reversals ON point_reversals (ref_core, cif);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS index_point_histories_pending;
+
+DELETE FROM point_histories WHERE status = 0 AND transaction_type = 'D' AND reward_id IS NOT NULL;
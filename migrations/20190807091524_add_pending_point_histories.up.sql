@@ -0,0 +1,10 @@
+-- the point rewards of the transactions that are still inquired are kept as pending point histories,
+-- so they are posted when the transaction is succeeded
+INSERT INTO point_histories (cif, point_amount, transaction_type, transaction_date, used_for, ref_id, status,
+    reward_id, expired_date, created_at)
+SELECT cif, floor((response_data->>'value')::NUMERIC), 'D', transaction_date, '', ref_id, 0, reward_id,
+    (response_data->>'expiredDate')::TIMESTAMP, now()
+FROM reward_transactions
+WHERE status = 0 AND response_data->>'type' = 'point' AND floor((response_data->>'value')::NUMERIC) > 0;
+
+CREATE INDEX index_point_histories_pending ON point_histories (cif, status);
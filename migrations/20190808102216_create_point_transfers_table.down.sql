@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS point_transfer_recipients;
+DROP TABLE IF EXISTS point_transfers;
@@ -0,0 +1,38 @@
+-- Table: point_transfers
+/*  status  0 --> succeeded, the points are moved from cif to recipient_cif
+            1 --> reversed by an admin, the points and the fee are returned to cif
+    ref_id is the reference of the channel, a retried transfer is never sent twice */
+
+CREATE TABLE IF NOT EXISTS point_transfers (
+    id SERIAL PRIMARY KEY NOT NULL,
+    ref_id VARCHAR(100) NOT NULL UNIQUE,
+    cif VARCHAR(50) NOT NULL,
+    recipient_cif VARCHAR(50) NOT NULL,
+    point_amount INTEGER NOT NULL,
+    fee INTEGER NOT NULL DEFAULT 0,
+    note TEXT NULL,
+    status SMALLINT NOT NULL DEFAULT 0,
+    reversed_by VARCHAR(50) NULL,
+    reversal_note TEXT NULL,
+    reversed_at TIMESTAMP NULL,
+    updated_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT point_transfers_recipient CHECK (recipient_cif <> cif)
+);
+
+CREATE INDEX index_point_transfers_cif ON point_transfers (cif, created_at);
+CREATE INDEX index_point_transfers_recipient_cif ON point_transfers (recipient_cif, created_at);
+
+-- Table: point_transfer_recipients
+-- the allowed recipients of a customer, only checked when the allow list is enabled
+
+CREATE TABLE IF NOT EXISTS point_transfer_recipients (
+    id SERIAL PRIMARY KEY NOT NULL,
+    cif VARCHAR(50) NOT NULL,
+    recipient_cif VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (cif, recipient_cif)
+);
+
+INSERT INTO point_accounts (code, type, created_at) VALUES ('SYSTEM-TRANSFER', 1, now()), ('SYSTEM-TRANSFER-FEE', 1, now())
+ON CONFLICT (code) DO NOTHING;
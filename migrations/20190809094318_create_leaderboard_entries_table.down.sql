@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS index_point_histories_earned;
+DROP TABLE IF EXISTS leaderboard_entries;
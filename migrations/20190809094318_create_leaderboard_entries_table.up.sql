@@ -0,0 +1,22 @@
+-- Table: leaderboard_entries
+/*  period      day, week, month or campaign
+    period_key  2019-08-09 for a day, 2019-W32 for a week, 2019-08 for a month and the campaign id for a campaign
+    campaign_id 0 --> the points of every campaign
+    product     '' --> the points of every product
+    the entries are recomputed by a scheduled job, customers with the same points share the same rank */
+
+CREATE TABLE IF NOT EXISTS leaderboard_entries (
+    id SERIAL PRIMARY KEY NOT NULL,
+    period VARCHAR(10) NOT NULL,
+    period_key VARCHAR(20) NOT NULL,
+    campaign_id INTEGER NOT NULL DEFAULT 0,
+    product VARCHAR(50) NOT NULL DEFAULT '',
+    cif VARCHAR(50) NOT NULL,
+    point_amount INTEGER NOT NULL,
+    rank INTEGER NOT NULL,
+    computed_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (period, period_key, campaign_id, product, cif)
+);
+
+CREATE INDEX index_leaderboard_entries_rank ON leaderboard_entries (period, period_key, campaign_id, product, rank);
+CREATE INDEX index_point_histories_earned ON point_histories (transaction_date) WHERE reward_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_tiers;
+DROP TABLE IF EXISTS membership_tiers;
@@ -0,0 +1,27 @@
+-- Table: membership_tiers
+-- rewards and vouchers refer to the tiers by the codes on membershipTiers of their validators
+
+CREATE TABLE IF NOT EXISTS membership_tiers (
+    id SERIAL PRIMARY KEY NOT NULL,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    min_amount NUMERIC(20, 2) NOT NULL DEFAULT 0,
+    multiplier NUMERIC(10, 4) NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+-- Table: customer_tiers
+/*  amount          the rolling 12 months earned points or transaction volume at the last evaluation
+    downgrade_date  the tier is kept until this date, NULL for the lowest tier */
+
+CREATE TABLE IF NOT EXISTS customer_tiers (
+    cif VARCHAR(50) PRIMARY KEY NOT NULL,
+    tier_id INTEGER NULL REFERENCES membership_tiers(id) ON DELETE SET NULL,
+    amount NUMERIC(20, 2) NOT NULL DEFAULT 0,
+    qualified_at TIMESTAMP DEFAULT NULL,
+    downgrade_date TIMESTAMP DEFAULT NULL,
+    evaluated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX index_customer_tiers_downgrade_date ON customer_tiers (downgrade_date);
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS index_reward_transactions_history ON reward_transactions (cif, status, transaction_date);
+DROP TABLE IF EXISTS transaction_histories;
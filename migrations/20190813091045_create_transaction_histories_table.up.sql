@@ -0,0 +1,32 @@
+-- Table: transaction_histories
+/*  every transaction that is evaluated by an inquiry, whether it is rewarded or not, the history rules
+    count them except the ones whose reward transaction is rejected, timed out or reversed.
+    A retried inquiry of the same ref_channel is only recorded once. */
+
+CREATE TABLE IF NOT EXISTS transaction_histories (
+    id SERIAL PRIMARY KEY NOT NULL,
+    ref_id VARCHAR(50) NOT NULL UNIQUE,
+    ref_channel VARCHAR(50) NULL,
+    cif VARCHAR(50) NOT NULL,
+    product VARCHAR(50) NULL,
+    transaction_amount NUMERIC NOT NULL DEFAULT 0,
+    transaction_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX transaction_histories_ref_channel_cif_key ON transaction_histories (ref_channel, cif)
+    WHERE ref_channel IS NOT NULL;
+CREATE INDEX index_transaction_histories ON transaction_histories (cif, transaction_date);
+
+-- backfill the rewarded transactions, a transaction could have many rewards so it is recorded once by its ref id
+INSERT INTO transaction_histories (ref_id, ref_channel, cif, product, transaction_amount, transaction_date,
+    created_at)
+SELECT DISTINCT ON (ref_id) ref_id, nullif(request_data->>'refChannel', ''), cif,
+    nullif(request_data->'validators'->>'product', ''),
+    coalesce((request_data->>'transactionAmount')::NUMERIC, 0), transaction_date, now()
+FROM reward_transactions WHERE ref_id IS NOT NULL AND cif IS NOT NULL AND transaction_date IS NOT NULL
+ORDER BY ref_id, id
+ON CONFLICT DO NOTHING;
+
+-- the history rules no longer read the reward transactions
+DROP INDEX IF EXISTS index_reward_transactions_history;
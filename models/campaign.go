@@ -1,20 +1,46 @@
 package models
 
 import (
+	"sort"
 	"time"
 )
 
+var (
+	// CampaignStackingAll to store stacking policy that give every eligible rewards
+	CampaignStackingAll int64
+	// CampaignStackingBest to store stacking policy that give a single reward with the highest value
+	CampaignStackingBest int64 = 1
+	// CampaignStackingPriority to store stacking policy that give a single reward with the highest priority
+	CampaignStackingPriority int64 = 2
+	// CampaignStackingExclusive to store stacking policy that give every eligible rewards and block the others campaign
+	CampaignStackingExclusive int64 = 3
+)
+
+var stackingPolicy = map[int64]string{
+	CampaignStackingAll:       "all",
+	CampaignStackingBest:      "best",
+	CampaignStackingPriority:  "priority",
+	CampaignStackingExclusive: "exclusive",
+}
+
 // Campaign is represent a campaigns model
 type Campaign struct {
-	ID          int64      `json:"id,omitempty"`
-	Name        string     `json:"name,omitempty"`
-	Description string     `json:"description,omitempty"`
-	EndDate     string     `json:"endDate,omitempty"`
-	StartDate   string     `json:"startDate,omitempty"`
-	Status      *int8      `json:"status,omitempty"`
-	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
-	CreatedAt   *time.Time `json:"createdAt,omitempty"`
-	Rewards     *[]Reward  `json:"rewards,omitempty"`
+	ID             int64      `json:"id,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	EndDate        string     `json:"endDate,omitempty"`
+	StartDate      string     `json:"startDate,omitempty"`
+	Status         *int8      `json:"status,omitempty"`
+	StackingPolicy *int64     `json:"stackingPolicy,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	Rewards        *[]Reward  `json:"rewards,omitempty"`
+}
+
+// RewardCandidate is represent an eligible reward before the campaign stacking policy is applied
+type RewardCandidate struct {
+	Reward Reward
+	Value  float64
 }
 
 // GetCampaignValue to store payload get campaign value
@@ -28,3 +54,69 @@ type GetCampaignValue struct {
 	Source            string  `json:"source,omitempty"` // device name that user used
 	RefCore           string  `json:"refCore,omitempty"`
 }
+
+// GetStackingPolicy to get the campaign stacking policy, all rewards is the default
+func (cmp Campaign) GetStackingPolicy() int64 {
+	if cmp.StackingPolicy == nil {
+		return CampaignStackingAll
+	}
+
+	return *cmp.StackingPolicy
+}
+
+// GetStackingPolicyText to get text of the campaign stacking policy
+func (cmp Campaign) GetStackingPolicyText() string {
+	return stackingPolicy[cmp.GetStackingPolicy()]
+}
+
+// IsValidStackingPolicy to check whether the campaign stacking policy is known
+func (cmp Campaign) IsValidStackingPolicy() bool {
+	_, ok := stackingPolicy[cmp.GetStackingPolicy()]
+
+	return ok
+}
+
+// IsExclusive to check whether the campaign block the others campaign
+func (cmp Campaign) IsExclusive() bool {
+	return cmp.GetStackingPolicy() == CampaignStackingExclusive
+}
+
+// GetMaxRewards to get how many rewards could be given by the campaign, zero means unlimited
+func (cmp Campaign) GetMaxRewards() int {
+	switch cmp.GetStackingPolicy() {
+	case CampaignStackingBest, CampaignStackingPriority:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SortCandidates to order the eligible rewards based on the campaign stacking policy.
+// best policy is ordered by the highest value, the others by priority then reward id
+func (cmp Campaign) SortCandidates(candidates []RewardCandidate) []RewardCandidate {
+	isBest := cmp.GetStackingPolicy() == CampaignStackingBest
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if isBest && candidates[i].Value != candidates[j].Value {
+			return candidates[i].Value > candidates[j].Value
+		}
+
+		if candidates[i].Reward.GetPriority() != candidates[j].Reward.GetPriority() {
+			return candidates[i].Reward.GetPriority() < candidates[j].Reward.GetPriority()
+		}
+
+		return candidates[i].Reward.ID < candidates[j].Reward.ID
+	})
+
+	return candidates
+}
+
+// SortCampaigns to order the available campaigns, exclusive campaigns are evaluated first
+// and the rest keep their original order
+func SortCampaigns(campaigns []*Campaign) []*Campaign {
+	sort.SliceStable(campaigns, func(i, j int) bool {
+		return campaigns[i].IsExclusive() && !campaigns[j].IsExclusive()
+	})
+
+	return campaigns
+}
@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCandidates(t *testing.T) {
+	first, second := int64(1), int64(2)
+	candidates := func() []models.RewardCandidate {
+		return []models.RewardCandidate{
+			{Reward: models.Reward{ID: 3, Priority: &second}, Value: 5000},
+			{Reward: models.Reward{ID: 2, Priority: &first}, Value: 1000},
+			{Reward: models.Reward{ID: 1, Priority: &second}, Value: 5000},
+		}
+	}
+	campaign := models.Campaign{StackingPolicy: &models.CampaignStackingBest}
+
+	// when the policy is best, highest value first then priority and id
+	sorted := campaign.SortCandidates(candidates())
+	assert.Equal(t, int64(1), sorted[0].Reward.ID)
+	assert.Equal(t, int64(3), sorted[1].Reward.ID)
+	assert.Equal(t, int64(2), sorted[2].Reward.ID)
+	assert.Equal(t, 1, campaign.GetMaxRewards())
+
+	// when the policy is priority, lowest priority first then id
+	campaign.StackingPolicy = &models.CampaignStackingPriority
+	sorted = campaign.SortCandidates(candidates())
+	assert.Equal(t, int64(2), sorted[0].Reward.ID)
+	assert.Equal(t, int64(1), sorted[1].Reward.ID)
+	assert.Equal(t, 1, campaign.GetMaxRewards())
+
+	// when the policy is not available, all rewards is the default
+	campaign.StackingPolicy = nil
+	assert.Equal(t, models.CampaignStackingAll, campaign.GetStackingPolicy())
+	assert.Equal(t, 0, campaign.GetMaxRewards())
+	assert.True(t, campaign.IsValidStackingPolicy())
+
+	unknown := int64(9)
+	campaign.StackingPolicy = &unknown
+	assert.False(t, campaign.IsValidStackingPolicy())
+}
+
+func TestSortCampaigns(t *testing.T) {
+	campaigns := []*models.Campaign{
+		{ID: 1},
+		{ID: 2, StackingPolicy: &models.CampaignStackingExclusive},
+		{ID: 3, StackingPolicy: &models.CampaignStackingBest},
+		{ID: 4, StackingPolicy: &models.CampaignStackingExclusive},
+	}
+
+	sorted := models.SortCampaigns(campaigns)
+	assert.Equal(t, int64(2), sorted[0].ID)
+	assert.Equal(t, int64(4), sorted[1].ID)
+	assert.Equal(t, int64(1), sorted[2].ID)
+	assert.Equal(t, int64(3), sorted[3].ID)
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// BusinessTimezoneDefault to store default timezone of the custom period schedule
+	BusinessTimezoneDefault = "Asia/Jakarta"
+
+	// CustomPeriodTimeFormat to store a time format of a custom period window
+	CustomPeriodTimeFormat = "15:04"
+
+	// CustomPeriodDateFormat to store a date format of a custom period blackout
+	CustomPeriodDateFormat = "2006-01-02"
+
+	businessLocation     *time.Location
+	businessLocationOnce sync.Once
+)
+
+// CustomPeriod is represent a reward schedule. A transaction should fall in one of the weekly
+// windows and in one of the monthly ranges if any, and never on a blackout date.
+//
+//	{"weekly": [{"days": [5], "startTime": "12:00", "endTime": "14:00"}],
+//		"monthly": [{"startDay": 1, "endDay": 7}], "blackouts": ["2019-08-17"]}
+type CustomPeriod struct {
+	Weekly    []WeeklyWindow `json:"weekly,omitempty"`
+	Monthly   []MonthlyRange `json:"monthly,omitempty"`
+	Blackouts []string       `json:"blackouts,omitempty"`
+}
+
+// WeeklyWindow is represent a recurring time window on some days of a week,
+// day 0 is sunday and an empty start or end time means the whole day
+type WeeklyWindow struct {
+	Days      []int  `json:"days,omitempty"`
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// MonthlyRange is represent an inclusive range of days of every month
+type MonthlyRange struct {
+	StartDay int `json:"startDay,omitempty"`
+	EndDay   int `json:"endDay,omitempty"`
+}
+
+// GetBusinessLocation to get the timezone of the custom period schedule from BUSINESS_TIMEZONE env
+func GetBusinessLocation() *time.Location {
+	businessLocationOnce.Do(func() {
+		name := os.Getenv(`BUSINESS_TIMEZONE`)
+
+		if name == "" {
+			name = BusinessTimezoneDefault
+		}
+
+		location, err := time.LoadLocation(name)
+
+		// WIB is used when the timezone database is not available
+		if err != nil {
+			location = time.FixedZone("WIB", 7*60*60)
+		}
+
+		businessLocation = location
+	})
+
+	return businessLocation
+}
+
+// Check to check whether the custom period is well formed
+func (cp *CustomPeriod) Check() error {
+	for _, window := range cp.Weekly {
+		if len(window.Days) == 0 {
+			return fmt.Errorf("custom period weekly window needs at least a day")
+		}
+
+		for _, day := range window.Days {
+			if day < int(time.Sunday) || day > int(time.Saturday) {
+				return fmt.Errorf("custom period day %d should be between 0 (sunday) and 6 (saturday)", day)
+			}
+		}
+
+		start, end, err := window.getMinutes()
+
+		if err != nil {
+			return err
+		}
+
+		if start >= end {
+			return fmt.Errorf("custom period start time %s should be before end time %s", window.StartTime, window.EndTime)
+		}
+	}
+
+	for _, monthly := range cp.Monthly {
+		if monthly.StartDay < 1 || monthly.EndDay > 31 || monthly.StartDay > monthly.EndDay {
+			return fmt.Errorf("custom period monthly range %d-%d should be between 1 and 31", monthly.StartDay, monthly.EndDay)
+		}
+	}
+
+	for _, blackout := range cp.Blackouts {
+		if _, err := time.Parse(CustomPeriodDateFormat, blackout); err != nil {
+			return fmt.Errorf("custom period blackout %s should be in YYYY-MM-DD format", blackout)
+		}
+	}
+
+	return nil
+}
+
+// IsActive to check whether the transaction date is inside the custom period on the given timezone
+func (cp *CustomPeriod) IsActive(trxDate time.Time, location *time.Location) bool {
+	localDate := trxDate.In(location)
+	date := localDate.Format(CustomPeriodDateFormat)
+
+	for _, blackout := range cp.Blackouts {
+		if blackout == date {
+			return false
+		}
+	}
+
+	if len(cp.Weekly) > 0 && !cp.isWeeklyActive(localDate) {
+		return false
+	}
+
+	if len(cp.Monthly) > 0 && !cp.isMonthlyActive(localDate) {
+		return false
+	}
+
+	return true
+}
+
+func (cp *CustomPeriod) isWeeklyActive(localDate time.Time) bool {
+	minute := localDate.Hour()*60 + localDate.Minute()
+
+	for _, window := range cp.Weekly {
+		start, end, err := window.getMinutes()
+
+		if err != nil || minute < start || minute >= end {
+			continue
+		}
+
+		for _, day := range window.Days {
+			if day == int(localDate.Weekday()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (cp *CustomPeriod) isMonthlyActive(localDate time.Time) bool {
+	for _, monthly := range cp.Monthly {
+		if localDate.Day() >= monthly.StartDay && localDate.Day() <= monthly.EndDay {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getMinutes to get the window as minutes of a day, the end time is exclusive
+func (ww WeeklyWindow) getMinutes() (int, int, error) {
+	start, end := 0, 24*60
+
+	if ww.StartTime != "" {
+		startTime, err := time.Parse(CustomPeriodTimeFormat, ww.StartTime)
+
+		if err != nil {
+			return 0, 0, fmt.Errorf("custom period start time %s should be in HH:MM format", ww.StartTime)
+		}
+
+		start = startTime.Hour()*60 + startTime.Minute()
+	}
+
+	if ww.EndTime != "" && ww.EndTime != "24:00" {
+		endTime, err := time.Parse(CustomPeriodTimeFormat, ww.EndTime)
+
+		if err != nil {
+			return 0, 0, fmt.Errorf("custom period end time %s should be in HH:MM format", ww.EndTime)
+		}
+
+		end = endTime.Hour()*60 + endTime.Minute()
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomPeriodIsActive(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	fridayLunch := &models.CustomPeriod{
+		Weekly: []models.WeeklyWindow{{Days: []int{5}, StartTime: "12:00", EndTime: "14:00"}},
+	}
+	weekend := &models.CustomPeriod{
+		Weekly:    []models.WeeklyWindow{{Days: []int{0, 6}}},
+		Blackouts: []string{"2019-08-17"},
+	}
+	firstWeek := &models.CustomPeriod{
+		Monthly: []models.MonthlyRange{{StartDay: 1, EndDay: 7}},
+	}
+	tests := []struct {
+		name         string
+		customPeriod *models.CustomPeriod
+		trxDate      string
+		expected     bool
+	}{
+		{"friday lunch", fridayLunch, "2019-08-02T12:30:00+07:00", true},
+		{"friday lunch end time is exclusive", fridayLunch, "2019-08-02T14:00:00+07:00", false},
+		{"friday lunch on thursday", fridayLunch, "2019-08-01T12:30:00+07:00", false},
+		{"friday lunch on business timezone", fridayLunch, "2019-08-02T05:15:00Z", true},
+		{"friday lunch on utc", fridayLunch, "2019-08-02T12:30:00Z", false},
+		{"weekend", weekend, "2019-08-04T23:59:00+07:00", true},
+		{"weekend on monday", weekend, "2019-08-05T00:00:00+07:00", false},
+		{"weekend on blackout", weekend, "2019-08-17T10:00:00+07:00", false},
+		{"first week", firstWeek, "2019-09-07T10:00:00+07:00", true},
+		{"first week on the 8th", firstWeek, "2019-09-08T10:00:00+07:00", false},
+		{"empty period", &models.CustomPeriod{}, "2019-09-08T10:00:00+07:00", true},
+	}
+
+	for _, test := range tests {
+		trxDate, _ := time.Parse(time.RFC3339, test.trxDate)
+
+		assert.Equal(t, test.expected, test.customPeriod.IsActive(trxDate, wib), test.name)
+	}
+}
+
+func TestCustomPeriodCheck(t *testing.T) {
+	valid := models.CustomPeriod{
+		Weekly:    []models.WeeklyWindow{{Days: []int{5}, StartTime: "12:00", EndTime: "24:00"}},
+		Monthly:   []models.MonthlyRange{{StartDay: 1, EndDay: 7}},
+		Blackouts: []string{"2019-08-17"},
+	}
+	invalids := []models.CustomPeriod{
+		{Weekly: []models.WeeklyWindow{{StartTime: "12:00"}}},
+		{Weekly: []models.WeeklyWindow{{Days: []int{7}}}},
+		{Weekly: []models.WeeklyWindow{{Days: []int{1}, StartTime: "14:00", EndTime: "12:00"}}},
+		{Weekly: []models.WeeklyWindow{{Days: []int{1}, StartTime: "noon"}}},
+		{Monthly: []models.MonthlyRange{{StartDay: 10, EndDay: 5}}},
+		{Monthly: []models.MonthlyRange{{StartDay: 0, EndDay: 32}}},
+		{Blackouts: []string{"17-08-2019"}},
+	}
+
+	assert.NoError(t, valid.Check())
+
+	for _, invalid := range invalids {
+		assert.Error(t, invalid.Check())
+	}
+}
@@ -45,9 +45,15 @@ var (
 	// ErrGetUserPointHistory to get user point history error message
 	ErrGetUserPointHistory = errors.New("Something went wrong when trying to get user point histrory")
 
+	// ErrGetUserPointExpiry to get user point expiry error message
+	ErrGetUserPointExpiry = errors.New("Something went wrong when trying to get user point expiry")
+
 	// ErrUserPointHistoryNA to get user point history N/A error message
 	ErrUserPointHistoryNA = errors.New("You dont have any points history yet")
 
+	// ErrStackingPolicy to store unknown campaign stacking policy error message
+	ErrStackingPolicy = errors.New("Campaign stacking policy is not valid")
+
 	// ErrCampaignExpired to store campaign expired error message
 	ErrCampaignExpired = errors.New("Campaign has been expired")
 
@@ -168,18 +174,93 @@ var (
 	// ErrCreateRewardsFailed to store create rewards failed message
 	ErrCreateRewardsFailed = errors.New("Something went wrong when trying to create rewards")
 
+	// ErrRewardUnavailable to store no eligible reward of a transaction error message
+	ErrRewardUnavailable = errors.New("Your transaction is not eligible for any reward")
+
 	// ErrRewardTrxFailed to store create reward transaction failed error message
 	ErrRewardTrxFailed = errors.New("Failed to create a reward transaction")
 
 	// ErrRewardTrxUpdateFailed to store create reward transaction failed error message
 	ErrRewardTrxUpdateFailed = errors.New("Failed to update a reward transaction")
 
+	// ErrGenerateRefID to store generate reference id error message
+	ErrGenerateRefID = errors.New("Something went wrong when trying to generate reference id")
+
+	// ErrRefTrxNotFound to store reward transaction reference not found error message
+	ErrRefTrxNotFound = errors.New("Reward transaction reference is not found")
+
+	// ErrRewardTrxProcessed to store reward transaction already processed error message
+	ErrRewardTrxProcessed = errors.New("Reward transaction has already been processed")
+
+	// ErrRewardTrxTimeout to store reward transaction timeout failed error message
+	ErrRewardTrxTimeout = errors.New("Something went wrong when trying to timeout reward transactions")
+
+	// ErrInquiryConflict to store inquiry conflicted error message
+	ErrInquiryConflict = errors.New("Reference channel has been used by another inquiry request")
+
+	// ErrInquiryInProgress to store inquiry in progress error message
+	ErrInquiryInProgress = errors.New("Inquiry with this reference channel is still in progress")
+
+	// ErrInquiryLog to store inquiry log error message
+	ErrInquiryLog = errors.New("Something went wrong when trying to store the inquiry request")
+
+	// ErrGetReward to store get reward error message
+	ErrGetReward = errors.New("Something went wrong when trying to get reward")
+
+	// ErrNoReward to store reward not found error message
+	ErrNoReward = errors.New("Reward is not found")
+
+	// ErrRewardUpdateFailed to store update reward failed error message
+	ErrRewardUpdateFailed = errors.New("Failed to update a reward")
+
+	// ErrRewardInUse to store reward referenced by reward transactions error message
+	ErrRewardInUse = errors.New("Reward has been used by reward transactions")
+
+	// ErrRewardStatus to store reward status error message
+	ErrRewardStatus = errors.New("Reward status is not valid")
+
+	// ErrGoldPriceUnavailable to store gold price unavailable error message
+	ErrGoldPriceUnavailable = errors.New("Gold price is not available to calculate the goldback")
+
+	// ErrSegmentFailed to store create segment failed error message
+	ErrSegmentFailed = errors.New("Failed to create a segment")
+
+	// ErrGetSegment to store get segment error message
+	ErrGetSegment = errors.New("Something went wrong when trying to get segment")
+
+	// ErrNoSegment to store segment not found error message
+	ErrNoSegment = errors.New("Segment is not found")
+
+	// ErrSegmentMembers to store segment members modification error message
+	ErrSegmentMembers = errors.New("Something went wrong when trying to store segment members")
+
+	// ErrSegmentNotIncluded to store customer is not a member of the included segments error message
+	ErrSegmentNotIncluded = errors.New("Customer is not eligible for this segment")
+
+	// ErrSegmentExcluded to store customer is a member of the excluded segments error message
+	ErrSegmentExcluded = errors.New("Customer is excluded from this segment")
+
 	// ErrDelRewardFailed to store delete reward error message
 	ErrDelRewardFailed = errors.New("Something went wrong when deleting a reward")
 
 	// ErrDelQuotaFailed to store delete quota error message
 	ErrDelQuotaFailed = errors.New("Something went wrong when deleting a quota")
 
+	// ErrUseQuotaFailed to store use quota error message
+	ErrUseQuotaFailed = errors.New("Something went wrong when trying to use a reward quota")
+
+	// ErrGetQuota to store get quota error message
+	ErrGetQuota = errors.New("Something went wrong when trying to get quotas")
+
+	// ErrQuotaExhausted to store quota exhausted error message
+	ErrQuotaExhausted = errors.New("Reward quota has been used up")
+
+	// ErrUserQuotaExhausted to store user quota exhausted error message
+	ErrUserQuotaExhausted = errors.New("Reward quota for this user has been used up")
+
+	// ErrReleaseRewardTrx to store release reward transaction error message
+	ErrReleaseRewardTrx = errors.New("Something went wrong when trying to release a reward transaction")
+
 	// ErrCreateQuotasFailed to store create quotas failed message
 	ErrCreateQuotasFailed = errors.New("Something went wrong when trying to create quotas")
 
@@ -192,6 +273,129 @@ var (
 	// ErrTrxDateFormat to store a trx date format params error message
 	ErrTrxDateFormat = errors.New("Transaction date parameters is not meet the format")
 
+	// ErrRewardOutOfPeriod to store a transaction outside the reward custom period error message
+	ErrRewardOutOfPeriod = errors.New("Reward is not available at the transaction time")
+
+	// ErrGetHistory to store get customer transaction history error message
+	ErrGetHistory = errors.New("Something went wrong when trying to get transaction history")
+
+	// ErrCreateHistory to store create customer transaction history error message
+	ErrCreateHistory = errors.New("Something went wrong when trying to store transaction history")
+
+	// ErrAsOfDateFormat to store an as of date format params error message
+	ErrAsOfDateFormat = errors.New("As of date parameters is not meet the format")
+
+	// ErrAdjustmentFailed to store failed point adjustment error message
+	ErrAdjustmentFailed = errors.New("Failed to submit the point adjustment")
+
+	// ErrGetAdjustment to store get point adjustment error message
+	ErrGetAdjustment = errors.New("Something went wrong when trying to get point adjustment")
+
+	// ErrNoAdjustment to store point adjustment not found error message
+	ErrNoAdjustment = errors.New("Point adjustment is not found")
+
+	// ErrAdjustmentActor to store a non admin user of a point adjustment error message
+	ErrAdjustmentActor = errors.New("Only an admin user is allowed to adjust customer points")
+
+	// ErrAdjustmentReviewed to store an already reviewed point adjustment error message
+	ErrAdjustmentReviewed = errors.New("Point adjustment has already been reviewed")
+
+	// ErrAdjustmentSelfReview to store a point adjustment reviewed by its maker error message
+	ErrAdjustmentSelfReview = errors.New("Point adjustment should be reviewed by another admin user")
+
+	// ErrAdjustmentDeficit to store a debit adjustment above the customer point error message
+	ErrAdjustmentDeficit = errors.New("Customer point is not enough for the debit adjustment")
+
+	// ErrReviewAdjustment to store review point adjustment error message
+	ErrReviewAdjustment = errors.New("Something went wrong when trying to review point adjustment")
+
+	// ErrRefCoreNotFound to store core transaction without succeeded rewards error message
+	ErrRefCoreNotFound = errors.New("There is no succeeded reward transaction of the refCore that you input")
+
+	// ErrReversalExceeded to store reversal above the rest of the transaction error message
+	ErrReversalExceeded = errors.New("Reversed amount exceeds the rest of the transaction")
+
+	// ErrReversalAmount to store reversal of a transaction without amount error message
+	ErrReversalAmount = errors.New("Partial reversal is not available for a transaction without amount")
+
+	// ErrReversalProcessed to store an already processed refReversal error message
+	ErrReversalProcessed = errors.New("The refReversal that you input has already been processed")
+
+	// ErrReversalFailed to store failed reversal error message
+	ErrReversalFailed = errors.New("Something went wrong when trying to reverse the rewards")
+
+	// ErrTransferAmount to store transfer amount out of the limits error message
+	ErrTransferAmount = errors.New("Transferred point is out of the minimum and maximum amount")
+
+	// ErrTransferDailyLimit to store transfer above the daily limit error message
+	ErrTransferDailyLimit = errors.New("Transferred point exceeds your daily transfer limit")
+
+	// ErrTransferRecipient to store transfer to a recipient out of the allow list error message
+	ErrTransferRecipient = errors.New("Recipient is not in your allowed recipients")
+
+	// ErrTransferDeficit to store transfer above the sender point error message
+	ErrTransferDeficit = errors.New("You dont have enough point to transfer")
+
+	// ErrTransferProcessed to store an already processed transfer reference error message
+	ErrTransferProcessed = errors.New("The refId of the transfer has already been processed")
+
+	// ErrTransferFailed to store failed point transfer error message
+	ErrTransferFailed = errors.New("Something went wrong when trying to transfer point")
+
+	// ErrTransferRecipientFailed to store failed transfer recipient error message
+	ErrTransferRecipientFailed = errors.New("Something went wrong when trying to update the allowed recipients")
+
+	// ErrGetTransfer to store get point transfer error message
+	ErrGetTransfer = errors.New("Something went wrong when trying to get point transfer")
+
+	// ErrNoTransfer to store point transfer not found error message
+	ErrNoTransfer = errors.New("Point transfer is not found")
+
+	// ErrTransferReversed to store an already reversed point transfer error message
+	ErrTransferReversed = errors.New("Point transfer has already been reversed")
+
+	// ErrTransferReversalDeficit to store reversal above the recipient point error message
+	ErrTransferReversalDeficit = errors.New("Recipient point is not enough to reverse the transfer")
+
+	// ErrTransferReversalActor to store a non admin user of a transfer reversal error message
+	ErrTransferReversalActor = errors.New("Only an admin user is allowed to reverse a point transfer")
+
+	// ErrReverseTransfer to store reverse point transfer error message
+	ErrReverseTransfer = errors.New("Something went wrong when trying to reverse point transfer")
+
+	// ErrLeaderboardPeriod to store unknown leaderboard period error message
+	ErrLeaderboardPeriod = errors.New("Leaderboard period must be one of day, week, month or campaign")
+
+	// ErrLeaderboardCampaign to store campaign leaderboard without campaign id error message
+	ErrLeaderboardCampaign = errors.New("Campaign leaderboard requires a campaignId")
+
+	// ErrLeaderboardDate to store invalid leaderboard date error message
+	ErrLeaderboardDate = errors.New("Leaderboard date must be formatted as YYYY-MM-DD")
+
+	// ErrGetLeaderboard to store get leaderboard error message
+	ErrGetLeaderboard = errors.New("Something went wrong when trying to get the leaderboard")
+
+	// ErrLeaderboardRankNA to store customer without rank on a leaderboard error message
+	ErrLeaderboardRankNA = errors.New("You are not ranked on this leaderboard yet")
+
+	// ErrComputeLeaderboard to store compute leaderboards error message
+	ErrComputeLeaderboard = errors.New("Something went wrong when trying to compute the leaderboards")
+
+	// ErrMembershipTierFailed to store create or update membership tier failed error message
+	ErrMembershipTierFailed = errors.New("Failed to store the membership tier")
+
+	// ErrGetMembershipTier to store get membership tier error message
+	ErrGetMembershipTier = errors.New("Something went wrong when trying to get membership tier")
+
+	// ErrNoMembershipTier to store membership tier not found error message
+	ErrNoMembershipTier = errors.New("Membership tier is not found")
+
+	// ErrMembershipTierNotTargeted to store customer tier is not targeted by the benefit error message
+	ErrMembershipTierNotTargeted = errors.New("Customer membership tier is not eligible for this benefit")
+
+	// ErrEvaluateMembershipTier to store evaluate customer tier error message
+	ErrEvaluateMembershipTier = errors.New("Something went wrong when trying to evaluate the membership tier")
+
 	// ErrCreateMetric to store metric error message
 	ErrCreateMetric = errors.New("Failed to create metric")
 
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	govaluate "gopkg.in/Knetic/govaluate.v2"
+)
+
+// formulaVariables is the whitelist of variables that could be used by a formula
+var formulaVariables = map[string]func(*Validator, *PayloadValidator) interface{}{
+	"transactionAmount":    func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(pl.TransactionAmount) },
+	"loanAmount":           func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(pl.LoanAmount) },
+	"value":                func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.Value) },
+	"multiplier":           func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.Multiplier) },
+	"discount":             func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.Discount) },
+	"maxValue":             func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.MaxValue) },
+	"minTransactionAmount": func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.MinTransactionAmount) },
+	"minLoanAmount":        func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.MinLoanAmount) },
+	"maxLoanAmount":        func(v *Validator, pl *PayloadValidator) interface{} { return getFloatPointer(v.MaxLoanAmount) },
+}
+
+// formulaFunctions is the functions that could be used by a formula
+var formulaFunctions = map[string]govaluate.ExpressionFunction{
+	"min": func(args ...interface{}) (interface{}, error) {
+		return reduceNumbers("min", args, math.Min)
+	},
+	"max": func(args ...interface{}) (interface{}, error) {
+		return reduceNumbers("max", args, math.Max)
+	},
+	"floor": func(args ...interface{}) (interface{}, error) {
+		return roundNumber("floor", args, math.Floor)
+	},
+	"ceil": func(args ...interface{}) (interface{}, error) {
+		return roundNumber("ceil", args, math.Ceil)
+	},
+	"round": func(args ...interface{}) (interface{}, error) {
+		return roundNumber("round", args, math.Round)
+	},
+	"if": func(args ...interface{}) (interface{}, error) {
+		if len(args) != 3 {
+			return nil, errors.New("formula function if needs 3 arguments: condition, then and else")
+		}
+
+		condition, ok := args[0].(bool)
+
+		if !ok {
+			return nil, errors.New("formula function if needs a boolean condition")
+		}
+
+		if condition {
+			return args[1], nil
+		}
+
+		return args[2], nil
+	},
+}
+
+var formulaCache = struct {
+	sync.RWMutex
+	expressions map[string]*govaluate.EvaluableExpression
+}{expressions: map[string]*govaluate.EvaluableExpression{}}
+
+// CompileFormula to compile a formula and check its variables against the whitelist,
+// the compiled expression is cached by the formula so it is only parsed once
+func CompileFormula(formula string) (*govaluate.EvaluableExpression, error) {
+	formulaCache.RLock()
+	expression, ok := formulaCache.expressions[formula]
+	formulaCache.RUnlock()
+
+	if ok {
+		return expression, nil
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(formula, formulaFunctions)
+
+	if err != nil {
+		return nil, fmt.Errorf("formula %s is not valid: %s", formula, err)
+	}
+
+	for _, variable := range expression.Vars() {
+		if _, ok := formulaVariables[variable]; !ok {
+			return nil, fmt.Errorf("formula variable %s is not allowed, use one of: %s", variable,
+				strings.Join(getFormulaVariables(), ", "))
+		}
+	}
+
+	formulaCache.Lock()
+	formulaCache.expressions[formula] = expression
+	formulaCache.Unlock()
+
+	return expression, nil
+}
+
+func getFormulaVariables() []string {
+	var variables []string
+
+	for variable := range formulaVariables {
+		variables = append(variables, variable)
+	}
+
+	sort.Strings(variables)
+
+	return variables
+}
+
+func getFormulaParameters(v *Validator, payloadValidator *PayloadValidator) map[string]interface{} {
+	parameters := make(map[string]interface{}, len(formulaVariables))
+
+	for variable, getVariable := range formulaVariables {
+		value := getVariable(v, payloadValidator)
+
+		// unavailable variable is counted as zero
+		if value == nil {
+			value = float64(0)
+		}
+
+		parameters[variable] = value
+	}
+
+	return parameters
+}
+
+func reduceNumbers(name string, args []interface{}, reducer func(float64, float64) float64) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("formula function %s needs at least 1 argument", name)
+	}
+
+	var result float64
+
+	for i, arg := range args {
+		number, ok := arg.(float64)
+
+		if !ok {
+			return nil, fmt.Errorf("formula function %s needs number arguments", name)
+		}
+
+		if i == 0 {
+			result = number
+
+			continue
+		}
+
+		result = reducer(result, number)
+	}
+
+	return result, nil
+}
+
+func roundNumber(name string, args []interface{}, rounder func(float64) float64) (interface{}, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("formula function %s needs a number and an optional precision", name)
+	}
+
+	number, isNumber := args[0].(float64)
+	precision, isPrecision := float64(0), true
+
+	if len(args) == 2 {
+		precision, isPrecision = args[1].(float64)
+	}
+
+	if !isNumber || !isPrecision {
+		return nil, fmt.Errorf("formula function %s needs number arguments", name)
+	}
+
+	pow := math.Pow(10, precision)
+
+	return rounder(number*pow) / pow, nil
+}
@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileFormula(t *testing.T) {
+	// when its valid
+	_, err := models.CompileFormula("if(loanAmount > 0, min(loanAmount * 0.01, maxValue), round(transactionAmount / 3, 2))")
+	assert.Nil(t, err)
+
+	// when the variable is not in the whitelist
+	_, err = models.CompileFormula("transactionAmount * multipler")
+	assert.EqualError(t, err, "formula variable multipler is not allowed, use one of: discount, loanAmount, "+
+		"maxLoanAmount, maxValue, minLoanAmount, minTransactionAmount, multiplier, transactionAmount, value")
+
+	// when the syntax is not valid
+	_, err = models.CompileFormula("(transactionAmount * value")
+	assert.NotNil(t, err)
+
+	// when the function is not available
+	_, err = models.CompileFormula("abs(transactionAmount)")
+	assert.NotNil(t, err)
+}
+
+func TestGetFormulaResultWithFunctions(t *testing.T) {
+	trxAmount := float64(1000000)
+	loanAmount := float64(2000000)
+	maxValue := float64(15000)
+	rwdValidator := &models.Validator{MaxValue: &maxValue}
+	payload := &models.PayloadValidator{TransactionAmount: &trxAmount}
+
+	// when the conditional takes the else branch, unavailable loan amount is zero
+	rwdValidator.Formula = "if(loanAmount > 0, min(loanAmount * 0.01, maxValue), floor(transactionAmount / 3))"
+	assert.Equal(t, mltplFunc(float64(333333), nil), mltplFunc(rwdValidator.GetFormulaResult(payload)))
+
+	// when the conditional takes the then branch
+	payload.LoanAmount = &loanAmount
+	assert.Equal(t, mltplFunc(float64(15000), nil), mltplFunc(rwdValidator.GetFormulaResult(payload)))
+
+	// when rounding with precision
+	rwdValidator.Formula = "round(transactionAmount / 3, 2) + ceil(0.1) + max(1, 2, 3)"
+	assert.Equal(t, mltplFunc(333337.33, nil), mltplFunc(rwdValidator.GetFormulaResult(payload)))
+
+	// when the formula is not valid
+	rwdValidator.Formula = "transactionAmount * unknown"
+	_, err := rwdValidator.GetFormulaResult(payload)
+	assert.NotNil(t, err)
+	assert.NotNil(t, rwdValidator.Check())
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+var (
+	// GoldbackRoundingFloor to store goldback gram rounding down
+	GoldbackRoundingFloor = "floor"
+	// GoldbackRoundingRound to store goldback gram rounding half up
+	GoldbackRoundingRound = "round"
+	// GoldbackRoundingCeil to store goldback gram rounding up
+	GoldbackRoundingCeil = "ceil"
+
+	// GoldbackDefaultPrecision to store default number of decimal of goldback gram
+	GoldbackDefaultPrecision = 4
+
+	// GoldPriceSourceDB to store gold price source from database
+	GoldPriceSourceDB = "db"
+	// GoldPriceSourceFile to store gold price source from a local file
+	GoldPriceSourceFile = "file"
+)
+
+// GoldPrice is represent a gold price model, price is the rupiah value of one gram gold
+type GoldPrice struct {
+	ID        int64      `json:"id,omitempty"`
+	Price     float64    `json:"price,omitempty"`
+	PriceDate *time.Time `json:"priceDate,omitempty"`
+	Source    string     `json:"source,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// ToGram to convert a rupiah value into gram of gold based on the gold price
+func (gp GoldPrice) ToGram(value float64, precision int, rounding string) float64 {
+	if gp.Price <= 0 {
+		return 0
+	}
+
+	return RoundGram(value/gp.Price, precision, rounding)
+}
+
+// RoundGram to round a gram value by the precision and the rounding rule, floor is the default
+func RoundGram(gram float64, precision int, rounding string) float64 {
+	// tolerance to avoid floating point error, ex: 0.3 * 10000 = 2999.9999999999995
+	tolerance := 1e-9
+	pow := math.Pow(10, float64(precision))
+
+	switch rounding {
+	case GoldbackRoundingRound:
+		return math.Round(gram*pow) / pow
+	case GoldbackRoundingCeil:
+		return math.Ceil(gram*pow-tolerance) / pow
+	default:
+		return math.Floor(gram*pow+tolerance) / pow
+	}
+}
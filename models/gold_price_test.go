@@ -0,0 +1,35 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundGram(t *testing.T) {
+	// when its rounded down
+	assert.Equal(t, 0.1234, models.RoundGram(0.12349, 4, models.GoldbackRoundingFloor))
+	assert.Equal(t, 0.3, models.RoundGram(0.3, 4, models.GoldbackRoundingFloor))
+
+	// when its rounded half up
+	assert.Equal(t, 0.1235, models.RoundGram(0.12345, 4, models.GoldbackRoundingRound))
+
+	// when its rounded up
+	assert.Equal(t, 0.1235, models.RoundGram(0.12341, 4, models.GoldbackRoundingCeil))
+	assert.Equal(t, 0.3, models.RoundGram(0.3, 4, models.GoldbackRoundingCeil))
+
+	// when rounding rule is not available
+	assert.Equal(t, 0.12, models.RoundGram(0.129, 2, ""))
+}
+
+func TestGoldPriceToGram(t *testing.T) {
+	goldPrice := models.GoldPrice{Price: 650000}
+
+	// when its valid
+	assert.Equal(t, 0.0153, goldPrice.ToGram(10000, 4, models.GoldbackRoundingFloor))
+
+	// when gold price is not available
+	goldPrice.Price = 0
+	assert.Equal(t, float64(0), goldPrice.ToGram(10000, 4, models.GoldbackRoundingFloor))
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// HistoryRuleFirst to store first transaction history rule
+	HistoryRuleFirst = "first"
+	// HistoryRuleNth to store every nth transaction history rule
+	HistoryRuleNth = "nth"
+	// HistoryRuleCumulative to store cumulative transaction amount history rule
+	HistoryRuleCumulative = "cumulative"
+
+	// HistoryPeriodDay to store a calendar day history period
+	HistoryPeriodDay = "day"
+	// HistoryPeriodWeek to store a calendar week history period, a week starts on monday
+	HistoryPeriodWeek = "week"
+	// HistoryPeriodMonth to store a calendar month history period
+	HistoryPeriodMonth = "month"
+	// HistoryPeriodYear to store a calendar year history period
+	HistoryPeriodYear = "year"
+)
+
+// HistoryRule is represent a rule condition of a customer transaction history, the history is counting
+// every evaluated transaction before the current one, rewarded or not, see TransactionHistory.
+//
+//	{"history": {"type": "first", "product": "TE"}}
+//	{"history": {"type": "nth", "product": "GD", "every": 5}}
+//	{"history": {"type": "cumulative", "product": "TE", "period": "month", "value": 10000000}}
+type HistoryRule struct {
+	Type    string  `json:"type,omitempty"`
+	Product string  `json:"product,omitempty"`
+	Period  string  `json:"period,omitempty"`
+	Days    int64   `json:"days,omitempty"`
+	Every   int64   `json:"every,omitempty"`
+	Value   float64 `json:"value,omitempty"`
+}
+
+// HistoryQuery is represent a query of a customer transaction history within [From, Until)
+type HistoryQuery struct {
+	CIF     string
+	Product string
+	From    time.Time
+	Until   time.Time
+}
+
+// HistorySummary is represent a summary of a customer transaction history
+type HistorySummary struct {
+	Count  int64   `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+// TransactionHistory is represent a transaction_histories model, a transaction that is evaluated by an inquiry.
+// It is left out of the history once its reward transaction is rejected, timed out or reversed.
+type TransactionHistory struct {
+	ID                int64      `json:"id,omitempty"`
+	RefID             string     `json:"refId,omitempty"`
+	RefChannel        string     `json:"refChannel,omitempty"`
+	CIF               string     `json:"cif,omitempty"`
+	Product           string     `json:"product,omitempty"`
+	TransactionAmount float64    `json:"transactionAmount"`
+	TransactionDate   *time.Time `json:"transactionDate,omitempty"`
+	CreatedAt         *time.Time `json:"createdAt,omitempty"`
+}
+
+// NewTransactionHistory to get the history of an inquiry transaction that is evaluated on the ref id
+func NewTransactionHistory(payloadValidator *PayloadValidator, refID string, trxDate time.Time) *TransactionHistory {
+	trxHistory := &TransactionHistory{
+		RefID:           refID,
+		RefChannel:      payloadValidator.RefChannel,
+		CIF:             payloadValidator.CIF,
+		TransactionDate: &trxDate,
+	}
+
+	if payloadValidator.TransactionAmount != nil {
+		trxHistory.TransactionAmount = *payloadValidator.TransactionAmount
+	}
+
+	if payloadValidator.Validators != nil {
+		trxHistory.Product = payloadValidator.Validators.Product
+	}
+
+	return trxHistory
+}
+
+// HistoryProvider is the source of a customer transaction history of a history rule
+type HistoryProvider interface {
+	GetHistory(HistoryQuery) (HistorySummary, error)
+}
+
+func (hr *HistoryRule) check() error {
+	switch hr.Type {
+	case HistoryRuleFirst:
+	case HistoryRuleNth:
+		if hr.Every < 1 {
+			return fmt.Errorf("history rule %s needs every to be at least 1", hr.Type)
+		}
+	case HistoryRuleCumulative:
+		if hr.Value <= 0 {
+			return fmt.Errorf("history rule %s needs a positive value", hr.Type)
+		}
+	default:
+		return fmt.Errorf("history rule type %s is not supported", hr.Type)
+	}
+
+	switch hr.Period {
+	case "", HistoryPeriodDay, HistoryPeriodWeek, HistoryPeriodMonth, HistoryPeriodYear:
+	default:
+		return fmt.Errorf("history rule period %s is not supported", hr.Period)
+	}
+
+	if hr.Days < 0 || (hr.Days > 0 && hr.Period != "") {
+		return fmt.Errorf("history rule needs either a period or a positive days window")
+	}
+
+	return nil
+}
+
+func (hr *HistoryRule) evaluate(payloadValidator *PayloadValidator) ValidationErrors {
+	if payloadValidator.History == nil {
+		return ValidationErrors{newValidationError(ValidationCodeHistoryUnavailable, "history", hr, nil)}
+	}
+
+	trxDate, err := time.Parse(time.RFC3339, payloadValidator.TransactionDate)
+
+	if err != nil {
+		return ValidationErrors{newValidationError(ValidationCodeHistoryUnavailable, "history", hr,
+			payloadValidator.TransactionDate)}
+	}
+
+	from, until := hr.GetWindow(trxDate, GetBusinessLocation())
+	summary, err := payloadValidator.History.GetHistory(HistoryQuery{
+		CIF:     payloadValidator.CIF,
+		Product: hr.Product,
+		From:    from,
+		Until:   until,
+	})
+
+	if err != nil {
+		return ValidationErrors{newValidationError(ValidationCodeHistoryUnavailable, "history", hr, err.Error())}
+	}
+
+	if !hr.IsMatch(summary, getFloatPointer(payloadValidator.TransactionAmount)) {
+		return ValidationErrors{newValidationError(ValidationCodeHistoryNotMatch, "history", hr, summary)}
+	}
+
+	return nil
+}
+
+// IsMatch to check whether the history before the current transaction match the rule
+func (hr *HistoryRule) IsMatch(summary HistorySummary, trxAmount interface{}) bool {
+	switch hr.Type {
+	case HistoryRuleFirst:
+		return summary.Count == 0
+	case HistoryRuleNth:
+		// the current transaction is the next one of the history
+		return (summary.Count+1)%hr.Every == 0
+	case HistoryRuleCumulative:
+		amount, _ := toFloat(trxAmount)
+
+		return summary.Amount+amount >= hr.Value
+	}
+
+	return false
+}
+
+// GetWindow to get the history window of the rule before the transaction date, a rule without
+// period and days is counting the whole history
+func (hr *HistoryRule) GetWindow(trxDate time.Time, location *time.Location) (time.Time, time.Time) {
+	localDate := trxDate.In(location)
+	year, month, day := localDate.Date()
+	startOfDay := time.Date(year, month, day, 0, 0, 0, 0, location)
+
+	if hr.Days > 0 {
+		return trxDate.AddDate(0, 0, -int(hr.Days)), trxDate
+	}
+
+	switch hr.Period {
+	case HistoryPeriodDay:
+		return startOfDay, trxDate
+	case HistoryPeriodWeek:
+		// weekday of monday is 1, so sunday is the 7th day of the week
+		return startOfDay.AddDate(0, 0, -((int(localDate.Weekday()) + 6) % 7)), trxDate
+	case HistoryPeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, location), trxDate
+	case HistoryPeriodYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location), trxDate
+	}
+
+	return time.Time{}, trxDate
+}
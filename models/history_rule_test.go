@@ -0,0 +1,132 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHistory struct {
+	summary models.HistorySummary
+	queries []models.HistoryQuery
+}
+
+func (fh *fakeHistory) GetHistory(query models.HistoryQuery) (models.HistorySummary, error) {
+	fh.queries = append(fh.queries, query)
+
+	return fh.summary, nil
+}
+
+func TestHistoryRuleIsMatch(t *testing.T) {
+	first := models.HistoryRule{Type: models.HistoryRuleFirst}
+	fifth := models.HistoryRule{Type: models.HistoryRuleNth, Every: 5}
+	tenMillion := models.HistoryRule{Type: models.HistoryRuleCumulative, Value: 10000000}
+
+	assert.True(t, first.IsMatch(models.HistorySummary{}, nil))
+	assert.False(t, first.IsMatch(models.HistorySummary{Count: 1}, nil))
+	assert.True(t, fifth.IsMatch(models.HistorySummary{Count: 4}, nil))
+	assert.True(t, fifth.IsMatch(models.HistorySummary{Count: 9}, nil))
+	assert.False(t, fifth.IsMatch(models.HistorySummary{Count: 5}, nil))
+	assert.True(t, tenMillion.IsMatch(models.HistorySummary{Count: 3, Amount: 8000000}, float64(2000000)))
+	assert.False(t, tenMillion.IsMatch(models.HistorySummary{Count: 3, Amount: 8000000}, float64(1999999)))
+}
+
+func TestHistoryRuleGetWindow(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	trxDate, _ := time.Parse(time.RFC3339, "2019-08-01T02:00:00+07:00") // thursday
+	tests := []struct {
+		rule models.HistoryRule
+		from string
+	}{
+		{models.HistoryRule{Period: models.HistoryPeriodDay}, "2019-08-01T00:00:00+07:00"},
+		{models.HistoryRule{Period: models.HistoryPeriodWeek}, "2019-07-29T00:00:00+07:00"},
+		{models.HistoryRule{Period: models.HistoryPeriodMonth}, "2019-08-01T00:00:00+07:00"},
+		{models.HistoryRule{Period: models.HistoryPeriodYear}, "2019-01-01T00:00:00+07:00"},
+		{models.HistoryRule{Days: 30}, "2019-07-02T02:00:00+07:00"},
+		{models.HistoryRule{}, "0001-01-01T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		from, until := test.rule.GetWindow(trxDate, wib)
+
+		assert.Equal(t, test.from, from.Format(time.RFC3339), test.rule)
+		assert.True(t, until.Equal(trxDate))
+	}
+}
+
+func TestRuleEvaluateHistory(t *testing.T) {
+	amount := float64(2000000)
+	history := &fakeHistory{summary: models.HistorySummary{Count: 2, Amount: 9000000}}
+	payload := models.PayloadValidator{
+		CIF:               "1122334455",
+		TransactionDate:   "2019-08-01T10:00:00+07:00",
+		TransactionAmount: &amount,
+		History:           history,
+	}
+	rule := models.Rule{Version: 1, All: []models.Rule{
+		{Attribute: "transactionAmount", Operator: models.RuleOperatorGte, Value: float64(1000000)},
+		{History: &models.HistoryRule{Type: models.HistoryRuleCumulative, Product: "TE",
+			Period: models.HistoryPeriodMonth, Value: 10000000}},
+	}}
+
+	assert.NoError(t, rule.Check())
+	assert.Equal(t, mltplFunc("", nil), mltplFunc(rule.Evaluate(&payload)))
+	assert.Equal(t, "1122334455", history.queries[0].CIF)
+	assert.Equal(t, "TE", history.queries[0].Product)
+
+	// when it is not the first transaction
+	first := models.Rule{History: &models.HistoryRule{Type: models.HistoryRuleFirst}}
+	attribute, err := first.Evaluate(&payload)
+	assert.Equal(t, "history", attribute)
+	assert.Error(t, err)
+
+	// when the history is not available
+	payload.History = nil
+	attribute, err = rule.Evaluate(&payload)
+	assert.Equal(t, "history", attribute)
+	assert.Error(t, err)
+}
+
+func TestHistoryRuleCheck(t *testing.T) {
+	invalids := []models.Rule{
+		{History: &models.HistoryRule{Type: "last"}},
+		{History: &models.HistoryRule{Type: models.HistoryRuleNth}},
+		{History: &models.HistoryRule{Type: models.HistoryRuleCumulative}},
+		{History: &models.HistoryRule{Type: models.HistoryRuleFirst, Period: "quarter"}},
+		{History: &models.HistoryRule{Type: models.HistoryRuleFirst, Period: models.HistoryPeriodMonth, Days: 7}},
+		{History: &models.HistoryRule{Type: models.HistoryRuleFirst}, Attribute: "product"},
+	}
+
+	assert.NoError(t, (&models.Rule{History: &models.HistoryRule{Type: models.HistoryRuleNth, Every: 5, Days: 30}}).Check())
+
+	for _, invalid := range invalids {
+		assert.Error(t, invalid.Check(), invalid.History)
+	}
+}
+
+func TestNewTransactionHistory(t *testing.T) {
+	amount := float64(2000000)
+	trxDate := time.Now()
+	payload := models.PayloadValidator{
+		CIF:               "1122334455",
+		RefChannel:        "CH-1",
+		TransactionAmount: &amount,
+		Validators:        &models.Validator{Product: "TE"},
+	}
+	trxHistory := models.NewTransactionHistory(&payload, "REF-1", trxDate)
+
+	assert.Equal(t, "REF-1", trxHistory.RefID)
+	assert.Equal(t, "CH-1", trxHistory.RefChannel)
+	assert.Equal(t, "1122334455", trxHistory.CIF)
+	assert.Equal(t, "TE", trxHistory.Product)
+	assert.Equal(t, amount, trxHistory.TransactionAmount)
+	assert.Equal(t, trxDate, *trxHistory.TransactionDate)
+
+	// an unrewarded transaction without product and amount is still a part of the history
+	trxHistory = models.NewTransactionHistory(&models.PayloadValidator{CIF: "1122334455"}, "REF-2", trxDate)
+
+	assert.Equal(t, "", trxHistory.Product)
+	assert.Equal(t, float64(0), trxHistory.TransactionAmount)
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	// LeaderboardPeriodDay to store a leaderboard of a business day
+	LeaderboardPeriodDay = "day"
+	// LeaderboardPeriodWeek to store a leaderboard of a week that starts on monday
+	LeaderboardPeriodWeek = "week"
+	// LeaderboardPeriodMonth to store a leaderboard of a calendar month
+	LeaderboardPeriodMonth = "month"
+	// LeaderboardPeriodCampaign to store a leaderboard of a campaign from its start until its end
+	LeaderboardPeriodCampaign = "campaign"
+
+	// LeaderboardCalendarPeriods to store the periods that are computed for every campaign and product
+	LeaderboardCalendarPeriods = []string{LeaderboardPeriodDay, LeaderboardPeriodWeek, LeaderboardPeriodMonth}
+
+	leaderboardDateFormat = "2006-01-02"
+)
+
+// LeaderboardQuery to store the filters of a leaderboard, a campaign id of a calendar period
+// narrows the leaderboard to the points of that campaign
+type LeaderboardQuery struct {
+	Period     string `json:"period,omitempty" validate:"required,oneof=day week month campaign"`
+	Date       string `json:"date,omitempty"`
+	CampaignID int64  `json:"campaignId,omitempty" validate:"gte=0"`
+	Product    string `json:"product,omitempty"`
+	CIF        string `json:"cif,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+// LeaderboardWindow is represent the time window a leaderboard is computed on
+type LeaderboardWindow struct {
+	Period     string
+	Key        string
+	CampaignID int64
+	From       time.Time
+	Until      time.Time
+}
+
+// LeaderboardEntry is represent a leaderboard_entries model, the customers with the same points share the same rank
+type LeaderboardEntry struct {
+	Period       string     `json:"period,omitempty"`
+	PeriodKey    string     `json:"periodKey,omitempty"`
+	CampaignID   int64      `json:"campaignId,omitempty"`
+	Product      string     `json:"product,omitempty"`
+	CIF          string     `json:"cif,omitempty"`
+	PointAmount  int64      `json:"pointAmount"`
+	Rank         int64      `json:"rank"`
+	Participants int64      `json:"participants,omitempty"`
+	ComputedAt   *time.Time `json:"computedAt,omitempty"`
+}
+
+// GetLeaderboardWindow to get the calendar window of a period that contains the date in the business location
+func GetLeaderboardWindow(period string, date time.Time, location *time.Location) (LeaderboardWindow, error) {
+	local := date.In(location)
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	window := LeaderboardWindow{Period: period}
+
+	switch period {
+	case LeaderboardPeriodDay:
+		window.From, window.Until = from, from.AddDate(0, 0, 1)
+		window.Key = from.Format(leaderboardDateFormat)
+	case LeaderboardPeriodWeek:
+		// time.Sunday is zero, so it is moved to the end of the week
+		window.From = from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+		window.Until = window.From.AddDate(0, 0, 7)
+		year, week := window.From.ISOWeek()
+		window.Key = fmt.Sprintf("%d-W%02d", year, week)
+	case LeaderboardPeriodMonth:
+		window.From = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, location)
+		window.Until = window.From.AddDate(0, 1, 0)
+		window.Key = window.From.Format("2006-01")
+	default:
+		return window, ErrLeaderboardPeriod
+	}
+
+	return window, nil
+}
+
+// GetCampaignLeaderboardWindow to get the window of a campaign leaderboard, it only counts the points of the campaign
+func GetCampaignLeaderboardWindow(campaignID int64, startDate, endDate time.Time) LeaderboardWindow {
+	return LeaderboardWindow{
+		Period:     LeaderboardPeriodCampaign,
+		Key:        strconv.FormatInt(campaignID, 10),
+		CampaignID: campaignID,
+		From:       startDate,
+		Until:      endDate,
+	}
+}
+
+// GetLeaderboardWindows to get the calendar windows that are computed at a moment, the previous window
+// of each period is computed once more so the points posted right before it is closed are counted
+func GetLeaderboardWindows(now time.Time, location *time.Location) []LeaderboardWindow {
+	var windows []LeaderboardWindow
+
+	for _, period := range LeaderboardCalendarPeriods {
+		current, _ := GetLeaderboardWindow(period, now, location)
+		previous, _ := GetLeaderboardWindow(period, current.From.Add(-time.Nanosecond), location)
+		windows = append(windows, previous, current)
+	}
+
+	return windows
+}
+
+// GetKey to get the period key of the leaderboard that the query is looking for
+func (lq *LeaderboardQuery) GetKey(now time.Time, location *time.Location) (string, error) {
+	if lq.Period == LeaderboardPeriodCampaign {
+		if lq.CampaignID <= 0 {
+			return "", ErrLeaderboardCampaign
+		}
+
+		return strconv.FormatInt(lq.CampaignID, 10), nil
+	}
+
+	date := now
+
+	if lq.Date != "" {
+		var err error
+
+		if date, err = time.ParseInLocation(leaderboardDateFormat, lq.Date, location); err != nil {
+			return "", ErrLeaderboardDate
+		}
+	}
+
+	window, err := GetLeaderboardWindow(lq.Period, date, location)
+
+	if err != nil {
+		return "", err
+	}
+
+	return window.Key, nil
+}
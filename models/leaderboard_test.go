@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLeaderboardWindow(t *testing.T) {
+	location := time.FixedZone("WIB", 7*60*60)
+	// sunday 4 august 2019 in the business location
+	now := time.Date(2019, 8, 3, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period string
+		key    string
+		from   time.Time
+		until  time.Time
+		err    error
+	}{
+		{
+			period: models.LeaderboardPeriodDay,
+			key:    "2019-08-04",
+			from:   time.Date(2019, 8, 4, 0, 0, 0, 0, location),
+			until:  time.Date(2019, 8, 5, 0, 0, 0, 0, location),
+		},
+		{
+			period: models.LeaderboardPeriodWeek,
+			key:    "2019-W31",
+			from:   time.Date(2019, 7, 29, 0, 0, 0, 0, location),
+			until:  time.Date(2019, 8, 5, 0, 0, 0, 0, location),
+		},
+		{
+			period: models.LeaderboardPeriodMonth,
+			key:    "2019-08",
+			from:   time.Date(2019, 8, 1, 0, 0, 0, 0, location),
+			until:  time.Date(2019, 9, 1, 0, 0, 0, 0, location),
+		},
+		{period: models.LeaderboardPeriodCampaign, err: models.ErrLeaderboardPeriod},
+	}
+
+	for _, test := range tests {
+		window, err := models.GetLeaderboardWindow(test.period, now, location)
+		assert.Equal(t, test.err, err, test.period)
+
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, test.key, window.Key, test.period)
+		assert.Equal(t, test.from, window.From, test.period)
+		assert.Equal(t, test.until, window.Until, test.period)
+	}
+}
+
+func TestGetLeaderboardWindows(t *testing.T) {
+	location := time.FixedZone("WIB", 7*60*60)
+	windows := models.GetLeaderboardWindows(time.Date(2019, 8, 1, 1, 0, 0, 0, location), location)
+	var keys []string
+
+	for _, window := range windows {
+		keys = append(keys, window.Key)
+	}
+
+	assert.Equal(t, []string{"2019-07-31", "2019-08-01", "2019-W30", "2019-W31", "2019-07", "2019-08"}, keys)
+}
+
+func TestLeaderboardQueryGetKey(t *testing.T) {
+	location := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2019, 8, 9, 10, 0, 0, 0, location)
+
+	key, err := (&models.LeaderboardQuery{Period: models.LeaderboardPeriodMonth, Date: "2019-07-15"}).GetKey(now, location)
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-07", key)
+
+	key, _ = (&models.LeaderboardQuery{Period: models.LeaderboardPeriodDay}).GetKey(now, location)
+	assert.Equal(t, "2019-08-09", key)
+
+	key, _ = (&models.LeaderboardQuery{Period: models.LeaderboardPeriodCampaign, CampaignID: 12}).GetKey(now, location)
+	assert.Equal(t, "12", key)
+
+	_, err = (&models.LeaderboardQuery{Period: models.LeaderboardPeriodCampaign}).GetKey(now, location)
+	assert.Equal(t, models.ErrLeaderboardCampaign, err)
+
+	_, err = (&models.LeaderboardQuery{Period: models.LeaderboardPeriodDay, Date: "09-08-2019"}).GetKey(now, location)
+	assert.Equal(t, models.ErrLeaderboardDate, err)
+}
@@ -19,7 +19,11 @@ var errReqLogger = errors.New("Error during creating a request logger")
 
 // GetRequestLogger is to get a log parameter
 func (rl *RequestLogger) GetRequestLogger(c echo.Context, payload interface{}) *logrus.Entry {
-	rl.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	// scheduled jobs does not have any request context
+	if c != nil {
+		rl.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+
 	pl, err := json.Marshal(payload)
 
 	if err != nil {
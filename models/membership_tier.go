@@ -0,0 +1,156 @@
+package models
+
+import (
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// MembershipTierBasisPoint to store tiers that are reached by the earned points
+	MembershipTierBasisPoint = "point"
+	// MembershipTierBasisTransactionAmount to store tiers that are reached by the transaction volume
+	MembershipTierBasisTransactionAmount = "transactionAmount"
+
+	// MembershipTierMonths to store the rolling window of the tier basis and how long a tier is kept
+	MembershipTierMonths = 12
+
+	membershipTierBasis     string
+	membershipTierBasisOnce sync.Once
+)
+
+// MembershipTier is represent a membership_tiers model, a customer holds the highest tier
+// that its rolling amount reaches
+type MembershipTier struct {
+	ID         int64      `json:"id,omitempty"`
+	Code       string     `json:"code,omitempty" validate:"required"`
+	Name       string     `json:"name,omitempty" validate:"required"`
+	MinAmount  *float64   `json:"minAmount,omitempty" validate:"required,gte=0"`
+	Multiplier *float64   `json:"multiplier,omitempty" validate:"omitempty,gt=0"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+}
+
+// CustomerTier is represent a customer_tiers model with the progress to the next tier
+type CustomerTier struct {
+	CIF              string          `json:"cif,omitempty"`
+	Basis            string          `json:"basis,omitempty"`
+	Amount           float64         `json:"amount"`
+	Tier             *MembershipTier `json:"tier,omitempty"`
+	NextTier         *MembershipTier `json:"nextTier,omitempty"`
+	AmountToNextTier *float64        `json:"amountToNextTier,omitempty"`
+	QualifiedAt      *time.Time      `json:"qualifiedAt,omitempty"`
+	DowngradeDate    *time.Time      `json:"downgradeDate,omitempty"`
+	EvaluatedAt      *time.Time      `json:"evaluatedAt,omitempty"`
+}
+
+// GetMembershipTierBasis to get the tier basis from MEMBERSHIP_TIER_BASIS env, the earned points is the default
+func GetMembershipTierBasis() string {
+	membershipTierBasisOnce.Do(func() {
+		membershipTierBasis = MembershipTierBasisPoint
+
+		if os.Getenv(`MEMBERSHIP_TIER_BASIS`) == MembershipTierBasisTransactionAmount {
+			membershipTierBasis = MembershipTierBasisTransactionAmount
+		}
+	})
+
+	return membershipTierBasis
+}
+
+// GetMembershipTierWindow to get the rolling window of the tier basis that ends at a moment
+func GetMembershipTierWindow(now time.Time) (time.Time, time.Time) {
+	return now.AddDate(0, -MembershipTierMonths, 0), now
+}
+
+// GetMultiplier to get the point earning multiplier of the tier, a tier without multiplier keeps the points as is
+func (mt *MembershipTier) GetMultiplier() float64 {
+	if mt == nil || mt.Multiplier == nil || *mt.Multiplier <= 0 {
+		return 1
+	}
+
+	return *mt.Multiplier
+}
+
+// GetQualifiedTier to get the highest tier that is reached by the amount, the tiers are sorted by their minimum amount
+func GetQualifiedTier(tiers []MembershipTier, amount float64) *MembershipTier {
+	var qualified *MembershipTier
+
+	for i := range tiers {
+		if *tiers[i].MinAmount <= amount {
+			qualified = &tiers[i]
+		}
+	}
+
+	return qualified
+}
+
+// Evaluate to recompute the customer tier from the rolling amount. A higher or the same tier is
+// taken at once and kept for another period, a lower tier only replaces the current one after
+// its downgrade date has passed.
+func (ct *CustomerTier) Evaluate(tiers []MembershipTier, amount float64, now time.Time) {
+	qualified := GetQualifiedTier(tiers, amount)
+	ct.RefreshTier(tiers)
+	current := ct.Tier
+	isRetained := current != nil && ct.DowngradeDate != nil && now.Before(*ct.DowngradeDate)
+
+	if isRetained && (qualified == nil || *qualified.MinAmount < *current.MinAmount) {
+		ct.Tier = current
+	} else {
+		ct.Tier = qualified
+		ct.QualifiedAt = &now
+		ct.DowngradeDate = nil
+
+		if qualified != nil && *qualified.MinAmount > 0 {
+			downgradeDate := now.AddDate(0, MembershipTierMonths, 0)
+			ct.DowngradeDate = &downgradeDate
+		}
+	}
+
+	ct.Amount = amount
+	ct.EvaluatedAt = &now
+	ct.SetProgress(tiers)
+}
+
+// SetProgress to set the next tier above the current one and the amount left to reach it
+func (ct *CustomerTier) SetProgress(tiers []MembershipTier) {
+	ct.NextTier, ct.AmountToNextTier = nil, nil
+
+	for i := range tiers {
+		if ct.Tier != nil && *tiers[i].MinAmount <= *ct.Tier.MinAmount {
+			continue
+		}
+
+		amountToNextTier := math.Max(*tiers[i].MinAmount-ct.Amount, 0)
+		ct.NextTier = &tiers[i]
+		ct.AmountToNextTier = &amountToNextTier
+
+		return
+	}
+}
+
+// IsMembershipTierTargeted to check whether the validator targets the tier, a validator without tiers targets everyone
+func (v *Validator) IsMembershipTierTargeted(tier *MembershipTier) bool {
+	if v == nil || len(v.MembershipTiers) == 0 {
+		return true
+	}
+
+	return tier != nil && contains(v.MembershipTiers, tier.Code)
+}
+
+// RefreshTier to replace the stored tier with its latest setting, a removed tier is no longer held
+func (ct *CustomerTier) RefreshTier(tiers []MembershipTier) {
+	if ct.Tier == nil {
+		return
+	}
+
+	for i := range tiers {
+		if tiers[i].ID == ct.Tier.ID {
+			ct.Tier = &tiers[i]
+
+			return
+		}
+	}
+
+	ct.Tier = nil
+}
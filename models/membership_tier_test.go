@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMembershipTiers() []models.MembershipTier {
+	silver, gold, platinum, multiplier := float64(0), float64(1000), float64(5000), float64(1.5)
+
+	return []models.MembershipTier{
+		{ID: 1, Code: "silver", MinAmount: &silver},
+		{ID: 2, Code: "gold", MinAmount: &gold, Multiplier: &multiplier},
+		{ID: 3, Code: "platinum", MinAmount: &platinum},
+	}
+}
+
+func TestCustomerTierEvaluate(t *testing.T) {
+	tiers := getMembershipTiers()
+	now := time.Date(2019, 8, 12, 10, 0, 0, 0, time.UTC)
+	customerTier := &models.CustomerTier{CIF: "1011111111"}
+
+	customerTier.Evaluate(tiers, 500, now)
+	assert.Equal(t, "silver", customerTier.Tier.Code)
+	assert.Nil(t, customerTier.DowngradeDate)
+	assert.Equal(t, "gold", customerTier.NextTier.Code)
+	assert.Equal(t, float64(500), *customerTier.AmountToNextTier)
+	assert.Equal(t, float64(1), customerTier.Tier.GetMultiplier())
+
+	// an upgrade is taken at once and kept for a year
+	customerTier.Evaluate(tiers, 1200, now)
+	assert.Equal(t, "gold", customerTier.Tier.Code)
+	assert.Equal(t, now.AddDate(1, 0, 0), *customerTier.DowngradeDate)
+	assert.Equal(t, float64(3800), *customerTier.AmountToNextTier)
+	assert.Equal(t, float64(1.5), customerTier.Tier.GetMultiplier())
+
+	// a lower amount keeps the tier until the downgrade date
+	customerTier.Evaluate(tiers, 200, now.AddDate(0, 6, 0))
+	assert.Equal(t, "gold", customerTier.Tier.Code)
+	assert.Equal(t, now.AddDate(1, 0, 0), *customerTier.DowngradeDate)
+	assert.Equal(t, "platinum", customerTier.NextTier.Code)
+
+	customerTier.Evaluate(tiers, 200, now.AddDate(1, 0, 0))
+	assert.Equal(t, "silver", customerTier.Tier.Code)
+	assert.Nil(t, customerTier.DowngradeDate)
+
+	customerTier.Evaluate(tiers, 6000, now)
+	assert.Equal(t, "platinum", customerTier.Tier.Code)
+	assert.Nil(t, customerTier.NextTier)
+	assert.Nil(t, customerTier.AmountToNextTier)
+}
+
+func TestValidatorIsMembershipTierTargeted(t *testing.T) {
+	tiers := getMembershipTiers()
+	validator := &models.Validator{MembershipTiers: []string{"gold", "platinum"}}
+
+	assert.True(t, validator.IsMembershipTierTargeted(&tiers[1]))
+	assert.False(t, validator.IsMembershipTierTargeted(&tiers[0]))
+	assert.False(t, validator.IsMembershipTierTargeted(nil))
+	assert.True(t, (&models.Validator{}).IsMembershipTierTargeted(nil))
+}
+
+func TestGetQualifiedTier(t *testing.T) {
+	tiers := getMembershipTiers()
+
+	tests := []struct {
+		amount float64
+		tier   string
+	}{
+		{amount: -1, tier: ""},
+		{amount: 0, tier: "silver"},
+		{amount: 999, tier: "silver"},
+		{amount: 1000, tier: "gold"},
+		{amount: 4999.5, tier: "gold"},
+		{amount: 5000, tier: "platinum"},
+	}
+
+	for _, test := range tests {
+		tier := models.GetQualifiedTier(tiers, test.amount)
+
+		if test.tier == "" {
+			assert.Nil(t, tier, "amount %v", test.amount)
+
+			continue
+		}
+
+		if assert.NotNil(t, tier, "amount %v", test.amount) {
+			assert.Equal(t, test.tier, tier.Code, "amount %v", test.amount)
+		}
+	}
+}
+
+func TestCustomerTierRefreshTier(t *testing.T) {
+	tiers := getMembershipTiers()
+	oldGold := float64(2000)
+
+	tests := []struct {
+		tier     *models.MembershipTier
+		expected *models.MembershipTier
+	}{
+		{tier: nil, expected: nil},
+		{tier: &models.MembershipTier{ID: 2, Code: "gold", MinAmount: &oldGold}, expected: &tiers[1]},
+		{tier: &models.MembershipTier{ID: 9, Code: "removed", MinAmount: &oldGold}, expected: nil},
+	}
+
+	for i, test := range tests {
+		customerTier := &models.CustomerTier{Tier: test.tier}
+		customerTier.RefreshTier(tiers)
+
+		assert.Equal(t, test.expected, customerTier.Tier, "case %d", i)
+	}
+}
@@ -15,6 +15,9 @@ var (
 	// MessageUpdateSuccess to store a success message response of update
 	MessageUpdateSuccess = "Successfully Updated"
 
+	// MessageDeleteSuccess to store a success message response of delete
+	MessageDeleteSuccess = "Successfully Deleted"
+
 	// MessageUploadSuccess to store a success message response of upload
 	MessageUploadSuccess = "Successfully Upload"
 
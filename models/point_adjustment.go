@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// PointAdjustmentPending to store status of an adjustment that is waiting for a review
+	PointAdjustmentPending int64
+	// PointAdjustmentApproved to store status of an approved and posted adjustment
+	PointAdjustmentApproved int64 = 1
+	// PointAdjustmentRejected to store status of a rejected adjustment
+	PointAdjustmentRejected int64 = 2
+
+	// PointAdjustmentActionSubmit to store audit action of a submitted adjustment
+	PointAdjustmentActionSubmit = "submit"
+	// PointAdjustmentActionApprove to store audit action of an approved adjustment
+	PointAdjustmentActionApprove = "approve"
+	// PointAdjustmentActionReject to store audit action of a rejected adjustment
+	PointAdjustmentActionReject = "reject"
+
+	// PointUsedForAdjustment to store used for of a point history that is posted by an adjustment
+	PointUsedForAdjustment = "adjustment"
+)
+
+// PointAdjustment is represent a point_adjustments model, transaction type D adds points
+// to the customer and K deducts them
+type PointAdjustment struct {
+	ID              int64                `json:"id,omitempty"`
+	CIF             string               `json:"cif,omitempty" validate:"required"`
+	TransactionType string               `json:"transactionType,omitempty" validate:"required,oneof=D K"`
+	PointAmount     *float64             `json:"pointAmount,omitempty" validate:"required,gt=0"`
+	Reason          string               `json:"reason,omitempty" validate:"required"`
+	TicketRef       string               `json:"ticketRef,omitempty" validate:"required"`
+	Status          *int64               `json:"status,omitempty"`
+	CreatedBy       string               `json:"createdBy,omitempty"`
+	ReviewedBy      string               `json:"reviewedBy,omitempty"`
+	ReviewNote      string               `json:"reviewNote,omitempty"`
+	ReviewedAt      *time.Time           `json:"reviewedAt,omitempty"`
+	PointHistoryID  *int64               `json:"pointHistoryId,omitempty"`
+	Logs            []PointAdjustmentLog `json:"logs,omitempty"`
+	UpdatedAt       *time.Time           `json:"updatedAt,omitempty"`
+	CreatedAt       *time.Time           `json:"createdAt,omitempty"`
+}
+
+// PointAdjustmentLog is represent a point_adjustment_logs model, an audit trail of an adjustment
+type PointAdjustmentLog struct {
+	ID        int64      `json:"id,omitempty"`
+	Action    string     `json:"action,omitempty"`
+	Actor     string     `json:"actor,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// PayloadPointAdjustmentReview to store a payload to approve or reject an adjustment
+type PayloadPointAdjustmentReview struct {
+	Note string `json:"note,omitempty"`
+}
+
+// Review to check whether the reviewer is allowed to review the adjustment, the maker is never its checker
+func (pa *PointAdjustment) Review(reviewer string) error {
+	if pa.Status == nil || *pa.Status != PointAdjustmentPending {
+		return ErrAdjustmentReviewed
+	}
+
+	if pa.CreatedBy == reviewer {
+		return ErrAdjustmentSelfReview
+	}
+
+	return nil
+}
+
+// GetPointHistory to get the point history that is posted by an approved adjustment, the ticket stays
+// on the adjustment that refers to the point history, ref core is only for the core references
+func (pa *PointAdjustment) GetPointHistory(now time.Time) (*PointHistory, error) {
+	if pa.PointAmount == nil || *pa.PointAmount <= 0 {
+		return nil, errors.New("adjustment point amount should be a positive value")
+	}
+
+	pointAmount := *pa.PointAmount
+	pointHistory := &PointHistory{
+		CIF:             pa.CIF,
+		PointAmount:     &pointAmount,
+		TransactionType: pa.TransactionType,
+		TransactionDate: &now,
+		UsedFor:         PointUsedForAdjustment,
+		Status:          &PointHistoryStatusSuccess,
+	}
+
+	// the credited points follow the global expiry rule as if they are earned at approval
+	if pa.TransactionType == TransactionPointTypeDebet {
+		pointHistory.ExpiredDate = GetDefaultPointExpiry().GetExpiredDate(now, GetBusinessLocation())
+	}
+
+	return pointHistory, nil
+}
@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointAdjustmentReview(t *testing.T) {
+	adjustment := models.PointAdjustment{CreatedBy: "maker", Status: &models.PointAdjustmentPending}
+
+	assert.NoError(t, adjustment.Review("checker"))
+	assert.Equal(t, models.ErrAdjustmentSelfReview, adjustment.Review("maker"))
+
+	adjustment.Status = &models.PointAdjustmentApproved
+	assert.Equal(t, models.ErrAdjustmentReviewed, adjustment.Review("checker"))
+}
+
+func TestPointAdjustmentGetPointHistory(t *testing.T) {
+	now := time.Now()
+	amount := float64(250)
+	credit := models.PointAdjustment{
+		CIF: "1011234567", TransactionType: models.TransactionPointTypeDebet, PointAmount: &amount, TicketRef: "TCK-1",
+	}
+
+	pointHistory, err := credit.GetPointHistory(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointUsedForAdjustment, pointHistory.UsedFor)
+	assert.Empty(t, pointHistory.RefCore)
+	assert.NotNil(t, pointHistory.ExpiredDate)
+
+	journal, err := models.NewPointJournal(pointHistory)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointJournalAdjust, journal.JournalType)
+	assert.Equal(t, []models.PointEntry{
+		{AccountCode: "1011234567", AccountType: models.PointAccountTypeCustomer, Amount: 250},
+		{AccountCode: models.PointAccountAdjustment, AccountType: models.PointAccountTypeSystem, Amount: -250},
+	}, journal.Entries)
+
+	debit := credit
+	debit.TransactionType = models.TransactionPointTypeKredit
+	pointHistory, err = debit.GetPointHistory(now)
+
+	assert.NoError(t, err)
+	assert.Nil(t, pointHistory.ExpiredDate)
+
+	journal, _ = models.NewPointJournal(pointHistory)
+	assert.Equal(t, int64(-250), journal.Entries[0].Amount)
+
+	_, err = (&models.PointAdjustment{TransactionType: models.TransactionPointTypeDebet}).GetPointHistory(now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// PointExpiryMonths to store an expiry of N months after the points are earned
+	PointExpiryMonths = "months"
+	// PointExpiryEndOfYear to store an expiry at the end of the Nth year after the points are earned,
+	// 0 is the end of the same year and 1 is the end of the following year
+	PointExpiryEndOfYear = "endOfYear"
+	// PointExpiryNever to store points that never expire
+	PointExpiryNever = "never"
+
+	// PointUsedForExpiry to store used for of a point history that expires points
+	PointUsedForExpiry = "expiry"
+
+	defaultPointExpiry     *PointExpiry
+	defaultPointExpiryOnce sync.Once
+)
+
+// PointExpiry is represent an expiry rule of earned points
+//
+//	{"type": "months", "value": 6}
+//	{"type": "endOfYear", "value": 1}
+type PointExpiry struct {
+	Type  string `json:"type,omitempty"`
+	Value int64  `json:"value,omitempty"`
+}
+
+// PointLot is represent the points earned by a journal and how many of them are left,
+// the spent points are taken from the oldest lot first
+type PointLot struct {
+	JournalID       int64
+	Amount          int64
+	Remaining       int64
+	TransactionDate *time.Time
+	ExpiredDate     *time.Time
+}
+
+// PointExpiration is represent the amount of points that expire on a date
+type PointExpiration struct {
+	PointAmount int64      `json:"pointAmount"`
+	ExpiredDate *time.Time `json:"expiredDate"`
+}
+
+// UserPointExpiry to store the upcoming point expirations of a user
+type UserPointExpiry struct {
+	CIF         string            `json:"cif,omitempty"`
+	Expirations []PointExpiration `json:"expirations"`
+}
+
+// GetDefaultPointExpiry to get the global expiry rule from POINT_EXPIRY_TYPE and POINT_EXPIRY_VALUE env,
+// the points expire at the end of the following year by default
+func GetDefaultPointExpiry() *PointExpiry {
+	defaultPointExpiryOnce.Do(func() {
+		expiry := &PointExpiry{Type: os.Getenv(`POINT_EXPIRY_TYPE`)}
+		expiry.Value, _ = strconv.ParseInt(os.Getenv(`POINT_EXPIRY_VALUE`), 10, 64)
+
+		if expiry.Type == "" || expiry.Check() != nil {
+			expiry = &PointExpiry{Type: PointExpiryEndOfYear, Value: 1}
+		}
+
+		defaultPointExpiry = expiry
+	})
+
+	return defaultPointExpiry
+}
+
+// Check to check whether the point expiry is well formed
+func (pe *PointExpiry) Check() error {
+	switch pe.Type {
+	case PointExpiryMonths:
+		if pe.Value < 1 {
+			return fmt.Errorf("point expiry %s needs a value of at least 1", pe.Type)
+		}
+	case PointExpiryEndOfYear:
+		if pe.Value < 0 {
+			return fmt.Errorf("point expiry %s needs a value of at least 0", pe.Type)
+		}
+	case PointExpiryNever:
+	default:
+		return fmt.Errorf("point expiry type %s is not supported", pe.Type)
+	}
+
+	return nil
+}
+
+// GetExpiredDate to get the moment the points earned on the date expire, nil means never
+func (pe *PointExpiry) GetExpiredDate(earnedDate time.Time, location *time.Location) *time.Time {
+	var expiredDate time.Time
+
+	switch pe.Type {
+	case PointExpiryMonths:
+		expiredDate = earnedDate.In(location).AddDate(0, int(pe.Value), 0)
+	case PointExpiryEndOfYear:
+		expiredDate = time.Date(earnedDate.In(location).Year()+int(pe.Value)+1, time.January, 1, 0, 0, 0, 0, location)
+	default:
+		return nil
+	}
+
+	return &expiredDate
+}
+
+// SpendPointLots to get the remaining points of each lot after the spent points are taken from the oldest lot,
+// the lots should be ordered from the oldest one
+func SpendPointLots(lots []PointLot, spent int64) []PointLot {
+	result := make([]PointLot, len(lots))
+
+	for i, lot := range lots {
+		taken := lot.Amount
+
+		if spent < taken {
+			taken = spent
+		}
+
+		if taken < 0 {
+			taken = 0
+		}
+
+		lot.Remaining = lot.Amount - taken
+		spent -= taken
+		result[i] = lot
+	}
+
+	return result
+}
+
+// TakePointLots to get the portions of the remaining lots that are taken by the points from the oldest lot
+// and the lots that are left after it. The points that are not covered by the lots are returned as a lot without
+// a journal
+func TakePointLots(lots []PointLot, pointAmount int64) ([]PointLot, []PointLot) {
+	var taken, left []PointLot
+
+	for _, lot := range lots {
+		portion := lot.Remaining
+
+		if pointAmount < portion {
+			portion = pointAmount
+		}
+
+		if portion > 0 {
+			takenLot := lot
+			takenLot.Amount = portion
+			takenLot.Remaining = portion
+			taken = append(taken, takenLot)
+			lot.Remaining -= portion
+			pointAmount -= portion
+		}
+
+		if lot.Remaining > 0 {
+			left = append(left, lot)
+		}
+	}
+
+	if pointAmount > 0 {
+		taken = append(taken, PointLot{Amount: pointAmount, Remaining: pointAmount})
+	}
+
+	return taken, left
+}
+
+// GetExpiredPoint to get the remaining points of the lots that are expired at the moment
+func GetExpiredPoint(lots []PointLot, now time.Time) int64 {
+	var expired int64
+
+	for _, lot := range lots {
+		if lot.ExpiredDate != nil && !lot.ExpiredDate.After(now) {
+			expired += lot.Remaining
+		}
+	}
+
+	return expired
+}
+
+// GetPointExpirations to get the remaining points of the lots grouped by their expired date
+func GetPointExpirations(lots []PointLot) []PointExpiration {
+	expirations := []PointExpiration{}
+
+	for _, lot := range lots {
+		if lot.ExpiredDate == nil || lot.Remaining <= 0 {
+			continue
+		}
+
+		i := sort.Search(len(expirations), func(i int) bool {
+			return !expirations[i].ExpiredDate.Before(*lot.ExpiredDate)
+		})
+
+		if i < len(expirations) && expirations[i].ExpiredDate.Equal(*lot.ExpiredDate) {
+			expirations[i].PointAmount += lot.Remaining
+
+			continue
+		}
+
+		expiredDate := *lot.ExpiredDate
+		expirations = append(expirations, PointExpiration{})
+		copy(expirations[i+1:], expirations[i:])
+		expirations[i] = PointExpiration{PointAmount: lot.Remaining, ExpiredDate: &expiredDate}
+	}
+
+	return expirations
+}
@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointExpiryGetExpiredDate(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	earnedDate, _ := time.Parse(time.RFC3339, "2019-12-31T20:00:00Z") // 1 january 2020 in WIB
+	tests := []struct {
+		expiry   models.PointExpiry
+		expected string
+	}{
+		{models.PointExpiry{Type: models.PointExpiryMonths, Value: 6}, "2020-06-30T20:00:00Z"},
+		{models.PointExpiry{Type: models.PointExpiryEndOfYear}, "2021-01-01T00:00:00+07:00"},
+		{models.PointExpiry{Type: models.PointExpiryEndOfYear, Value: 1}, "2022-01-01T00:00:00+07:00"},
+	}
+
+	for _, test := range tests {
+		expected, _ := time.Parse(time.RFC3339, test.expected)
+		expiredDate := test.expiry.GetExpiredDate(earnedDate, wib)
+
+		assert.NotNil(t, expiredDate)
+		assert.True(t, expected.Equal(*expiredDate), test.expiry.Type)
+	}
+
+	never := models.PointExpiry{Type: models.PointExpiryNever}
+	assert.Nil(t, never.GetExpiredDate(earnedDate, wib))
+}
+
+func TestPointExpiryCheck(t *testing.T) {
+	assert.NoError(t, (&models.PointExpiry{Type: models.PointExpiryMonths, Value: 12}).Check())
+	assert.NoError(t, (&models.PointExpiry{Type: models.PointExpiryEndOfYear}).Check())
+	assert.NoError(t, (&models.PointExpiry{Type: models.PointExpiryNever}).Check())
+	assert.Error(t, (&models.PointExpiry{Type: models.PointExpiryMonths}).Check())
+	assert.Error(t, (&models.PointExpiry{Type: models.PointExpiryEndOfYear, Value: -1}).Check())
+	assert.Error(t, (&models.PointExpiry{Type: "weeks", Value: 2}).Check())
+}
+
+func TestSpendPointLots(t *testing.T) {
+	january := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)
+	lots := []models.PointLot{
+		{JournalID: 1, Amount: 100, ExpiredDate: &january},
+		{JournalID: 2, Amount: 50, ExpiredDate: &february},
+		{JournalID: 3, Amount: 30, ExpiredDate: &january},
+		{JournalID: 4, Amount: 20},
+	}
+
+	spent := models.SpendPointLots(lots, 120)
+
+	assert.Equal(t, []int64{0, 30, 30, 20}, []int64{spent[0].Remaining, spent[1].Remaining, spent[2].Remaining,
+		spent[3].Remaining})
+	assert.Equal(t, int64(0), lots[0].Remaining, "the lots should not be changed")
+
+	assert.Equal(t, int64(30), models.GetExpiredPoint(spent, january))
+	assert.Equal(t, int64(60), models.GetExpiredPoint(spent, february))
+	assert.Equal(t, int64(0), models.GetExpiredPoint(spent, january.Add(-time.Second)))
+
+	expirations := models.GetPointExpirations(spent)
+
+	assert.Len(t, expirations, 2)
+	assert.Equal(t, int64(30), expirations[0].PointAmount)
+	assert.True(t, january.Equal(*expirations[0].ExpiredDate))
+	assert.Equal(t, int64(30), expirations[1].PointAmount)
+	assert.True(t, february.Equal(*expirations[1].ExpiredDate))
+
+	assert.Empty(t, models.GetPointExpirations(models.SpendPointLots(lots, 500)))
+}
+
+func TestTakePointLots(t *testing.T) {
+	january := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)
+	lots := []models.PointLot{
+		{JournalID: 1, Amount: 100, Remaining: 0, ExpiredDate: &january},
+		{JournalID: 2, Amount: 50, Remaining: 30, ExpiredDate: &january},
+		{JournalID: 3, Amount: 40, Remaining: 40, ExpiredDate: &february},
+	}
+
+	tests := []struct {
+		pointAmount int64
+		taken       []int64
+		journals    []int64
+		left        []int64
+	}{
+		{pointAmount: 20, taken: []int64{20}, journals: []int64{2}, left: []int64{10, 40}},
+		{pointAmount: 30, taken: []int64{30}, journals: []int64{2}, left: []int64{40}},
+		{pointAmount: 50, taken: []int64{30, 20}, journals: []int64{2, 3}, left: []int64{20}},
+		{pointAmount: 90, taken: []int64{30, 40, 20}, journals: []int64{2, 3, 0}, left: []int64{}},
+	}
+
+	for _, test := range tests {
+		taken, left := models.TakePointLots(lots, test.pointAmount)
+		amounts, journals, remaining := []int64{}, []int64{}, []int64{}
+
+		for _, lot := range taken {
+			amounts = append(amounts, lot.Amount)
+			journals = append(journals, lot.JournalID)
+		}
+
+		for _, lot := range left {
+			remaining = append(remaining, lot.Remaining)
+		}
+
+		assert.Equal(t, test.taken, amounts, "points %d", test.pointAmount)
+		assert.Equal(t, test.journals, journals, "points %d", test.pointAmount)
+		assert.Equal(t, test.left, remaining, "points %d", test.pointAmount)
+	}
+
+	assert.Equal(t, int64(30), lots[1].Remaining, "the lots should not be changed")
+}
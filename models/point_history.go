@@ -9,7 +9,7 @@ var (
 	TransactionPointTypeKredit = "K"
 	// PointHistoryStatusPending to status pending point history; nil means zero
 	PointHistoryStatusPending int64
-	// PointHistoryStatusSuccess to status success point history
+	// PointHistoryStatusSuccess to status success point history, only this status is spendable
 	PointHistoryStatusSuccess int64 = 1
 )
 
@@ -24,13 +24,16 @@ type PointHistory struct {
 	RefCore         string       `json:"refCore,omitempty"`
 	RefID           string       `json:"refID,omitempty"`
 	Status          *int64       `json:"status,omitempty"`
+	ExpiredDate     *time.Time   `json:"expiredDate,omitempty"`
 	Reward          *Reward      `json:"reward,omitempty"`
 	VoucherCode     *VoucherCode `json:"voucherCode,omitempty"`
+	AllowNegative   bool         `json:"-"`
 	UpdatedAt       *time.Time   `json:"updatedAt,omitempty"`
 	CreatedAt       *time.Time   `json:"createdAt,omitempty"`
 }
 
 // UserPoint to store payload user point data
 type UserPoint struct {
-	UserPoint *float64 `json:"userPoint,omitempty"`
+	UserPoint    *float64 `json:"userPoint,omitempty"`
+	PendingPoint *float64 `json:"pendingPoint,omitempty"`
 }
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// PointAccountTypeCustomer to store customer point account type, the account code is the cif
+	PointAccountTypeCustomer int64
+	// PointAccountTypeSystem to store system point account type
+	PointAccountTypeSystem int64 = 1
+
+	// PointAccountReward to store the system account that issues the reward points
+	PointAccountReward = "SYSTEM-REWARD"
+	// PointAccountVoucher to store the system account that receives the points of bought vouchers
+	PointAccountVoucher = "SYSTEM-VOUCHER"
+	// PointAccountExpiry to store the system account that receives the expired points
+	PointAccountExpiry = "SYSTEM-EXPIRY"
+	// PointAccountAdjustment to store the system account of the manual point adjustments
+	PointAccountAdjustment = "SYSTEM-ADJUSTMENT"
+	// PointAccountTransfer to store the system account that clears the points transferred between customers
+	PointAccountTransfer = "SYSTEM-TRANSFER"
+	// PointAccountTransferFee to store the system account that receives the transfer fees
+	PointAccountTransferFee = "SYSTEM-TRANSFER-FEE"
+
+	// PointJournalEarn to store earn point journal type
+	PointJournalEarn = "earn"
+	// PointJournalRedeem to store redeem point journal type
+	PointJournalRedeem = "redeem"
+	// PointJournalExpire to store expire point journal type
+	PointJournalExpire = "expire"
+	// PointJournalAdjust to store adjust point journal type
+	PointJournalAdjust = "adjust"
+	// PointJournalReverse to store reverse point journal type
+	PointJournalReverse = "reverse"
+	// PointJournalTransfer to store transfer point journal type
+	PointJournalTransfer = "transfer"
+	// PointJournalTransferFee to store transfer fee point journal type
+	PointJournalTransferFee = "transferFee"
+
+	// PointJournalSourceHistory to store point histories journal source
+	PointJournalSourceHistory = "point_histories"
+)
+
+// PointJournal is represent a point_journals model, a journal is a set of balanced entries
+type PointJournal struct {
+	ID              int64        `json:"id,omitempty"`
+	JournalType     string       `json:"journalType,omitempty"`
+	Source          string       `json:"source,omitempty"`
+	SourceID        int64        `json:"sourceId,omitempty"`
+	RefID           string       `json:"refId,omitempty"`
+	RefCore         string       `json:"refCore,omitempty"`
+	TransactionDate *time.Time   `json:"transactionDate,omitempty"`
+	ExpiredDate     *time.Time   `json:"expiredDate,omitempty"`
+	Entries         []PointEntry `json:"entries,omitempty"`
+	AllowNegative   bool         `json:"-"`
+	CreatedAt       *time.Time   `json:"createdAt,omitempty"`
+}
+
+// PointEntry is represent a point_entries model, a positive amount adds points to the account
+type PointEntry struct {
+	ID          int64  `json:"id,omitempty"`
+	AccountCode string `json:"accountCode,omitempty"`
+	AccountType int64  `json:"accountType"`
+	Amount      int64  `json:"amount"`
+}
+
+// NewPointJournal to get the journal that posts a point history between the customer and a system account
+func NewPointJournal(pointHistory *PointHistory) (*PointJournal, error) {
+	if pointHistory.PointAmount == nil || *pointHistory.PointAmount <= 0 {
+		return nil, errors.New("point amount should be a positive value")
+	}
+
+	var counterAccount string
+	amount := int64(*pointHistory.PointAmount)
+	journal := &PointJournal{
+		Source:          PointJournalSourceHistory,
+		SourceID:        pointHistory.ID,
+		RefID:           pointHistory.RefID,
+		RefCore:         pointHistory.RefCore,
+		TransactionDate: pointHistory.TransactionDate,
+		AllowNegative:   pointHistory.AllowNegative,
+	}
+
+	// the customer account moves the other way around of its counter account
+	switch pointHistory.TransactionType {
+	case TransactionPointTypeDebet:
+		journal.ExpiredDate = pointHistory.ExpiredDate
+	case TransactionPointTypeKredit:
+		amount = -amount
+	default:
+		return nil, errors.New("transaction type " + pointHistory.TransactionType + " is not supported")
+	}
+
+	journal.JournalType, counterAccount = getPointJournalType(pointHistory)
+	journal.Entries = []PointEntry{
+		{AccountCode: pointHistory.CIF, AccountType: PointAccountTypeCustomer, Amount: amount},
+		{AccountCode: counterAccount, AccountType: PointAccountTypeSystem, Amount: -amount},
+	}
+
+	return journal, nil
+}
+
+// getPointJournalType to get the journal type of a point history and its system counter account
+func getPointJournalType(pointHistory *PointHistory) (string, string) {
+	switch {
+	case pointHistory.UsedFor == PointUsedForAdjustment:
+		return PointJournalAdjust, PointAccountAdjustment
+	case pointHistory.UsedFor == PointUsedForExpiry:
+		return PointJournalExpire, PointAccountExpiry
+	case pointHistory.UsedFor == PointUsedForReversal:
+		return PointJournalReverse, PointAccountReward
+	case pointHistory.UsedFor == PointUsedForTransfer:
+		return PointJournalTransfer, PointAccountTransfer
+	case pointHistory.UsedFor == PointUsedForTransferFee:
+		return PointJournalTransferFee, PointAccountTransferFee
+	case pointHistory.TransactionType == TransactionPointTypeDebet:
+		return PointJournalEarn, PointAccountReward
+	}
+
+	return PointJournalRedeem, PointAccountVoucher
+}
+
+// IsBalanced to check whether the entries of the journal sum up to zero
+func (pj *PointJournal) IsBalanced() bool {
+	var total int64
+
+	for _, entry := range pj.Entries {
+		total += entry.Amount
+	}
+
+	return len(pj.Entries) > 0 && total == 0
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPointJournal(t *testing.T) {
+	amount := float64(150)
+	earn, err := models.NewPointJournal(&models.PointHistory{
+		ID: 7, CIF: "1011234567", PointAmount: &amount, TransactionType: models.TransactionPointTypeDebet,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointJournalEarn, earn.JournalType)
+	assert.Equal(t, int64(7), earn.SourceID)
+	assert.True(t, earn.IsBalanced())
+	assert.Equal(t, []models.PointEntry{
+		{AccountCode: "1011234567", AccountType: models.PointAccountTypeCustomer, Amount: 150},
+		{AccountCode: models.PointAccountReward, AccountType: models.PointAccountTypeSystem, Amount: -150},
+	}, earn.Entries)
+
+	redeem, err := models.NewPointJournal(&models.PointHistory{
+		CIF: "1011234567", PointAmount: &amount, TransactionType: models.TransactionPointTypeKredit,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointJournalRedeem, redeem.JournalType)
+	assert.True(t, redeem.IsBalanced())
+	assert.Equal(t, []models.PointEntry{
+		{AccountCode: "1011234567", AccountType: models.PointAccountTypeCustomer, Amount: -150},
+		{AccountCode: models.PointAccountVoucher, AccountType: models.PointAccountTypeSystem, Amount: 150},
+	}, redeem.Entries)
+}
+
+func TestNewPointJournalExpiry(t *testing.T) {
+	amount := float64(40)
+	expire, err := models.NewPointJournal(&models.PointHistory{
+		CIF: "1011234567", PointAmount: &amount, TransactionType: models.TransactionPointTypeKredit,
+		UsedFor: models.PointUsedForExpiry,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointJournalExpire, expire.JournalType)
+	assert.True(t, expire.IsBalanced())
+	assert.Equal(t, models.PointAccountExpiry, expire.Entries[1].AccountCode)
+	assert.Equal(t, int64(-40), expire.Entries[0].Amount)
+}
+
+func TestNewPointJournalInvalid(t *testing.T) {
+	zero := float64(0)
+	amount := float64(10)
+
+	_, err := models.NewPointJournal(&models.PointHistory{TransactionType: models.TransactionPointTypeDebet})
+	assert.Error(t, err)
+
+	_, err = models.NewPointJournal(&models.PointHistory{PointAmount: &zero, TransactionType: models.TransactionPointTypeDebet})
+	assert.Error(t, err)
+
+	_, err = models.NewPointJournal(&models.PointHistory{PointAmount: &amount, TransactionType: "X"})
+	assert.Error(t, err)
+
+	unbalanced := models.PointJournal{Entries: []models.PointEntry{{Amount: 10}, {Amount: -9}}}
+	assert.False(t, unbalanced.IsBalanced())
+	assert.False(t, (&models.PointJournal{}).IsBalanced())
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	// PointUsedForReversal to store used for of a point history that takes back reversed points
+	PointUsedForReversal = "reversal"
+
+	// reversalTolerance to store the tolerance of a float ratio before it is considered whole
+	reversalTolerance = 1e-9
+)
+
+// PayloadRewardReversal to store a payload to reverse the rewards of a core transaction, a reversal
+// without reversed amount is reversing the rest of the transaction
+type PayloadRewardReversal struct {
+	CIF            string   `json:"cif,omitempty" validate:"required"`
+	RefCore        string   `json:"refCore,omitempty" validate:"required"`
+	RefReversal    string   `json:"refReversal,omitempty" validate:"required"`
+	ReversedAmount *float64 `json:"reversedAmount,omitempty" validate:"omitempty,gt=0"`
+}
+
+// PointReversal is represent a point_reversals model
+type PointReversal struct {
+	ID             int64            `json:"id,omitempty"`
+	RefReversal    string           `json:"refReversal,omitempty"`
+	RefCore        string           `json:"refCore,omitempty"`
+	CIF            string           `json:"cif,omitempty"`
+	ReversedAmount *float64         `json:"reversedAmount,omitempty"`
+	Ratio          float64          `json:"ratio"`
+	PointAmount    float64          `json:"pointAmount"`
+	PointShortfall float64          `json:"pointShortfall"`
+	Rewards        []RewardReversal `json:"rewards,omitempty"`
+	VoidedVouchers []string         `json:"voidedVouchers,omitempty"`
+	CreatedAt      *time.Time       `json:"createdAt,omitempty"`
+}
+
+// RewardReversal is represent the benefit that is taken back from a reward transaction
+type RewardReversal struct {
+	RewardTrxID   int64    `json:"-"`
+	RefID         string   `json:"-"`
+	RewardID      *int64   `json:"rewardId,omitempty"`
+	Type          string   `json:"type,omitempty"`
+	Value         float64  `json:"value"`
+	Gram          *float64 `json:"gram,omitempty"`
+	VoucherName   string   `json:"voucherName,omitempty"`
+	VoidVoucher   bool     `json:"voidVoucher"`
+	PreviousRatio float64  `json:"-"`
+	ReversedRatio float64  `json:"reversedRatio"`
+}
+
+// IsNegativeBalanceAllowed to check whether a reversal may take the customer point below zero
+// from POINT_REVERSAL_NEGATIVE_BALANCE env
+func IsNegativeBalanceAllowed() bool {
+	allowed, _ := strconv.ParseBool(os.Getenv(`POINT_REVERSAL_NEGATIVE_BALANCE`))
+
+	return allowed
+}
+
+// GetReversalRatio to get the ratio of a transaction that is reversed, it never exceeds what is left of the transaction
+func GetReversalRatio(trxAmount, reversedAmount *float64, reversedRatio float64) (float64, error) {
+	remaining := 1 - reversedRatio
+
+	if remaining <= reversalTolerance {
+		return 0, ErrReversalExceeded
+	}
+
+	if reversedAmount == nil {
+		return remaining, nil
+	}
+
+	if trxAmount == nil || *trxAmount <= 0 {
+		return 0, ErrReversalAmount
+	}
+
+	ratio := *reversedAmount / *trxAmount
+
+	if ratio > remaining+reversalTolerance {
+		return 0, ErrReversalExceeded
+	}
+
+	return math.Min(ratio, remaining), nil
+}
+
+// NewRewardReversal to get the benefit that is taken back when a ratio of the reward transaction is reversed,
+// the points are rounded on the cumulative ratio so all reversals add up to the earned points
+func NewRewardReversal(rewardTrx RewardTrx, ratio float64) (RewardReversal, error) {
+	var rwdResponse RewardResponse
+
+	if err := json.Unmarshal([]byte(rewardTrx.ResponseData), &rwdResponse); err != nil {
+		return RewardReversal{}, err
+	}
+
+	reversedRatio := rewardTrx.ReversedRatio + ratio
+
+	if reversedRatio >= 1-reversalTolerance {
+		reversedRatio = 1
+	}
+
+	reversal := RewardReversal{
+		RewardTrxID:   rewardTrx.ID,
+		RefID:         rewardTrx.RefID,
+		RewardID:      rewardTrx.RewardID,
+		Type:          rwdResponse.Type,
+		Value:         rwdResponse.Value * ratio,
+		VoucherName:   rwdResponse.VoucherName,
+		VoidVoucher:   rwdResponse.VoucherName != "" && reversedRatio == 1,
+		PreviousRatio: rewardTrx.ReversedRatio,
+		ReversedRatio: reversedRatio,
+	}
+
+	if rwdResponse.Gram != nil {
+		gram := *rwdResponse.Gram * ratio
+		reversal.Gram = &gram
+	}
+
+	rwdType := rwdResponse.GetRewardType()
+
+	if rwdType != nil && *rwdType == RewardTypePoint {
+		earned := math.Floor(rwdResponse.Value)
+		reversal.Value = getReversedPoint(earned, reversedRatio) - getReversedPoint(earned, reversal.PreviousRatio)
+	}
+
+	return reversal, nil
+}
+
+// GetPointHistory to get the point history that takes back the reversed points, it is nil when no point is reversed
+func (pr *PointReversal) GetPointHistory(now time.Time) *PointHistory {
+	var pointAmount float64
+
+	for _, reward := range pr.Rewards {
+		rwdType := RewardResponse{Type: reward.Type}.GetRewardType()
+
+		if rwdType != nil && *rwdType == RewardTypePoint {
+			pointAmount += reward.Value
+		}
+	}
+
+	if pointAmount <= 0 {
+		return nil
+	}
+
+	return &PointHistory{
+		CIF:             pr.CIF,
+		PointAmount:     &pointAmount,
+		TransactionType: TransactionPointTypeKredit,
+		TransactionDate: &now,
+		UsedFor:         PointUsedForReversal,
+		RefCore:         pr.RefCore,
+		RefID:           pr.RefReversal,
+		Status:          &PointHistoryStatusSuccess,
+		AllowNegative:   IsNegativeBalanceAllowed(),
+	}
+}
+
+// SetPointBalance to cap the reversed points on the customer balance when a negative balance is not allowed,
+// the points that could not be taken back are kept as the shortfall of the reversal
+func (pr *PointReversal) SetPointBalance(pointHistory *PointHistory, balance float64) {
+	pr.PointAmount = *pointHistory.PointAmount
+	pr.PointShortfall = 0
+
+	if pointHistory.AllowNegative || pr.PointAmount <= balance {
+		return
+	}
+
+	pr.PointAmount = math.Max(balance, 0)
+	pr.PointShortfall = *pointHistory.PointAmount - pr.PointAmount
+	pointAmount := pr.PointAmount
+	pointHistory.PointAmount = &pointAmount
+}
+
+func getReversedPoint(earned, ratio float64) float64 {
+	if ratio >= 1 {
+		return earned
+	}
+
+	return math.Floor(earned*ratio + reversalTolerance)
+}
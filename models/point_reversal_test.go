@@ -0,0 +1,93 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReversalRatio(t *testing.T) {
+	trxAmount := float64(1000000)
+	partial := float64(300000)
+	exceeded := float64(800000)
+
+	ratio, err := models.GetReversalRatio(&trxAmount, &partial, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.3, ratio, 1e-12)
+
+	ratio, err = models.GetReversalRatio(&trxAmount, nil, 0.3)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.7, ratio, 1e-12)
+
+	_, err = models.GetReversalRatio(&trxAmount, &exceeded, 0.3)
+	assert.Equal(t, models.ErrReversalExceeded, err)
+
+	_, err = models.GetReversalRatio(&trxAmount, nil, 1)
+	assert.Equal(t, models.ErrReversalExceeded, err)
+
+	_, err = models.GetReversalRatio(nil, &partial, 0)
+	assert.Equal(t, models.ErrReversalAmount, err)
+}
+
+func TestNewRewardReversal(t *testing.T) {
+	rewardID := int64(7)
+	pointTrx := models.RewardTrx{ID: 1, RefID: "REF1", RewardID: &rewardID, ResponseData: `{"type":"point","value":99}`}
+
+	// the partial reversals of the points always add up to the earned points
+	first, err := models.NewRewardReversal(pointTrx, 0.29)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(28), first.Value)
+	assert.Equal(t, 0.29, first.ReversedRatio)
+
+	pointTrx.ReversedRatio = first.ReversedRatio
+	rest, err := models.NewRewardReversal(pointTrx, 0.71)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(71), rest.Value)
+	assert.Equal(t, float64(1), rest.ReversedRatio)
+
+	voucherTrx := models.RewardTrx{ID: 2, RefID: "REF1", ResponseData: `{"type":"voucher","voucherName":"Diskon"}`}
+	partial, err := models.NewRewardReversal(voucherTrx, 0.5)
+	assert.NoError(t, err)
+	assert.False(t, partial.VoidVoucher)
+
+	full, err := models.NewRewardReversal(voucherTrx, 1)
+	assert.NoError(t, err)
+	assert.True(t, full.VoidVoucher)
+
+	_, err = models.NewRewardReversal(models.RewardTrx{ResponseData: `{`}, 1)
+	assert.Error(t, err)
+}
+
+func TestPointReversalGetPointHistory(t *testing.T) {
+	reversal := models.PointReversal{
+		CIF: "1011234567", RefCore: "CORE1", RefReversal: "REV1",
+		Rewards: []models.RewardReversal{{Type: "point", Value: 60}, {Type: "discount", Value: 5000}},
+	}
+
+	pointHistory := reversal.GetPointHistory(time.Now())
+	assert.Equal(t, float64(60), *pointHistory.PointAmount)
+	assert.Equal(t, models.TransactionPointTypeKredit, pointHistory.TransactionType)
+	assert.Equal(t, "REV1", pointHistory.RefID)
+
+	journal, err := models.NewPointJournal(pointHistory)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PointJournalReverse, journal.JournalType)
+	assert.Equal(t, models.PointAccountReward, journal.Entries[1].AccountCode)
+
+	// a negative balance is not allowed, so only the remaining balance is taken back
+	pointHistory.AllowNegative = false
+	reversal.SetPointBalance(pointHistory, 45)
+	assert.Equal(t, float64(45), *pointHistory.PointAmount)
+	assert.Equal(t, float64(45), reversal.PointAmount)
+	assert.Equal(t, float64(15), reversal.PointShortfall)
+
+	allowed := reversal.GetPointHistory(time.Now())
+	allowed.AllowNegative = true
+	reversal.SetPointBalance(allowed, 45)
+	assert.Equal(t, float64(60), reversal.PointAmount)
+	assert.Equal(t, float64(0), reversal.PointShortfall)
+
+	assert.Nil(t, (&models.PointReversal{Rewards: []models.RewardReversal{{Type: "discount", Value: 5000}}}).GetPointHistory(time.Now()))
+}
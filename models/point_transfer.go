@@ -0,0 +1,239 @@
+package models
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// PointTransferSucceeded to store status of a transfer that has moved the points
+	PointTransferSucceeded int64
+	// PointTransferReversed to store status of a transfer that has been reversed by an admin
+	PointTransferReversed int64 = 1
+
+	// PointUsedForTransfer to store used for of a point history that sends or receives transferred points
+	PointUsedForTransfer = "transfer"
+	// PointUsedForTransferFee to store used for of a point history that charges the transfer fee
+	PointUsedForTransferFee = "transferFee"
+
+	pointTransferConfig     *PointTransferConfig
+	pointTransferConfigOnce sync.Once
+)
+
+// PayloadPointTransfer to store a payload to send points to another customer, ref id is the reference
+// of the channel so a retried transfer is never sent twice
+type PayloadPointTransfer struct {
+	RefID        string   `json:"refId,omitempty" validate:"required"`
+	CIF          string   `json:"cif,omitempty" validate:"required"`
+	RecipientCIF string   `json:"recipientCif,omitempty" validate:"required,nefield=CIF"`
+	PointAmount  *float64 `json:"pointAmount,omitempty" validate:"required,gt=0"`
+	Note         string   `json:"note,omitempty"`
+}
+
+// PayloadPointTransferReversal to store a payload of an admin to reverse a transfer
+type PayloadPointTransferReversal struct {
+	Note string `json:"note,omitempty" validate:"required"`
+}
+
+// PointTransferRecipient is represent a point_transfer_recipients model, the allowed recipients of a customer
+type PointTransferRecipient struct {
+	ID           int64      `json:"id,omitempty"`
+	CIF          string     `json:"cif,omitempty" validate:"required"`
+	RecipientCIF string     `json:"recipientCif,omitempty" validate:"required,nefield=CIF"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+}
+
+// PointTransfer is represent a point_transfers model
+type PointTransfer struct {
+	ID           int64      `json:"id,omitempty"`
+	RefID        string     `json:"refId,omitempty"`
+	CIF          string     `json:"cif,omitempty"`
+	RecipientCIF string     `json:"recipientCif,omitempty"`
+	PointAmount  *float64   `json:"pointAmount,omitempty"`
+	Fee          *float64   `json:"fee,omitempty"`
+	Note         string     `json:"note,omitempty"`
+	Status       *int64     `json:"status,omitempty"`
+	ReversedBy   string     `json:"reversedBy,omitempty"`
+	ReversalNote string     `json:"reversalNote,omitempty"`
+	ReversedAt   *time.Time `json:"reversedAt,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	Lots         []PointLot `json:"-"`
+}
+
+// PointTransferConfig is represent the limits of the point transfers, a zero limit is not checked
+type PointTransferConfig struct {
+	MinAmount  float64
+	MaxAmount  float64
+	DailyLimit float64
+	Fee        float64
+	AllowList  bool
+}
+
+// GetPointTransferConfig to get the point transfer limits from POINT_TRANSFER_MIN, POINT_TRANSFER_MAX,
+// POINT_TRANSFER_DAILY_LIMIT, POINT_TRANSFER_FEE and POINT_TRANSFER_ALLOW_LIST env
+func GetPointTransferConfig() *PointTransferConfig {
+	pointTransferConfigOnce.Do(func() {
+		config := &PointTransferConfig{}
+		config.MinAmount, _ = strconv.ParseFloat(os.Getenv(`POINT_TRANSFER_MIN`), 64)
+		config.MaxAmount, _ = strconv.ParseFloat(os.Getenv(`POINT_TRANSFER_MAX`), 64)
+		config.DailyLimit, _ = strconv.ParseFloat(os.Getenv(`POINT_TRANSFER_DAILY_LIMIT`), 64)
+		config.Fee, _ = strconv.ParseFloat(os.Getenv(`POINT_TRANSFER_FEE`), 64)
+		config.AllowList, _ = strconv.ParseBool(os.Getenv(`POINT_TRANSFER_ALLOW_LIST`))
+		pointTransferConfig = config
+	})
+
+	return pointTransferConfig
+}
+
+// CheckAmount to check whether the transferred points are within the minimum and maximum amount
+func (ptc *PointTransferConfig) CheckAmount(pointAmount float64) error {
+	if pointAmount <= 0 || (ptc.MinAmount > 0 && pointAmount < ptc.MinAmount) ||
+		(ptc.MaxAmount > 0 && pointAmount > ptc.MaxAmount) {
+		return ErrTransferAmount
+	}
+
+	return nil
+}
+
+// CheckDailyLimit to check whether the transferred points are within what is left of the sender daily limit
+func (ptc *PointTransferConfig) CheckDailyLimit(transferred, pointAmount float64) error {
+	if ptc.DailyLimit > 0 && transferred+pointAmount > ptc.DailyLimit {
+		return ErrTransferDailyLimit
+	}
+
+	return nil
+}
+
+// GetTransferDay to get the start and the end of the business day of a transfer, the daily limit is counted within it
+func GetTransferDay(now time.Time, location *time.Location) (time.Time, time.Time) {
+	local := now.In(location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	return start, start.AddDate(0, 0, 1)
+}
+
+// NewPointTransfer to get a transfer of the payload that is charged with the configured fee
+func NewPointTransfer(plTransfer *PayloadPointTransfer, config *PointTransferConfig) *PointTransfer {
+	pointAmount := float64(int64(*plTransfer.PointAmount))
+	fee := float64(int64(config.Fee))
+
+	return &PointTransfer{
+		RefID:        plTransfer.RefID,
+		CIF:          plTransfer.CIF,
+		RecipientCIF: plTransfer.RecipientCIF,
+		PointAmount:  &pointAmount,
+		Fee:          &fee,
+		Note:         plTransfer.Note,
+		Status:       &PointTransferSucceeded,
+	}
+}
+
+// GetPointHistories to get the paired point histories of a transfer, the sender is debited and the recipient
+// is credited with the same points. The points are taken from the remaining lots of the sender from the oldest one,
+// so the recipient is credited per lot with the expiry of it. The fee is charged to the sender apart and keeps
+// the expiry of the lots it is taken from, so a reversal gives it back as it was
+func (pt *PointTransfer) GetPointHistories(now time.Time) ([]*PointHistory, error) {
+	if pt.PointAmount == nil || *pt.PointAmount <= 0 {
+		return nil, errors.New("transfer point amount should be a positive value")
+	}
+
+	lots, left := TakePointLots(pt.Lots, int64(*pt.PointAmount))
+	pointHistories := []*PointHistory{
+		pt.getPointHistory(pt.CIF, *pt.PointAmount, TransactionPointTypeKredit, PointUsedForTransfer, now),
+	}
+
+	for _, lot := range lots {
+		pointHistory := pt.getPointHistory(pt.RecipientCIF, float64(lot.Amount), TransactionPointTypeDebet,
+			PointUsedForTransfer, now)
+		pointHistory.ExpiredDate = getLotExpiredDate(lot, now)
+		pointHistories = append(pointHistories, pointHistory)
+	}
+
+	if pt.Fee == nil || *pt.Fee <= 0 {
+		return pointHistories, nil
+	}
+
+	lots, _ = TakePointLots(left, int64(*pt.Fee))
+
+	for _, lot := range lots {
+		pointHistory := pt.getPointHistory(pt.CIF, float64(lot.Amount), TransactionPointTypeKredit,
+			PointUsedForTransferFee, now)
+		pointHistory.ExpiredDate = getLotExpiredDate(lot, now)
+		pointHistories = append(pointHistories, pointHistory)
+	}
+
+	return pointHistories, nil
+}
+
+// GetReversalHistories to get the point histories that return the points of a reversed transfer from its stored
+// point histories, the recipient gives back every received lot and the sender gets it back with the same expiry
+// along with the fee. The recipient may only go below zero when the reversal policy allows it
+func (pt *PointTransfer) GetReversalHistories(pointHistories []*PointHistory, now time.Time) ([]*PointHistory, error) {
+	var reversalHistories, returnedHistories []*PointHistory
+
+	for _, pointHistory := range pointHistories {
+		if pointHistory.PointAmount == nil || *pointHistory.PointAmount <= 0 {
+			continue
+		}
+
+		received := pointHistory.CIF == pt.RecipientCIF && pointHistory.UsedFor == PointUsedForTransfer &&
+			pointHistory.TransactionType == TransactionPointTypeDebet
+		charged := pointHistory.CIF == pt.CIF && pointHistory.UsedFor == PointUsedForTransferFee &&
+			pointHistory.TransactionType == TransactionPointTypeKredit
+
+		if !received && !charged {
+			continue
+		}
+
+		if received {
+			returned := pt.getPointHistory(pt.RecipientCIF, *pointHistory.PointAmount, TransactionPointTypeKredit,
+				PointUsedForTransfer, now)
+			returned.AllowNegative = IsNegativeBalanceAllowed()
+			reversalHistories = append(reversalHistories, returned)
+		}
+
+		returned := pt.getPointHistory(pt.CIF, *pointHistory.PointAmount, TransactionPointTypeDebet,
+			pointHistory.UsedFor, now)
+		returned.ExpiredDate = pointHistory.ExpiredDate
+
+		// a fee that is charged before its lots were recorded follows the global expiry rule
+		if returned.ExpiredDate == nil && pointHistory.UsedFor == PointUsedForTransferFee {
+			returned.ExpiredDate = GetDefaultPointExpiry().GetExpiredDate(now, GetBusinessLocation())
+		}
+
+		returnedHistories = append(returnedHistories, returned)
+	}
+
+	if len(reversalHistories) == 0 {
+		return nil, errors.New("transfer has no received points to reverse")
+	}
+
+	return append(reversalHistories, returnedHistories...), nil
+}
+
+func (pt *PointTransfer) getPointHistory(CIF string, pointAmount float64, trxType, usedFor string,
+	now time.Time) *PointHistory {
+	return &PointHistory{
+		CIF:             CIF,
+		PointAmount:     &pointAmount,
+		TransactionType: trxType,
+		TransactionDate: &now,
+		UsedFor:         usedFor,
+		RefID:           pt.RefID,
+		Status:          &PointHistoryStatusSuccess,
+	}
+}
+
+// getLotExpiredDate to get the expiry of the points taken from a lot, the points that are not covered
+// by a lot of the sender follow the global expiry rule as if they are earned at the transfer
+func getLotExpiredDate(lot PointLot, now time.Time) *time.Time {
+	if lot.JournalID == 0 {
+		return GetDefaultPointExpiry().GetExpiredDate(now, GetBusinessLocation())
+	}
+
+	return lot.ExpiredDate
+}
@@ -0,0 +1,146 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointTransferConfig(t *testing.T) {
+	config := models.PointTransferConfig{MinAmount: 10, MaxAmount: 1000, DailyLimit: 1500}
+
+	assert.NoError(t, config.CheckAmount(10))
+	assert.Equal(t, models.ErrTransferAmount, config.CheckAmount(9))
+	assert.Equal(t, models.ErrTransferAmount, config.CheckAmount(1001))
+
+	assert.NoError(t, config.CheckDailyLimit(1000, 500))
+	assert.Equal(t, models.ErrTransferDailyLimit, config.CheckDailyLimit(1000, 501))
+
+	unlimited := models.PointTransferConfig{}
+	assert.NoError(t, unlimited.CheckAmount(1000000))
+	assert.NoError(t, unlimited.CheckDailyLimit(1000000, 1000000))
+}
+
+func TestGetTransferDay(t *testing.T) {
+	location := time.FixedZone("WIB", 7*60*60)
+	from, until := models.GetTransferDay(time.Date(2019, 8, 7, 18, 30, 0, 0, time.UTC), location)
+
+	assert.Equal(t, time.Date(2019, 8, 8, 0, 0, 0, 0, location), from)
+	assert.Equal(t, time.Date(2019, 8, 9, 0, 0, 0, 0, location), until)
+}
+
+func TestPointTransferGetPointHistories(t *testing.T) {
+	now := time.Now()
+	amount := float64(100.6)
+	transfer := models.NewPointTransfer(&models.PayloadPointTransfer{
+		RefID: "TRF1", CIF: "1011111111", RecipientCIF: "1012222222", PointAmount: &amount,
+	}, &models.PointTransferConfig{Fee: 5})
+
+	pointHistories, err := transfer.GetPointHistories(now)
+	assert.NoError(t, err)
+	assert.Len(t, pointHistories, 3)
+
+	// the sender pays the points and the fee, the recipient receives the points only
+	balances := map[string]int64{}
+
+	for _, pointHistory := range pointHistories {
+		journal, err := models.NewPointJournal(pointHistory)
+		assert.NoError(t, err)
+		balances[journal.Entries[0].AccountCode] += journal.Entries[0].Amount
+		balances[journal.Entries[1].AccountCode] += journal.Entries[1].Amount
+	}
+
+	assert.Equal(t, map[string]int64{
+		"1011111111": -105, "1012222222": 100, models.PointAccountTransfer: 0, models.PointAccountTransferFee: 5,
+	}, balances)
+	assert.NotNil(t, pointHistories[1].ExpiredDate)
+
+	reversalHistories, err := transfer.GetReversalHistories(pointHistories, now)
+	assert.NoError(t, err)
+
+	for _, pointHistory := range reversalHistories {
+		journal, _ := models.NewPointJournal(pointHistory)
+		balances[journal.Entries[0].AccountCode] += journal.Entries[0].Amount
+		balances[journal.Entries[1].AccountCode] += journal.Entries[1].Amount
+	}
+
+	assert.Equal(t, map[string]int64{
+		"1011111111": 0, "1012222222": 0, models.PointAccountTransfer: 0, models.PointAccountTransferFee: 0,
+	}, balances)
+	assert.Nil(t, reversalHistories[0].ExpiredDate)
+	assert.NotNil(t, reversalHistories[1].ExpiredDate)
+
+	_, err = transfer.GetReversalHistories(nil, now)
+	assert.Error(t, err)
+}
+
+func TestPointTransferKeepsLotExpiry(t *testing.T) {
+	now := time.Date(2020, time.January, 15, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+	amount := float64(100)
+	transfer := models.NewPointTransfer(&models.PayloadPointTransfer{
+		RefID: "TRF2", CIF: "1011111111", RecipientCIF: "1012222222", PointAmount: &amount,
+	}, &models.PointTransferConfig{Fee: 10})
+	transfer.Lots = []models.PointLot{
+		{JournalID: 1, Amount: 80, Remaining: 60, ExpiredDate: &march},
+		{JournalID: 2, Amount: 50, Remaining: 50, ExpiredDate: &june},
+	}
+
+	pointHistories, err := transfer.GetPointHistories(now)
+	assert.NoError(t, err)
+
+	// the recipient is credited per lot of the sender and the fee is taken from what is left of them
+	tests := []struct {
+		CIF         string
+		trxType     string
+		usedFor     string
+		pointAmount float64
+		expiredDate *time.Time
+	}{
+		{"1011111111", models.TransactionPointTypeKredit, models.PointUsedForTransfer, 100, nil},
+		{"1012222222", models.TransactionPointTypeDebet, models.PointUsedForTransfer, 60, &march},
+		{"1012222222", models.TransactionPointTypeDebet, models.PointUsedForTransfer, 40, &june},
+		{"1011111111", models.TransactionPointTypeKredit, models.PointUsedForTransferFee, 10, &june},
+	}
+
+	assert.Len(t, pointHistories, len(tests))
+
+	for i, test := range tests {
+		assert.Equal(t, test.CIF, pointHistories[i].CIF, "history %d", i)
+		assert.Equal(t, test.trxType, pointHistories[i].TransactionType, "history %d", i)
+		assert.Equal(t, test.usedFor, pointHistories[i].UsedFor, "history %d", i)
+		assert.Equal(t, test.pointAmount, *pointHistories[i].PointAmount, "history %d", i)
+		assert.Equal(t, test.expiredDate, pointHistories[i].ExpiredDate, "history %d", i)
+	}
+
+	// the sender gets back every lot and the fee with the expiry they had
+	reversalHistories, err := transfer.GetReversalHistories(pointHistories, now)
+	assert.NoError(t, err)
+
+	tests = []struct {
+		CIF         string
+		trxType     string
+		usedFor     string
+		pointAmount float64
+		expiredDate *time.Time
+	}{
+		{"1012222222", models.TransactionPointTypeKredit, models.PointUsedForTransfer, 60, nil},
+		{"1012222222", models.TransactionPointTypeKredit, models.PointUsedForTransfer, 40, nil},
+		{"1011111111", models.TransactionPointTypeDebet, models.PointUsedForTransfer, 60, &march},
+		{"1011111111", models.TransactionPointTypeDebet, models.PointUsedForTransfer, 40, &june},
+		{"1011111111", models.TransactionPointTypeDebet, models.PointUsedForTransferFee, 10, &june},
+	}
+
+	assert.Len(t, reversalHistories, len(tests))
+
+	for i, test := range tests {
+		assert.Equal(t, test.CIF, reversalHistories[i].CIF, "reversal %d", i)
+		assert.Equal(t, test.trxType, reversalHistories[i].TransactionType, "reversal %d", i)
+		assert.Equal(t, test.usedFor, reversalHistories[i].UsedFor, "reversal %d", i)
+		assert.Equal(t, test.pointAmount, *reversalHistories[i].PointAmount, "reversal %d", i)
+		assert.Equal(t, test.expiredDate, reversalHistories[i].ExpiredDate, "reversal %d", i)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 )
 
@@ -9,6 +10,11 @@ var (
 	IsPerUserFalse int64
 	// IsPerUserTrue to store is per user true
 	IsPerUserTrue int64 = 1
+
+	// QuotaPeriodRolling to store quota rolling window period
+	QuotaPeriodRolling int64
+	// QuotaPeriodCalendar to store quota calendar window period
+	QuotaPeriodCalendar int64 = 1
 )
 
 // Quota is represent a quota model
@@ -17,8 +23,49 @@ type Quota struct {
 	NumberOfDays *int64     `json:"numberOfDays,omitempty"`
 	Amount       *int64     `json:"amount,omitempty"`
 	IsPerUser    *int64     `json:"isPerUser,omitempty"`
+	PeriodType   *int64     `json:"periodType,omitempty"`
 	Available    *int64     `json:"available,omitempty"`
 	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
 	CreatedAt    *time.Time `json:"createdAt,omitempty"`
 	Reward       *Reward    `json:"reward,omitempty"`
 }
+
+// GetWindow to get the start and the end of the quota window for the given transaction date.
+// A quota without number of days is counted for the whole reward lifetime, a rolling window
+// looks back N days from the transaction date and a calendar window splits the time into
+// N days blocks started from the day the quota was created. Nil end means an open window.
+func (quot Quota) GetWindow(trxDate time.Time) (time.Time, *time.Time) {
+	if quot.NumberOfDays == nil || *quot.NumberOfDays <= 0 {
+		return time.Time{}, nil
+	}
+
+	days := int(*quot.NumberOfDays)
+
+	if quot.PeriodType == nil || *quot.PeriodType != QuotaPeriodCalendar {
+		return trxDate.AddDate(0, 0, -days), nil
+	}
+
+	anchor := time.Unix(0, 0).In(trxDate.Location())
+
+	if quot.CreatedAt != nil {
+		anchor = *quot.CreatedAt
+	}
+
+	start := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, trxDate.Location())
+	elapsed := int(math.Floor(trxDate.Sub(start).Hours() / 24))
+	block := elapsed / days
+
+	if elapsed%days < 0 {
+		block--
+	}
+
+	start = start.AddDate(0, 0, block*days)
+	end := start.AddDate(0, 0, days)
+
+	return start, &end
+}
+
+// IsUserQuota to check whether the quota is counted for each user
+func (quot Quota) IsUserQuota() bool {
+	return quot.IsPerUser != nil && *quot.IsPerUser == IsPerUserTrue
+}
@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaGetWindow(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 10, 0, 0, 0, time.UTC)
+	trxDate := time.Date(2019, 7, 16, 8, 30, 0, 0, time.UTC)
+	days := int64(7)
+	quota := models.Quota{
+		NumberOfDays: &days,
+		PeriodType:   &models.QuotaPeriodRolling,
+		CreatedAt:    &createdAt,
+	}
+
+	// when its a rolling window
+	start, end := quota.GetWindow(trxDate)
+	assert.Equal(t, time.Date(2019, 7, 9, 8, 30, 0, 0, time.UTC), start)
+	assert.Nil(t, end)
+
+	// when its a calendar window
+	quota.PeriodType = &models.QuotaPeriodCalendar
+	start, end = quota.GetWindow(trxDate)
+	assert.Equal(t, time.Date(2019, 7, 15, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 7, 22, 0, 0, 0, 0, time.UTC), *end)
+
+	// when the transaction date is before the quota created
+	start, end = quota.GetWindow(time.Date(2019, 6, 30, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2019, 6, 24, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), *end)
+
+	// when number of days is not available
+	zero := int64(0)
+	quota.NumberOfDays = &zero
+	start, end = quota.GetWindow(trxDate)
+	assert.True(t, start.IsZero())
+	assert.Nil(t, end)
+}
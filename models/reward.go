@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -14,6 +15,11 @@ var (
 	// RewardTypeVoucher to store reward type voucher
 	RewardTypeVoucher int64 = 3
 
+	// RewardStatusInactive to store reward status inactive
+	RewardStatusInactive int8
+	// RewardStatusActive to store reward status active
+	RewardStatusActive int8 = 1
+
 	// IsPromoCodeFalse to store is promo code false
 	IsPromoCodeFalse int64
 	// IsPromoCodeTrue to store is promo code true
@@ -29,24 +35,27 @@ var rewardType = map[int64]string{
 
 // Reward is represent a reward model
 type Reward struct {
-	ID                 int64      `json:"id,omitempty"`
-	Name               string     `json:"name,omitempty"`
-	Description        string     `json:"description,omitempty"`
-	TermsAndConditions string     `json:"termsAndConditions,omitempty"`
-	HowToUse           string     `json:"howToUse,omitempty"`
-	PromoCode          string     `json:"promoCode,omitempty"`
-	CustomPeriod       string     `json:"customPeriod,omitempty"`
-	JournalAccount     string     `json:"journalAccount,omitempty"`
-	IsPromoCode        *int64     `json:"isPromoCode,omitempty"`
-	Type               *int64     `json:"type,omitempty"`
-	CampaignID         *int64     `json:"campaign_id,omitempty"`
-	Validators         *Validator `json:"validators,omitempty"`
-	UpdatedAt          *time.Time `json:"updatedAt,omitempty"`
-	CreatedAt          *time.Time `json:"createdAt,omitempty"`
-	Campaign           *Campaign  `json:"campaign,omitempty"`
-	Quotas             *[]Quota   `json:"quotas,omitempty"`
-	Tags               *[]Tag     `json:"tags,omitempty"`
-	Vouchers           *[]Voucher `json:"vouchers,omitempty"`
+	ID                 int64         `json:"id,omitempty"`
+	Name               string        `json:"name,omitempty"`
+	Description        string        `json:"description,omitempty"`
+	TermsAndConditions string        `json:"termsAndConditions,omitempty"`
+	HowToUse           string        `json:"howToUse,omitempty"`
+	PromoCode          string        `json:"promoCode,omitempty"`
+	CustomPeriod       *CustomPeriod `json:"customPeriod,omitempty"`
+	PointExpiry        *PointExpiry  `json:"pointExpiry,omitempty"`
+	JournalAccount     string        `json:"journalAccount,omitempty"`
+	IsPromoCode        *int64        `json:"isPromoCode,omitempty"`
+	Priority           *int64        `json:"priority,omitempty"`
+	Status             *int8         `json:"status,omitempty"`
+	Type               *int64        `json:"type,omitempty"`
+	CampaignID         *int64        `json:"campaign_id,omitempty"`
+	Validators         *Validator    `json:"validators,omitempty"`
+	UpdatedAt          *time.Time    `json:"updatedAt,omitempty"`
+	CreatedAt          *time.Time    `json:"createdAt,omitempty"`
+	Campaign           *Campaign     `json:"campaign,omitempty"`
+	Quotas             *[]Quota      `json:"quotas,omitempty"`
+	Tags               *[]Tag        `json:"tags,omitempty"`
+	Vouchers           *[]Voucher    `json:"vouchers,omitempty"`
 }
 
 // RewardsInquiry is represent a inquire reward response model
@@ -57,13 +66,101 @@ type RewardsInquiry struct {
 
 // RewardResponse is represent a reward response model
 type RewardResponse struct {
-	Type           string  `json:"type,omitempty"`
-	JournalAccount string  `json:"journalAccount,omitempty"`
-	Value          float64 `json:"value,omitempty"`
-	VoucherName    string  `json:"voucherName,omitempty"`
+	Type           string     `json:"type,omitempty"`
+	JournalAccount string     `json:"journalAccount,omitempty"`
+	Value          float64    `json:"value,omitempty"`
+	VoucherName    string     `json:"voucherName,omitempty"`
+	Gram           *float64   `json:"gram,omitempty"`
+	GoldPrice      *GoldPrice `json:"goldPrice,omitempty"`
+	ExpiredDate    *time.Time `json:"expiredDate,omitempty"`
+	RewardID       *int64     `json:"-"`
+}
+
+// PayloadRewardSimulation to store a payload to simulate a rewards inquiry
+type PayloadRewardSimulation struct {
+	PayloadValidator
+	AsOfDate string `json:"asOfDate,omitempty"`
+}
+
+// RewardSimulation is represent a simulated reward eligibility model
+type RewardSimulation struct {
+	CampaignID     int64   `json:"campaignId"`
+	CampaignName   string  `json:"campaignName,omitempty"`
+	StackingPolicy string  `json:"stackingPolicy,omitempty"`
+	RewardID       int64   `json:"rewardId"`
+	RewardName     string  `json:"rewardName,omitempty"`
+	PromoCodeMatch bool    `json:"promoCodeMatch"`
+	Eligible       bool    `json:"eligible"`
+	FailedRule     string  `json:"failedRule,omitempty"`
+	Reason         string  `json:"reason,omitempty"`
+	Value          float64 `json:"value"`
+	Selected       bool    `json:"selected"`
 }
 
 // GetRewardTypeText to get text of reward type
 func (rwd Reward) GetRewardTypeText() string {
 	return rewardType[*rwd.Type]
 }
+
+// IsActive to check whether the reward is active, a reward without status is active
+func (rwd Reward) IsActive() bool {
+	return rwd.Status == nil || *rwd.Status != RewardStatusInactive
+}
+
+// GetPointExpiry to get the expiry rule of the reward points, the global rule is used when it has none
+func (rwd Reward) GetPointExpiry() *PointExpiry {
+	if rwd.PointExpiry == nil {
+		return GetDefaultPointExpiry()
+	}
+
+	return rwd.PointExpiry
+}
+
+// IsInPeriod to check whether the transaction date is inside the reward custom period if any
+func (rwd Reward) IsInPeriod(trxDate time.Time) bool {
+	if rwd.CustomPeriod == nil {
+		return true
+	}
+
+	return rwd.CustomPeriod.IsActive(trxDate, GetBusinessLocation())
+}
+
+// IsBenefitChanged to check whether the updated reward changes the fields that define its benefit
+func (rwd Reward) IsBenefitChanged(updated Reward) bool {
+	if rwd.JournalAccount != updated.JournalAccount || rwd.PromoCode != updated.PromoCode {
+		return true
+	}
+
+	if (rwd.IsPromoCode == nil) != (updated.IsPromoCode == nil) {
+		return true
+	}
+
+	if rwd.IsPromoCode != nil && *rwd.IsPromoCode != *updated.IsPromoCode {
+		return true
+	}
+
+	validator, _ := json.Marshal(rwd.Validators)
+	updatedValidator, _ := json.Marshal(updated.Validators)
+
+	return string(validator) != string(updatedValidator)
+}
+
+// GetPriority to get the reward priority, the lower number is evaluated first
+func (rwd Reward) GetPriority() int64 {
+	if rwd.Priority == nil {
+		return 0
+	}
+
+	return *rwd.Priority
+}
+
+// GetRewardType to get reward type of the reward response
+func (rwdResp RewardResponse) GetRewardType() *int64 {
+	for rwdType, text := range rewardType {
+		if text == rwdResp.Type {
+			return &rwdType
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBenefitChanged(t *testing.T) {
+	value := float64(5000)
+	otherValue := float64(10000)
+	reward := models.Reward{
+		Name:           "Cashback",
+		JournalAccount: "123456",
+		PromoCode:      "CASHBACK",
+		IsPromoCode:    &models.IsPromoCodeTrue,
+		Validators:     &models.Validator{Channel: "pds", Value: &value},
+	}
+
+	// when only the text is changed
+	updated := reward
+	updated.Name = "Cashback Ramadhan"
+	updated.Validators = &models.Validator{Channel: "pds", Value: &value}
+	assert.False(t, reward.IsBenefitChanged(updated))
+
+	// when the validators is changed
+	updated.Validators = &models.Validator{Channel: "pds", Value: &otherValue}
+	assert.True(t, reward.IsBenefitChanged(updated))
+
+	// when the promo code flag is changed
+	updated = reward
+	updated.IsPromoCode = &models.IsPromoCodeFalse
+	assert.True(t, reward.IsBenefitChanged(updated))
+
+	// when the journal account is changed
+	updated = reward
+	updated.JournalAccount = "654321"
+	assert.True(t, reward.IsBenefitChanged(updated))
+}
+
+func TestRewardIsActive(t *testing.T) {
+	reward := models.Reward{}
+	assert.True(t, reward.IsActive())
+
+	reward.Status = &models.RewardStatusInactive
+	assert.False(t, reward.IsActive())
+}
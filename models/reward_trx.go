@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 var (
 	// RewardTrxInquired to store reward inquired status
@@ -9,8 +12,14 @@ var (
 	// RewardTrxSucceeded to store reward succeeded status
 	RewardTrxSucceeded int64 = 1
 
-	// RewardTrxRejected to store reward succeeded status
+	// RewardTrxRejected to store reward rejected status
 	RewardTrxRejected int64 = 2
+
+	// RewardTrxTimeout to store reward timeout status
+	RewardTrxTimeout int64 = 3
+
+	// RewardTrxReversed to store reward fully reversed by the core status
+	RewardTrxReversed int64 = 4
 )
 
 // RewardTrx is represent a reward_transactions model
@@ -28,6 +37,54 @@ type RewardTrx struct {
 	RejectedDate    *time.Time `json:"rejectedDate,omitempty"`
 	TimeoutDate     *time.Time `json:"timeoutDate,omitempty"`
 	RequestData     string     `json:"requestData,omitempty"`
+	ResponseData    string     `json:"responseData,omitempty"`
+	ReversedRatio   float64    `json:"reversedRatio,omitempty"`
 	CreatedAt       *time.Time `json:"createdAt,omitempty"`
 	UpdatedAt       *time.Time `json:"updatedAt,omitempty"`
 }
+
+// RewardPayment is represent a payload to confirm the reward transaction payment
+type RewardPayment struct {
+	CIF     string `json:"cif,omitempty" validate:"required"`
+	RefTrx  string `json:"refTrx,omitempty" validate:"required"`
+	RefCore string `json:"refCore,omitempty"`
+}
+
+// RewardInquiryLog is represent a reward_inquiries model
+type RewardInquiryLog struct {
+	ID           int64      `json:"id,omitempty"`
+	RefChannel   string     `json:"refChannel,omitempty"`
+	CIF          string     `json:"cif,omitempty"`
+	RefID        string     `json:"refId,omitempty"`
+	RequestHash  string     `json:"requestHash,omitempty"`
+	ResponseData *string    `json:"responseData,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+}
+
+// GetPointHistory to get the pending point history of a point reward, it is nil for any other reward
+func (rt RewardTrx) GetPointHistory(rwdResponse RewardResponse) *PointHistory {
+	rwdType := rwdResponse.GetRewardType()
+	pointAmount := math.Floor(rwdResponse.Value)
+
+	if rwdType == nil || *rwdType != RewardTypePoint || pointAmount <= 0 {
+		return nil
+	}
+
+	pointHistory := &PointHistory{
+		CIF:             rt.CIF,
+		PointAmount:     &pointAmount,
+		TransactionType: TransactionPointTypeDebet,
+		TransactionDate: rt.TransactionDate,
+		RefCore:         rt.RefCore,
+		RefID:           rt.RefID,
+		Status:          &PointHistoryStatusPending,
+		ExpiredDate:     rwdResponse.ExpiredDate,
+	}
+
+	if rt.RewardID != nil {
+		pointHistory.Reward = &Reward{ID: *rt.RewardID}
+	}
+
+	return pointHistory
+}
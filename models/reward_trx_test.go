@@ -0,0 +1,24 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewardTrxGetPointHistory(t *testing.T) {
+	trxDate := time.Now()
+	rewardID := int64(3)
+	rewardTrx := models.RewardTrx{CIF: "1011234567", RefID: "REF1", RewardID: &rewardID, TransactionDate: &trxDate}
+
+	pointHistory := rewardTrx.GetPointHistory(models.RewardResponse{Type: "point", Value: 12.7})
+	assert.Equal(t, float64(12), *pointHistory.PointAmount)
+	assert.Equal(t, models.PointHistoryStatusPending, *pointHistory.Status)
+	assert.Equal(t, "REF1", pointHistory.RefID)
+	assert.Equal(t, rewardID, pointHistory.Reward.ID)
+
+	assert.Nil(t, rewardTrx.GetPointHistory(models.RewardResponse{Type: "point", Value: 0.5}))
+	assert.Nil(t, rewardTrx.GetPointHistory(models.RewardResponse{Type: "discount", Value: 5000}))
+}
@@ -0,0 +1,313 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// RuleVersion to store the latest supported version of the rule language
+	RuleVersion int64 = 1
+
+	// RuleOperatorEq to store equal operator
+	RuleOperatorEq = "eq"
+	// RuleOperatorNeq to store not equal operator
+	RuleOperatorNeq = "neq"
+	// RuleOperatorIn to store in a list operator
+	RuleOperatorIn = "in"
+	// RuleOperatorNotIn to store not in a list operator
+	RuleOperatorNotIn = "nin"
+	// RuleOperatorBetween to store inclusive range operator
+	RuleOperatorBetween = "between"
+	// RuleOperatorRegex to store regular expression operator
+	RuleOperatorRegex = "regex"
+	// RuleOperatorGte to store greater than or equal operator
+	RuleOperatorGte = "gte"
+	// RuleOperatorLte to store less than or equal operator
+	RuleOperatorLte = "lte"
+	// RuleOperatorAny to store the expected constraint of a failing any group
+	RuleOperatorAny = "any"
+)
+
+// ruleAttributes is the registry of payload attributes that could be used by a rule
+var ruleAttributes = map[string]func(*PayloadValidator) interface{}{
+	"cif":               func(pl *PayloadValidator) interface{} { return pl.CIF },
+	"promoCode":         func(pl *PayloadValidator) interface{} { return pl.PromoCode },
+	"refChannel":        func(pl *PayloadValidator) interface{} { return pl.RefChannel },
+	"transactionDate":   func(pl *PayloadValidator) interface{} { return pl.TransactionDate },
+	"transactionAmount": func(pl *PayloadValidator) interface{} { return getFloatPointer(pl.TransactionAmount) },
+	"loanAmount":        func(pl *PayloadValidator) interface{} { return getFloatPointer(pl.LoanAmount) },
+	"channel":           func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).Channel },
+	"product":           func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).Product },
+	"transactionType":   func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).TransactionType },
+	"source":            func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).Source },
+	"unit":              func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).Unit },
+	"campaignCode":      func(pl *PayloadValidator) interface{} { return getPayloadValidator(pl).CampaignCode },
+}
+
+// Rule is represent a declarative eligibility rule. A rule is either a group of rules,
+// all of them (and) or any of them (or) must pass, or a condition of a payload attribute.
+//
+//	{"version": 1, "all": [
+//		{"attribute": "product", "operator": "in", "value": ["TE", "GC"]},
+//		{"attribute": "channel", "operator": "neq", "value": "pds"}
+//	]}
+//
+// A condition could also be a history rule of the customer, see HistoryRule.
+type Rule struct {
+	Version   int64        `json:"version,omitempty"`
+	All       []Rule       `json:"all,omitempty"`
+	Any       []Rule       `json:"any,omitempty"`
+	Attribute string       `json:"attribute,omitempty"`
+	Operator  string       `json:"operator,omitempty"`
+	Value     interface{}  `json:"value,omitempty"`
+	History   *HistoryRule `json:"history,omitempty"`
+}
+
+// Check to check whether the rule is well formed, it is meant to be called before storing a rule
+func (r *Rule) Check() error {
+	if r.Version > RuleVersion {
+		return fmt.Errorf("rule version %d is not supported", r.Version)
+	}
+
+	return r.check()
+}
+
+// Evaluate to evaluate the rule against the payload, it returns the first failing attribute if any
+func (r *Rule) Evaluate(payloadValidator *PayloadValidator) (string, error) {
+	validationErrs := r.EvaluateAll(payloadValidator)
+
+	if len(validationErrs) == 0 {
+		return "", nil
+	}
+
+	return validationErrs[0].Field, validationErrs
+}
+
+// EvaluateAll to evaluate the rule against the payload and collect every failing condition
+func (r *Rule) EvaluateAll(payloadValidator *PayloadValidator) ValidationErrors {
+	var validationErrs ValidationErrors
+
+	if r.Version > RuleVersion {
+		validationErr := newValidationError(ValidationCodeRuleInvalid, "rules", RuleVersion, r.Version)
+		validationErr.Message = fmt.Sprintf("rule version %d is not supported", r.Version)
+
+		return ValidationErrors{validationErr}
+	}
+
+	switch {
+	case len(r.All) > 0:
+		for _, rule := range r.All {
+			validationErrs = append(validationErrs, rule.EvaluateAll(payloadValidator)...)
+		}
+
+		return validationErrs
+	case len(r.Any) > 0:
+		for _, rule := range r.Any {
+			ruleErrs := rule.EvaluateAll(payloadValidator)
+
+			if len(ruleErrs) == 0 {
+				return nil
+			}
+
+			validationErrs = append(validationErrs, ruleErrs...)
+		}
+
+		// none of the rules is passed, so every failing condition is the detail
+		validationErr := newValidationError(ValidationCodeRuleNotMatch, "rules", RuleOperatorAny, nil)
+		validationErr.Details = validationErrs
+
+		return ValidationErrors{validationErr}
+	}
+
+	if r.History != nil {
+		return r.History.evaluate(payloadValidator)
+	}
+
+	getAttribute, ok := ruleAttributes[r.Attribute]
+
+	if !ok {
+		validationErr := newValidationError(ValidationCodeRuleInvalid, r.Attribute, nil, nil)
+		validationErr.Message = fmt.Sprintf("rule attribute %s is not registered", r.Attribute)
+
+		return ValidationErrors{validationErr}
+	}
+
+	value := getAttribute(payloadValidator)
+
+	if value == nil || !r.compare(value) {
+		expected := map[string]interface{}{"operator": r.Operator, "value": r.Value}
+
+		return ValidationErrors{newValidationError(ValidationCodeRuleNotMatch, r.Attribute, expected, value)}
+	}
+
+	return nil
+}
+
+func (r *Rule) check() error {
+	isGroup := len(r.All) > 0 || len(r.Any) > 0
+
+	if len(r.All) > 0 && len(r.Any) > 0 {
+		return errors.New("rule could not have both all and any group")
+	}
+
+	if isGroup && (r.Attribute != "" || r.History != nil) {
+		return errors.New("rule could not be a group and a condition at once")
+	}
+
+	if r.History != nil && r.Attribute != "" {
+		return errors.New("rule could not be a history and an attribute condition at once")
+	}
+
+	for _, rule := range append(r.All, r.Any...) {
+		if err := rule.check(); err != nil {
+			return err
+		}
+	}
+
+	if isGroup {
+		return nil
+	}
+
+	if r.History != nil {
+		return r.History.check()
+	}
+
+	if _, ok := ruleAttributes[r.Attribute]; !ok {
+		return fmt.Errorf("rule attribute %s is not registered", r.Attribute)
+	}
+
+	switch r.Operator {
+	case RuleOperatorEq, RuleOperatorNeq:
+		if r.Value == nil {
+			return fmt.Errorf("rule %s of %s needs a value", r.Operator, r.Attribute)
+		}
+	case RuleOperatorIn, RuleOperatorNotIn:
+		if _, ok := r.Value.([]interface{}); !ok {
+			return fmt.Errorf("rule %s of %s needs a list of values", r.Operator, r.Attribute)
+		}
+	case RuleOperatorBetween:
+		min, max, ok := r.getRange()
+
+		if !ok || min > max {
+			return fmt.Errorf("rule %s of %s needs a valid [min, max] range", r.Operator, r.Attribute)
+		}
+	case RuleOperatorRegex:
+		if _, err := regexp.Compile(fmt.Sprintf("%v", r.Value)); err != nil {
+			return fmt.Errorf("rule %s of %s is not a valid pattern", r.Operator, r.Attribute)
+		}
+	case RuleOperatorGte, RuleOperatorLte:
+		if _, ok := toFloat(r.Value); !ok {
+			return fmt.Errorf("rule %s of %s needs a number", r.Operator, r.Attribute)
+		}
+	default:
+		return fmt.Errorf("rule operator %s is not supported", r.Operator)
+	}
+
+	return nil
+}
+
+func (r *Rule) compare(value interface{}) bool {
+	switch r.Operator {
+	case RuleOperatorEq:
+		return isEqual(value, r.Value)
+	case RuleOperatorNeq:
+		return !isEqual(value, r.Value)
+	case RuleOperatorIn, RuleOperatorNotIn:
+		values, _ := r.Value.([]interface{})
+		found := false
+
+		for _, ruleValue := range values {
+			if isEqual(value, ruleValue) {
+				found = true
+
+				break
+			}
+		}
+
+		return found == (r.Operator == RuleOperatorIn)
+	case RuleOperatorBetween:
+		min, max, ok := r.getRange()
+		number, isNumber := toFloat(value)
+
+		return ok && isNumber && number >= min && number <= max
+	case RuleOperatorRegex:
+		matched, err := regexp.MatchString(fmt.Sprintf("%v", r.Value), fmt.Sprintf("%v", value))
+
+		return err == nil && matched
+	case RuleOperatorGte, RuleOperatorLte:
+		number, isNumber := toFloat(value)
+		limit, isLimit := toFloat(r.Value)
+
+		if !isNumber || !isLimit {
+			return false
+		}
+
+		if r.Operator == RuleOperatorGte {
+			return number >= limit
+		}
+
+		return number <= limit
+	}
+
+	return false
+}
+
+func (r *Rule) getRange() (float64, float64, bool) {
+	values, ok := r.Value.([]interface{})
+
+	if !ok || len(values) != 2 {
+		return 0, 0, false
+	}
+
+	min, isMin := toFloat(values[0])
+	max, isMax := toFloat(values[1])
+
+	return min, max, isMin && isMax
+}
+
+func isEqual(value, ruleValue interface{}) bool {
+	// numeric attribute is compared as a number, the others as a text
+	if number, isNumber := value.(float64); isNumber {
+		ruleNumber, isRuleNumber := toFloat(ruleValue)
+
+		return isRuleNumber && number == ruleNumber
+	}
+
+	return fmt.Sprintf("%v", value) == fmt.Sprintf("%v", ruleValue)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+
+		return number, err == nil
+	}
+
+	return 0, false
+}
+
+func getFloatPointer(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
+
+func getPayloadValidator(payloadValidator *PayloadValidator) *Validator {
+	if payloadValidator.Validators == nil {
+		return &Validator{}
+	}
+
+	return payloadValidator.Validators
+}
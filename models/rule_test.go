@@ -0,0 +1,162 @@
+package models_test
+
+import (
+	"encoding/json"
+	"gade/srv-gade-point/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getRule(t *testing.T, ruleJSON string) *models.Rule {
+	var rule models.Rule
+
+	assert.Nil(t, json.Unmarshal([]byte(ruleJSON), &rule))
+
+	return &rule
+}
+
+func TestRuleEvaluate(t *testing.T) {
+	amount := float64(1500000)
+	payload := &models.PayloadValidator{
+		CIF:               "1122334455",
+		PromoCode:         "EMASMERDEKA",
+		TransactionAmount: &amount,
+		Validators: &models.Validator{
+			Channel: "mobile",
+			Product: "TE",
+		},
+	}
+
+	// when all rules is passed
+	rule := getRule(t, `{"version": 1, "all": [
+		{"attribute": "product", "operator": "in", "value": ["TE", "GC"]},
+		{"attribute": "channel", "operator": "neq", "value": "pds"},
+		{"attribute": "transactionAmount", "operator": "between", "value": [1000000, 2000000]},
+		{"attribute": "promoCode", "operator": "regex", "value": "^EMAS"},
+		{"attribute": "transactionAmount", "operator": "gte", "value": 1500000},
+		{"attribute": "transactionAmount", "operator": "lte", "value": 1500000}
+	]}`)
+	assert.Equal(t, mltplFunc("", nil), mltplFunc(rule.Evaluate(payload)))
+
+	// when one of all rules is not passed
+	rule = getRule(t, `{"all": [
+		{"attribute": "product", "operator": "eq", "value": "TE"},
+		{"attribute": "channel", "operator": "nin", "value": ["mobile", "pds"]}
+	]}`)
+	attribute, err := rule.Evaluate(payload)
+	assert.Equal(t, "channel", attribute)
+	assert.NotNil(t, err)
+
+	// when one of any rules is passed
+	rule = getRule(t, `{"any": [
+		{"attribute": "product", "operator": "eq", "value": "GC"},
+		{"all": [{"attribute": "cif", "operator": "eq", "value": "1122334455"}]}
+	]}`)
+	assert.Equal(t, mltplFunc("", nil), mltplFunc(rule.Evaluate(payload)))
+
+	// when none of any rules is passed
+	rule = getRule(t, `{"any": [
+		{"attribute": "product", "operator": "eq", "value": "GC"},
+		{"attribute": "loanAmount", "operator": "gte", "value": 1}
+	]}`)
+	attribute, err = rule.Evaluate(payload)
+	assert.Equal(t, "rules", attribute)
+	assert.NotNil(t, err)
+
+	// when the rule version is not supported
+	rule = getRule(t, `{"version": 99, "attribute": "product", "operator": "eq", "value": "TE"}`)
+	_, err = rule.Evaluate(payload)
+	assert.NotNil(t, err)
+}
+
+func TestRuleCheck(t *testing.T) {
+	// when its valid
+	assert.Nil(t, getRule(t, `{"version": 1, "any": [
+		{"attribute": "product", "operator": "in", "value": ["TE"]},
+		{"attribute": "loanAmount", "operator": "between", "value": [1, 2]}
+	]}`).Check())
+
+	// when its not valid
+	invalidRules := []string{
+		`{"version": 99, "attribute": "product", "operator": "eq", "value": "TE"}`,
+		`{"attribute": "unknown", "operator": "eq", "value": "TE"}`,
+		`{"attribute": "product", "operator": "like", "value": "TE"}`,
+		`{"attribute": "product", "operator": "in", "value": "TE"}`,
+		`{"attribute": "loanAmount", "operator": "between", "value": [2, 1]}`,
+		`{"attribute": "cif", "operator": "regex", "value": "("}`,
+		`{"attribute": "loanAmount", "operator": "gte", "value": "a lot"}`,
+		`{"all": [{"attribute": "cif", "operator": "eq"}]}`,
+		`{"all": [{"attribute": "cif", "operator": "eq", "value": "1"}], "any": [{"attribute": "cif", "operator": "eq", "value": "1"}]}`,
+	}
+
+	for _, invalidRule := range invalidRules {
+		assert.NotNil(t, getRule(t, invalidRule).Check(), invalidRule)
+	}
+}
+
+func TestValidateWithRules(t *testing.T) {
+	trxAmount := float64(1000000)
+	minTrxAmount := float64(100000)
+	payload := &models.PayloadValidator{
+		TransactionAmount: &trxAmount,
+		Validators:        &models.Validator{Channel: "pds", Product: "TE"},
+	}
+	rwdValidator := &models.Validator{
+		Channel:              "pds",
+		MinTransactionAmount: &minTrxAmount,
+		Rules:                getRule(t, `{"attribute": "product", "operator": "in", "value": ["TE", "GC"]}`),
+	}
+
+	// when flat validators and rules is passed
+	assert.Equal(t, mltplFunc("", nil), mltplFunc(rwdValidator.ValidateRule(payload)))
+
+	// when the flat validators is passed but the rules is not
+	payload.Validators.Product = "GS"
+	attribute, err := rwdValidator.ValidateRule(payload)
+	assert.Equal(t, "product", attribute)
+	assert.NotNil(t, err)
+
+	// when the flat validators is not passed
+	payload.Validators.Product = "TE"
+	minTrxAmount = 5000000
+	attribute, err = rwdValidator.ValidateRule(payload)
+	assert.Equal(t, "minTransactionAmount", attribute)
+	assert.NotNil(t, err)
+	assert.Nil(t, rwdValidator.Check())
+}
+
+func TestRuleEvaluateAll(t *testing.T) {
+	amount := float64(1500000)
+	payload := &models.PayloadValidator{
+		TransactionAmount: &amount,
+		Validators:        &models.Validator{Channel: "mobile", Product: "TE"},
+	}
+
+	// when many of all rules is not passed
+	rule := getRule(t, `{"all": [
+		{"attribute": "product", "operator": "eq", "value": "GC"},
+		{"attribute": "channel", "operator": "eq", "value": "mobile"},
+		{"attribute": "transactionAmount", "operator": "gte", "value": 2000000}
+	]}`)
+	validationErrs := rule.EvaluateAll(payload)
+	assert.Equal(t, 2, len(validationErrs))
+	assert.Equal(t, models.ValidationError{
+		Code:     models.ValidationCodeRuleNotMatch,
+		Field:    "transactionAmount",
+		Message:  "transactionAmount on this transaction is not valid to use the benefit",
+		Expected: map[string]interface{}{"operator": "gte", "value": float64(2000000)},
+		Actual:   amount,
+	}, validationErrs[1])
+
+	// when none of any rules is passed, the failing rules are the details
+	rule = getRule(t, `{"any": [
+		{"attribute": "product", "operator": "eq", "value": "GC"},
+		{"attribute": "channel", "operator": "eq", "value": "pds"}
+	]}`)
+	validationErrs = rule.EvaluateAll(payload)
+	assert.Equal(t, 1, len(validationErrs))
+	assert.Equal(t, "rules", validationErrs[0].Field)
+	assert.Equal(t, []string{"product", "channel"},
+		[]string{validationErrs[0].Details[0].Field, validationErrs[0].Details[1].Field})
+}
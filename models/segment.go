@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	// SegmentFormatCSV to store segment members upload format csv
+	SegmentFormatCSV = "csv"
+	// SegmentFormatJSON to store segment members upload format json
+	SegmentFormatJSON = "json"
+
+	// SegmentBatchSize to store how many members is stored in a single batch
+	SegmentBatchSize = 1000
+)
+
+// Segment is represent a customer segment model
+type Segment struct {
+	ID          int64      `json:"id,omitempty"`
+	Name        string     `json:"name,omitempty" validate:"required"`
+	Description string     `json:"description,omitempty"`
+	TotalMember *int64     `json:"totalMember,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+// PayloadSegmentMember to store a payload to add or remove segment members
+type PayloadSegmentMember struct {
+	CIFs []string `json:"cifs,omitempty" validate:"required"`
+}
+
+// SegmentMemberResult is represent a segment members modification result model
+type SegmentMemberResult struct {
+	Total    int64 `json:"total"`
+	Affected int64 `json:"affected"`
+}
+
+// ReadSegmentMembers to read segment members from a csv or json stream and pass them batch by batch.
+// A csv stream has the cif on its first column with an optional "cif" header, a json stream is
+// an array of cif or an array of object with a cif attribute. It returns the total of read cif.
+func ReadSegmentMembers(reader io.Reader, format string, batchSize int, storeBatch func([]string) error) (int64, error) {
+	var err error
+	batcher := newMemberBatcher(batchSize, storeBatch)
+
+	switch format {
+	case SegmentFormatCSV:
+		err = readCSVMembers(reader, batcher.add)
+	case SegmentFormatJSON:
+		err = readJSONMembers(reader, batcher.add)
+	default:
+		err = fmt.Errorf("segment members format %s is not supported", format)
+	}
+
+	if err != nil {
+		return batcher.total, err
+	}
+
+	return batcher.total, batcher.flush()
+}
+
+// ReadSegmentMembersFromList to pass a list of segment members batch by batch
+func ReadSegmentMembersFromList(cifs []string, batchSize int, storeBatch func([]string) error) (int64, error) {
+	batcher := newMemberBatcher(batchSize, storeBatch)
+
+	for _, cif := range cifs {
+		if err := batcher.add(cif); err != nil {
+			return batcher.total, err
+		}
+	}
+
+	return batcher.total, batcher.flush()
+}
+
+type memberBatcher struct {
+	total      int64
+	batch      []string
+	batchSize  int
+	storeBatch func([]string) error
+}
+
+func newMemberBatcher(batchSize int, storeBatch func([]string) error) *memberBatcher {
+	return &memberBatcher{
+		batch:      make([]string, 0, batchSize),
+		batchSize:  batchSize,
+		storeBatch: storeBatch,
+	}
+}
+
+func (mb *memberBatcher) add(cif string) error {
+	cif = strings.TrimSpace(cif)
+
+	if cif == "" {
+		return nil
+	}
+
+	mb.total++
+	mb.batch = append(mb.batch, cif)
+
+	if len(mb.batch) < mb.batchSize {
+		return nil
+	}
+
+	return mb.flush()
+}
+
+func (mb *memberBatcher) flush() error {
+	if len(mb.batch) == 0 {
+		return nil
+	}
+
+	err := mb.storeBatch(mb.batch)
+	mb.batch = mb.batch[:0]
+
+	return err
+}
+
+func readCSVMembers(reader io.Reader, addCIF func(string) error) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+	isFirst := true
+
+	for {
+		record, err := csvReader.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// skip the header if any
+		if isFirst && strings.EqualFold(strings.TrimSpace(record[0]), "cif") {
+			isFirst = false
+
+			continue
+		}
+
+		isFirst = false
+
+		if err = addCIF(record[0]); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONMembers(reader io.Reader, addCIF func(string) error) error {
+	decoder := json.NewDecoder(reader)
+	token, err := decoder.Token()
+
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("segment members json should be an array")
+	}
+
+	for decoder.More() {
+		var member json.RawMessage
+		var cif string
+
+		if err = decoder.Decode(&member); err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(member, &cif); err != nil {
+			var memberObj struct {
+				CIF string `json:"cif"`
+			}
+
+			if err = json.Unmarshal(member, &memberObj); err != nil {
+				return fmt.Errorf("segment member %s is not valid", string(member))
+			}
+
+			cif = memberObj.CIF
+		}
+
+		if err = addCIF(cif); err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+
+	return err
+}
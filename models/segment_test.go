@@ -0,0 +1,95 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectBatches(batches *[][]string) func([]string) error {
+	return func(batch []string) error {
+		*batches = append(*batches, append([]string(nil), batch...))
+
+		return nil
+	}
+}
+
+func TestReadSegmentMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		batches [][]string
+		total   int64
+	}{
+		{
+			name:    "csv with header",
+			format:  models.SegmentFormatCSV,
+			input:   "cif,name\n1001,Budi\n1002,Ani\n\n 1003 ,Siti\n",
+			batches: [][]string{{"1001", "1002"}, {"1003"}},
+			total:   3,
+		},
+		{
+			name:    "csv without header",
+			format:  models.SegmentFormatCSV,
+			input:   "1001\n1002\n",
+			batches: [][]string{{"1001", "1002"}},
+			total:   2,
+		},
+		{
+			name:    "json of cif",
+			format:  models.SegmentFormatJSON,
+			input:   `["1001", "1002", "1003", "1004"]`,
+			batches: [][]string{{"1001", "1002"}, {"1003", "1004"}},
+			total:   4,
+		},
+		{
+			name:    "json of object",
+			format:  models.SegmentFormatJSON,
+			input:   `[{"cif": "1001"}, {"cif": ""}, {"cif": "1002"}]`,
+			batches: [][]string{{"1001", "1002"}},
+			total:   2,
+		},
+	}
+
+	for _, test := range tests {
+		var batches [][]string
+		total, err := models.ReadSegmentMembers(strings.NewReader(test.input), test.format, 2, collectBatches(&batches))
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.total, total, test.name)
+		assert.Equal(t, test.batches, batches, test.name)
+	}
+}
+
+func TestReadSegmentMembersInvalid(t *testing.T) {
+	var batches [][]string
+	tests := []struct {
+		format string
+		input  string
+	}{
+		{format: "xml", input: "<cif>1001</cif>"},
+		{format: models.SegmentFormatJSON, input: `{"cif": "1001"}`},
+		{format: models.SegmentFormatJSON, input: `[1001]`},
+	}
+
+	for _, test := range tests {
+		_, err := models.ReadSegmentMembers(strings.NewReader(test.input), test.format, 2, collectBatches(&batches))
+
+		assert.Error(t, err, test.input)
+	}
+
+	assert.Empty(t, batches)
+}
+
+func TestReadSegmentMembersFromList(t *testing.T) {
+	var batches [][]string
+	total, err := models.ReadSegmentMembersFromList([]string{"1001", " ", "1002", "1003"}, 2,
+		collectBatches(&batches))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, [][]string{{"1001", "1002"}, {"1003"}}, batches)
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var (
+	// TierByTransactionAmount to store tiers keyed by the transaction amount
+	TierByTransactionAmount = "transactionAmount"
+	// TierByLoanAmount to store tiers keyed by the loan amount
+	TierByLoanAmount = "loanAmount"
+)
+
+// Tier is represent an amount bracket of a reward, min is inclusive and max is exclusive,
+// an empty min or max means the bracket is unbounded on that side
+type Tier struct {
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Value    *float64 `json:"value,omitempty"`
+	Discount *float64 `json:"discount,omitempty"`
+	Formula  string   `json:"formula,omitempty"`
+	MaxValue *float64 `json:"maxValue,omitempty"`
+}
+
+// GetTier to get the tier that match the payload amount, it returns nil when the validator has no tiers
+func (v *Validator) GetTier(payloadValidator *PayloadValidator) (*Tier, error) {
+	if len(v.Tiers) == 0 {
+		return nil, nil
+	}
+
+	amount := getTierAmount(v.TierBy, payloadValidator)
+
+	if amount != nil {
+		for i := range v.Tiers {
+			if v.Tiers[i].isMatch(*amount) {
+				return &v.Tiers[i], nil
+			}
+		}
+	}
+
+	var tiers []string
+
+	for i := range v.Tiers {
+		tiers = append(tiers, v.Tiers[i].getRange())
+	}
+
+	return nil, ValidationErrors{newValidationError(ValidationCodeTierNotMatch, v.TierBy, tiers, getFloatPointer(amount))}
+}
+
+// checkTiers to check the tiers key and make sure none of the tiers is overlapping
+func (v *Validator) checkTiers() error {
+	if len(v.Tiers) == 0 {
+		return nil
+	}
+
+	if v.TierBy != TierByTransactionAmount && v.TierBy != TierByLoanAmount {
+		return fmt.Errorf("tier by %s is not supported, use %s or %s", v.TierBy, TierByTransactionAmount, TierByLoanAmount)
+	}
+
+	tiers := make([]Tier, len(v.Tiers))
+	copy(tiers, v.Tiers)
+
+	for _, tier := range tiers {
+		if tier.Min != nil && tier.Max != nil && *tier.Min >= *tier.Max {
+			return fmt.Errorf("tier %s min should be less than its max", tier.getRange())
+		}
+
+		if tier.Formula == "" {
+			continue
+		}
+
+		if _, err := CompileFormula(tier.Formula); err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if tiers[i].Min == nil || tiers[j].Min == nil {
+			return tiers[i].Min == nil && tiers[j].Min != nil
+		}
+
+		return *tiers[i].Min < *tiers[j].Min
+	})
+
+	for i := 1; i < len(tiers); i++ {
+		previous, current := tiers[i-1], tiers[i]
+
+		if previous.Max == nil || current.Min == nil || *current.Min < *previous.Max {
+			return fmt.Errorf("tier %s is overlapping with tier %s", current.getRange(), previous.getRange())
+		}
+	}
+
+	return nil
+}
+
+// apply to get the validator with the benefit of the tier
+func (t *Tier) apply(v *Validator) *Validator {
+	tiered := *v
+	tiered.Value = t.Value
+	tiered.Discount = t.Discount
+	tiered.Formula = t.Formula
+	tiered.MaxValue = t.MaxValue
+	tiered.Tiers = nil
+
+	return &tiered
+}
+
+func (t *Tier) isMatch(amount float64) bool {
+	return (t.Min == nil || amount >= *t.Min) && (t.Max == nil || amount < *t.Max)
+}
+
+func (t *Tier) getRange() string {
+	min, max := "-", "-"
+
+	if t.Min != nil {
+		min = strconv.FormatFloat(*t.Min, 'f', -1, 64)
+	}
+
+	if t.Max != nil {
+		max = strconv.FormatFloat(*t.Max, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("[%s, %s)", min, max)
+}
+
+func getTierAmount(tierBy string, payloadValidator *PayloadValidator) *float64 {
+	switch tierBy {
+	case TierByTransactionAmount:
+		return payloadValidator.TransactionAmount
+	case TierByLoanAmount:
+		return payloadValidator.LoanAmount
+	}
+
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
 )
 
-// Token to store JWT token data
+// Token to store JWT token data, user id is only given to the token of a logged in user
 type Token struct {
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	UserID int64  `json:"userId,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -23,3 +25,21 @@ type AccountToken struct {
 	CreatedAt *time.Time `json:"created_at,omitempty"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
+
+// GetTokenUser to get the claims of the logged in user that is authenticated by the request,
+// a token of a service account does not belong to any user
+func GetTokenUser(c echo.Context) *Token {
+	token, ok := c.Get("user").(*jwt.Token)
+
+	if !ok {
+		return nil
+	}
+
+	claims, ok := token.Claims.(*Token)
+
+	if !ok || claims.UserID == 0 || claims.Name == "" {
+		return nil
+	}
+
+	return claims
+}
@@ -0,0 +1,25 @@
+package models_test
+
+import (
+	"gade/srv-gade-point/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTokenUser(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+
+	assert.Nil(t, models.GetTokenUser(c))
+
+	// the token of a service account is not a user
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS512, &models.Token{Name: "service"}))
+	assert.Nil(t, models.GetTokenUser(c))
+
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS512, &models.Token{Name: "admin1", UserID: 7}))
+	assert.Equal(t, &models.Token{Name: "admin1", UserID: 7}, models.GetTokenUser(c))
+}
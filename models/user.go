@@ -2,6 +2,13 @@ package models
 
 import "time"
 
+var (
+	// UserRoleAdmin to store role of an admin user, the only role that is allowed to change customer points
+	UserRoleAdmin int8
+	// UserRoleUser to store role of a regular user
+	UserRoleUser int8 = 1
+)
+
 // User is represent a users model
 type User struct {
 	ID        int64      `json:"id,omitempty"`
@@ -10,6 +17,8 @@ type User struct {
 	Status    *int8      `json:"status,omitempty"`
 	Role      *int8      `json:"role,omitempty"`
 	Email     string     `json:"email,omitempty"`
+	Token     string     `json:"token,omitempty"`
+	ExpireAt  *time.Time `json:"expireAt,omitempty"`
 	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 	CreatedAt *time.Time `json:"createdAt,omitempty"`
 }
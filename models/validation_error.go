@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// ValidationCodeUnavailable to store validation code of a missing validator
+	ValidationCodeUnavailable = "VALIDATOR_UNAVAILABLE"
+	// ValidationCodeNotMatch to store validation code of a field that is not match the expected value
+	ValidationCodeNotMatch = "NOT_MATCH"
+	// ValidationCodeBelowMinimum to store validation code of an amount below the minimum
+	ValidationCodeBelowMinimum = "BELOW_MINIMUM"
+	// ValidationCodeAboveMaximum to store validation code of an amount above the maximum
+	ValidationCodeAboveMaximum = "ABOVE_MAXIMUM"
+	// ValidationCodeRuleNotMatch to store validation code of a failing declarative rule
+	ValidationCodeRuleNotMatch = "RULE_NOT_MATCH"
+	// ValidationCodeRuleInvalid to store validation code of a malformed declarative rule
+	ValidationCodeRuleInvalid = "RULE_INVALID"
+	// ValidationCodeHistoryNotMatch to store validation code of a failing history rule
+	ValidationCodeHistoryNotMatch = "HISTORY_NOT_MATCH"
+	// ValidationCodeHistoryUnavailable to store validation code of an unreadable customer history
+	ValidationCodeHistoryUnavailable = "HISTORY_UNAVAILABLE"
+	// ValidationCodeTierNotMatch to store validation code of an amount without matching tier
+	ValidationCodeTierNotMatch = "TIER_NOT_MATCH"
+	// ValidationCodeOutOfPeriod to store validation code of a transaction outside the reward period
+	ValidationCodeOutOfPeriod = "OUT_OF_PERIOD"
+	// ValidationCodePromoCodeNotMatch to store validation code of a promo code that is not match the reward
+	ValidationCodePromoCodeNotMatch = "PROMO_CODE_NOT_MATCH"
+	// ValidationCodeSegmentNotMatch to store validation code of a customer outside the reward segments
+	ValidationCodeSegmentNotMatch = "SEGMENT_NOT_MATCH"
+	// ValidationCodeSegmentUnavailable to store validation code of unreadable customer segments
+	ValidationCodeSegmentUnavailable = "SEGMENT_UNAVAILABLE"
+	// ValidationCodeMembershipTierNotMatch to store validation code of a customer tier that is not targeted by the reward
+	ValidationCodeMembershipTierNotMatch = "MEMBERSHIP_TIER_NOT_MATCH"
+	// ValidationCodeMembershipTierUnavailable to store validation code of an unreadable customer tier
+	ValidationCodeMembershipTierUnavailable = "MEMBERSHIP_TIER_UNAVAILABLE"
+	// ValidationCodeQuotaExhausted to store validation code of a reward without quota left
+	ValidationCodeQuotaExhausted = "QUOTA_EXHAUSTED"
+	// ValidationCodeUserQuotaExhausted to store validation code of a reward without quota left for the customer
+	ValidationCodeUserQuotaExhausted = "USER_QUOTA_EXHAUSTED"
+	// ValidationCodeFieldPrefix to store validation code prefix of a request field tag, e.g. FIELD_REQUIRED
+	ValidationCodeFieldPrefix = "FIELD_"
+)
+
+// ValidationError is represent a single failed validation, expected is the constraint
+// of the failing field and actual is the value of the request
+type ValidationError struct {
+	Code     string           `json:"code"`
+	Field    string           `json:"field"`
+	Message  string           `json:"message,omitempty"`
+	Expected interface{}      `json:"expected,omitempty"`
+	Actual   interface{}      `json:"actual,omitempty"`
+	Details  ValidationErrors `json:"details,omitempty"`
+}
+
+// ValidationErrors is represent all failed validations of a request
+type ValidationErrors []ValidationError
+
+// ValidationFailure is represent an error that is caused by failed validations
+type ValidationFailure struct {
+	Err    error
+	Errors ValidationErrors
+}
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+
+	for _, validationErr := range ve {
+		messages = append(messages, validationErr.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (vf *ValidationFailure) Error() string {
+	return vf.Err.Error()
+}
+
+// GetValidationErrors to get the failed validations behind an error if any
+func GetValidationErrors(err error) ValidationErrors {
+	switch validationErr := err.(type) {
+	case ValidationErrors:
+		return validationErr
+	case *ValidationFailure:
+		return validationErr.Errors
+	}
+
+	return nil
+}
+
+func newValidationError(code, field string, expected, actual interface{}) ValidationError {
+	return ValidationError{
+		Code:     code,
+		Field:    field,
+		Message:  fmt.Sprintf(customErrMsg, field),
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// NewValidationErrorFrom to get a failed validation of a check that is done apart from the validators,
+// the error of the check is its message
+func NewValidationErrorFrom(code, field string, err error) ValidationError {
+	return ValidationError{Code: code, Field: field, Message: err.Error()}
+}
+
+// toError to get the failed validations as an error, it is nil when nothing is failed
+func (ve ValidationErrors) toError() error {
+	if len(ve) == 0 {
+		return nil
+	}
+
+	return ve
+}
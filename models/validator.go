@@ -2,16 +2,13 @@ package models
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/iancoleman/strcase"
 	"github.com/sirupsen/logrus"
-	govaluate "gopkg.in/Knetic/govaluate.v2"
 )
 
 // Validator to store all validator data
@@ -31,22 +28,31 @@ type Validator struct {
 	Unit                 string   `json:"unit,omitempty"`
 	Value                *float64 `json:"value,omitempty"`
 	ValueVoucherID       *int64   `json:"valueVoucherId,omitempty"`
+	Rules                *Rule    `json:"rules,omitempty"`
+	IncludeSegments      []int64  `json:"includeSegments,omitempty"`
+	ExcludeSegments      []int64  `json:"excludeSegments,omitempty"`
+	TierBy               string   `json:"tierBy,omitempty"`
+	Tiers                []Tier   `json:"tiers,omitempty"`
+	MembershipTiers      []string `json:"membershipTiers,omitempty"`
 }
 
 // PayloadValidator to store a payload to validate a request
 type PayloadValidator struct {
-	CampaignID        string     `json:"campaignId,omitempty"`
-	CIF               string     `json:"cif,omitempty" validate:"required"`
-	LoanAmount        *float64   `json:"loanAmount,omitempty"`
-	PromoCode         string     `json:"promoCode,omitempty" validate:"required"`
-	RedeemedDate      string     `json:"redeemedDate,omitempty"`
-	TransactionDate   string     `json:"transactionDate,omitempty" validate:"required"`
-	TransactionAmount *float64   `json:"transactionAmount,omitempty" validate:"required"`
-	VoucherID         string     `json:"voucherId,omitempty"`
-	Validators        *Validator `json:"validators,omitempty"`
+	CampaignID        string          `json:"campaignId,omitempty"`
+	CIF               string          `json:"cif,omitempty" validate:"required"`
+	LoanAmount        *float64        `json:"loanAmount,omitempty"`
+	PromoCode         string          `json:"promoCode,omitempty" validate:"required"`
+	RefChannel        string          `json:"refChannel,omitempty"`
+	RedeemedDate      string          `json:"redeemedDate,omitempty"`
+	TransactionDate   string          `json:"transactionDate,omitempty" validate:"required"`
+	TransactionAmount *float64        `json:"transactionAmount,omitempty" validate:"required"`
+	VoucherID         string          `json:"voucherId,omitempty"`
+	Validators        *Validator      `json:"validators,omitempty"`
+	History           HistoryProvider `json:"-"`
 }
 
-var skippedValidator = []string{"multiplier", "value", "formula", "maxValue", "unit"}
+var skippedValidator = []string{"multiplier", "value", "formula", "maxValue", "unit", "rules",
+	"includeSegments", "excludeSegments", "tierBy", "tiers", "membershipTiers"}
 var compareEqual = []string{"channel", "product", "transactionType", "source", "campaignCode"}
 var tightenValidator = map[string]string{
 	"minTransactionAmount": "transactionAmount",
@@ -65,6 +71,17 @@ func (v *Validator) GetRewardValue(payloadValidator *PayloadValidator) (float64,
 		return 0, err
 	}
 
+	// use the benefit of the matching tier if any
+	tier, err := v.GetTier(payloadValidator)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if tier != nil {
+		v = tier.apply(v)
+	}
+
 	if v.Value != nil {
 		result = *v.Value
 	}
@@ -103,15 +120,32 @@ func (v *Validator) GetRewardValue(payloadValidator *PayloadValidator) (float64,
 	return result, nil
 }
 
-// Validate to validate client input with admin input
+// Validate to validate client input with admin input, the error is a ValidationErrors of every failed validation
 func (v *Validator) Validate(payloadValidator *PayloadValidator) error {
+	return v.ValidateAll(payloadValidator).toError()
+}
+
+// ValidateRule to validate client input with admin input, it also returns the name of the first failing rule
+func (v *Validator) ValidateRule(payloadValidator *PayloadValidator) (string, error) {
+	validationErrs := v.ValidateAll(payloadValidator)
+
+	if len(validationErrs) == 0 {
+		return "", nil
+	}
+
+	return validationErrs[0].Field, validationErrs
+}
+
+// ValidateAll to validate client input with admin input and collect every failed validation
+func (v *Validator) ValidateAll(payloadValidator *PayloadValidator) ValidationErrors {
 	var reqValidator map[string]interface{}
 	var payloadVal map[string]interface{}
+	var validationErrs ValidationErrors
 
 	if v == nil {
 		logrus.Debug(ErrValidatorUnavailable)
 
-		return ErrValidatorUnavailable
+		return ValidationErrors{{Code: ValidationCodeUnavailable, Field: "validators", Message: ErrValidatorUnavailable.Error()}}
 	}
 
 	vReflector := reflect.ValueOf(v).Elem()
@@ -139,79 +173,79 @@ func (v *Validator) Validate(payloadValidator *PayloadValidator) error {
 			reqValidatorVal := fmt.Sprintf("%v", reqValidator[fieldName])
 
 			if !strings.Contains(fieldValue, reqValidatorVal) {
-				logrus.Debug(fmt.Errorf(customErrMsg, fieldName))
-
-				return fmt.Errorf(customErrMsg, fieldName)
+				validationErrs = append(validationErrs, newValidationError(ValidationCodeNotMatch, fieldName,
+					fieldValue, reqValidator[fieldName]))
 			}
 		case strings.Contains(fieldName, "min"):
 			minTrx, _ := strconv.ParseFloat(fieldValue, 64)
+			amount, ok := payloadVal[tightenValidator[fieldName]].(float64)
 
-			if minTrx > payloadVal[tightenValidator[fieldName]].(float64) {
-				logrus.Debug(fmt.Errorf(customErrMsg, fieldName))
-
-				return fmt.Errorf(customErrMsg, tightenValidator[fieldName])
+			if !ok || minTrx > amount {
+				validationErrs = append(validationErrs, newValidationError(ValidationCodeBelowMinimum, fieldName,
+					minTrx, payloadVal[tightenValidator[fieldName]]))
 			}
 		case strings.Contains(fieldName, "max"):
 			maxTrx, _ := strconv.ParseFloat(fieldValue, 64)
+			amount, ok := payloadVal[tightenValidator[fieldName]].(float64)
 
-			if maxTrx < payloadVal[tightenValidator[fieldName]].(float64) {
-				logrus.Debug(fmt.Errorf(customErrMsg, fieldName))
-
-				return fmt.Errorf(customErrMsg, tightenValidator[fieldName])
+			if !ok || maxTrx < amount {
+				validationErrs = append(validationErrs, newValidationError(ValidationCodeAboveMaximum, fieldName,
+					maxTrx, payloadVal[tightenValidator[fieldName]]))
 			}
 		}
 	}
 
-	return nil
-}
-
-// GetFormulaResult to proccess the formula then get the result
-func (v *Validator) GetFormulaResult(payloadValidator *PayloadValidator) (float64, error) {
-	// check formula availability
-	if v.Formula == "" {
-		return float64(0), nil
+	// evaluate the declarative rules if any
+	if v.Rules != nil {
+		validationErrs = append(validationErrs, v.Rules.EvaluateAll(payloadValidator)...)
 	}
 
-	expression, err := govaluate.NewEvaluableExpression(v.Formula)
-
-	if err != nil {
-		logrus.Debug(err)
-
-		return 0, err
+	if len(validationErrs) > 0 {
+		logrus.Debug(validationErrs)
 	}
 
-	// Make a Regex to say we only want letters and numbers
-	regex, err := regexp.Compile("[^a-zA-Z0-9]+")
+	return validationErrs
+}
 
-	if err != nil {
-		logrus.Debug(err)
+// Check to check whether the validator rules, formula and tiers is well formed
+func (v *Validator) Check() error {
+	if v == nil {
+		return nil
+	}
 
-		return 0, nil
+	if v.Formula != "" {
+		if _, err := CompileFormula(v.Formula); err != nil {
+			return err
+		}
 	}
 
-	formulaVarStr := regex.ReplaceAllString(v.Formula, " ")
-	formulaVarStr = strings.TrimLeft(formulaVarStr, " ")
-	formulaVarStr = strings.TrimRight(formulaVarStr, " ")
-	formulaVar := strings.Split(formulaVarStr, " ")
-	remove(formulaVar, "transactionAmount")
+	if err := v.checkTiers(); err != nil {
+		return err
+	}
 
-	// get formula parameters
-	parameters := make(map[string]interface{}, 8)
-	parameters["transactionAmount"] = *payloadValidator.TransactionAmount
+	if v.Rules != nil {
+		return v.Rules.Check()
+	}
 
-	for _, fVar := range formulaVar {
-		parameters[fVar], err = v.getField(fVar)
+	return nil
+}
 
-		if err != nil {
-			break
-		}
+// GetFormulaResult to proccess the formula then get the result
+func (v *Validator) GetFormulaResult(payloadValidator *PayloadValidator) (float64, error) {
+	// check formula availability
+	if v.Formula == "" {
+		return float64(0), nil
 	}
 
+	expression, err := CompileFormula(v.Formula)
+
 	if err != nil {
+		logrus.Debug(err)
+
 		return 0, err
 	}
 
-	result, err := expression.Evaluate(parameters)
+	result, err := expression.Evaluate(getFormulaParameters(v, payloadValidator))
 
 	if err != nil {
 		logrus.Debug(err)
@@ -238,63 +272,26 @@ func (v *Validator) GetVoucherResult() (int64, error) {
 
 // CalculateDiscount to get the discount currency value
 func (v *Validator) CalculateDiscount(trxAmount *float64) (float64, error) {
-	discount := *v.Discount
-
-	if &discount == nil || discount == 0 {
+	if v.Discount == nil || *v.Discount == 0 || trxAmount == nil {
 		return 0, nil
 	}
 
-	result := *trxAmount * (discount / 100)
+	result := *trxAmount * (*v.Discount / 100)
 
 	return result, nil
 }
 
 // CalculateMaximumValue to get maximum value of a reward
 func (v *Validator) CalculateMaximumValue(value *float64) (float64, error) {
-	maxValue := *v.MaxValue
-
-	if &maxValue == nil || maxValue == 0 {
+	if v.MaxValue == nil || *v.MaxValue == 0 {
 		return 0, nil
-
 	}
 
-	if *value < maxValue {
+	if *value < *v.MaxValue {
 		return *value, nil
 	}
 
-	return maxValue, nil
-}
-
-func (v *Validator) getField(field string) (interface{}, error) {
-	r := reflect.ValueOf(v)
-	val := reflect.Indirect(r).FieldByName(strings.Title(field))
-
-	if !val.IsValid() {
-		return 0, errors.New("Formula is not valid")
-	}
-
-	f := val.Interface()
-
-	if f == nil {
-		return 0, nil
-	}
-
-	switch f.(type) {
-	case *float64:
-		v := getInterfaceValue(f)
-		result, _ := strconv.ParseFloat(v, 64)
-
-		return result, nil
-	case *int64:
-		v := getInterfaceValue(f)
-		result, _ := strconv.ParseInt(v, 10, 64)
-
-		return result, nil
-	}
-
-	result := getInterfaceValue(f)
-
-	return result, nil
+	return *v.MaxValue, nil
 }
 
 func contains(strings []string, str string) bool {
@@ -338,12 +335,3 @@ func getFloat(unk interface{}) (float64, error) {
 	fv := v.Convert(floatType)
 	return fv.Float(), nil
 }
-
-func remove(s []string, r string) []string {
-	for i, v := range s {
-		if v == r {
-			return append(s[:i], s[i+1:]...)
-		}
-	}
-	return s
-}
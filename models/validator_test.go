@@ -1,7 +1,6 @@
 package models_test
 
 import (
-	"errors"
 	"gade/srv-gade-point/models"
 	"testing"
 
@@ -50,8 +49,52 @@ func TestValidate(t *testing.T) {
 
 	// when its not valid
 	plValidator.Validators.Channel = "cacing"
-	err := errors.New("channel on this transaction is not valid to use the benefit")
+	err := models.ValidationErrors{{
+		Code:     models.ValidationCodeNotMatch,
+		Field:    "channel",
+		Message:  "channel on this transaction is not valid to use the benefit",
+		Expected: "pds",
+		Actual:   "cacing",
+	}}
 	assert.Equal(t, err, validator.Validate(&plValidator))
+	assert.EqualError(t, validator.Validate(&plValidator), "channel on this transaction is not valid to use the benefit")
+
+	// when many fields are not valid, all of them are reported
+	plValidator.Validators.Product = "GD"
+	lowTrx := float64(1000)
+	plValidator.TransactionAmount = &lowTrx
+	validationErrs := models.GetValidationErrors(validator.Validate(&plValidator))
+	assert.Equal(t, 3, len(validationErrs))
+	assert.Equal(t, models.ValidationError{
+		Code:     models.ValidationCodeBelowMinimum,
+		Field:    "minTransactionAmount",
+		Message:  "minTransactionAmount on this transaction is not valid to use the benefit",
+		Expected: minTrx,
+		Actual:   lowTrx,
+	}, validationErrs[1])
+	assert.Equal(t, "product", validationErrs[2].Field)
+	plValidator.Validators.Channel = "pds"
+	plValidator.Validators.Product = "TE"
+	plValidator.TransactionAmount = &trx
+}
+
+func TestValidateRule(t *testing.T) {
+	// when its valid
+	assert.Equal(t, mltplFunc("", nil), mltplFunc(validator.ValidateRule(&plValidator)))
+
+	// when its not valid
+	plValidator.Validators.Channel = "cacing"
+	attribute, err := validator.ValidateRule(&plValidator)
+	assert.Equal(t, "channel", attribute)
+	assert.EqualError(t, err, "channel on this transaction is not valid to use the benefit")
+	plValidator.Validators.Channel = "pds"
+
+	// when validator is not available
+	var nilValidator *models.Validator
+	attribute, err = nilValidator.ValidateRule(&plValidator)
+	assert.Equal(t, "validators", attribute)
+	assert.Equal(t, models.ValidationCodeUnavailable, models.GetValidationErrors(err)[0].Code)
+	assert.EqualError(t, err, models.ErrValidatorUnavailable.Error())
 }
 
 func TestGetFormulaResult(t *testing.T) {
@@ -63,6 +106,7 @@ func TestGetFormulaResult(t *testing.T) {
 	// when formula is not available
 	validator.Formula = ""
 	assert.Equal(t, mltplFunc(float64(0), nil), mltplFunc(validator.GetFormulaResult(&plValidator)))
+	plValidator.TransactionAmount = &trx
 }
 
 func TestCalculateDiscount(t *testing.T) {
@@ -137,6 +181,83 @@ func TestGetRewardValue(t *testing.T) {
 	assert.Equal(t, mltplFunc(float64(25000), nil), mltplFunc(validator.GetRewardValue(&plValidator)))
 }
 
+func TestGetRewardValueTiers(t *testing.T) {
+	oneMillion, fiveMillion, twentyMillion := float64(1000000), float64(5000000), float64(20000000)
+	halfPercent, onePercent, oneHalfPercent := float64(0.5), float64(1), float64(1.5)
+	maxCashback := float64(250000)
+	tieredValidator := models.Validator{
+		Channel:         "pds",
+		Product:         "TE",
+		TransactionType: "OP",
+		TierBy:          models.TierByTransactionAmount,
+		Tiers: []models.Tier{
+			{Min: &oneMillion, Max: &fiveMillion, Discount: &halfPercent},
+			{Min: &fiveMillion, Max: &twentyMillion, Discount: &onePercent},
+			{Min: &twentyMillion, Discount: &oneHalfPercent, MaxValue: &maxCashback},
+		},
+	}
+	tests := []struct {
+		amount   float64
+		expected float64
+	}{
+		{amount: 2000000, expected: 10000},
+		{amount: 5000000, expected: 50000},
+		{amount: 10000000, expected: 100000},
+		{amount: 100000000, expected: 250000},
+	}
+
+	for _, test := range tests {
+		amount := test.amount
+		payload := plValidator
+		payload.TransactionAmount = &amount
+
+		assert.Equal(t, mltplFunc(test.expected, nil), mltplFunc(tieredValidator.GetRewardValue(&payload)))
+	}
+
+	// when there is no matching tier
+	amount := float64(500000)
+	payload := plValidator
+	payload.TransactionAmount = &amount
+	rwdValue, err := tieredValidator.GetRewardValue(&payload)
+	assert.Equal(t, float64(0), rwdValue)
+	assert.Equal(t, models.ValidationErrors{{
+		Code:     models.ValidationCodeTierNotMatch,
+		Field:    models.TierByTransactionAmount,
+		Message:  "transactionAmount on this transaction is not valid to use the benefit",
+		Expected: []string{"[1000000, 5000000)", "[5000000, 20000000)", "[20000000, -)"},
+		Actual:   amount,
+	}}, err)
+
+	// when the tiers is keyed by loan amount
+	tieredValidator.TierBy = models.TierByLoanAmount
+	payload.LoanAmount = &fiveMillion
+	assert.Equal(t, mltplFunc(float64(5000), nil), mltplFunc(tieredValidator.GetRewardValue(&payload)))
+}
+
+func TestCheckTiers(t *testing.T) {
+	one, five, ten, twenty := float64(1), float64(5), float64(10), float64(20)
+	tests := []struct {
+		tierBy string
+		tiers  []models.Tier
+		valid  bool
+	}{
+		{models.TierByTransactionAmount, []models.Tier{{Min: &ten, Max: &twenty}, {Max: &ten}, {Min: &twenty}}, true},
+		{models.TierByLoanAmount, []models.Tier{{Min: &one, Max: &five}, {Min: &ten, Formula: "loanAmount * 0.01"}}, true},
+		{"", []models.Tier{{Min: &one}}, false},
+		{models.TierByTransactionAmount, []models.Tier{{Min: &one, Max: &ten}, {Min: &five, Max: &twenty}}, false},
+		{models.TierByTransactionAmount, []models.Tier{{Min: &one}, {Min: &ten}}, false},
+		{models.TierByTransactionAmount, []models.Tier{{Max: &ten}, {Max: &twenty}}, false},
+		{models.TierByTransactionAmount, []models.Tier{{Min: &ten, Max: &five}}, false},
+		{models.TierByTransactionAmount, []models.Tier{{Min: &one, Formula: "cif * 2"}}, false},
+	}
+
+	for i, test := range tests {
+		tieredValidator := models.Validator{TierBy: test.tierBy, Tiers: test.tiers}
+
+		assert.Equal(t, test.valid, tieredValidator.Check() == nil, i)
+	}
+}
+
 func mltplFunc(a, b interface{}) []interface{} {
 	return []interface{}{a, b}
 }
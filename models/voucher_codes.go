@@ -2,6 +2,21 @@ package models
 
 import "time"
 
+var (
+	// VoucherCodeStatusAvailable to store voucher code available status
+	VoucherCodeStatusAvailable int8
+	// VoucherCodeStatusBought to store voucher code bought status
+	VoucherCodeStatusBought int8 = 1
+	// VoucherCodeStatusRedeemed to store voucher code redeemed status
+	VoucherCodeStatusRedeemed int8 = 2
+	// VoucherCodeStatusExpired to store voucher code expired status
+	VoucherCodeStatusExpired int8 = 3
+	// VoucherCodeStatusReserved to store voucher code reserved by a reward transaction status
+	VoucherCodeStatusReserved int8 = 4
+	// VoucherCodeStatusVoid to store voucher code voided by a reversed reward transaction status
+	VoucherCodeStatusVoid int8 = 5
+)
+
 // VoucherCode to store a voucher code data
 type VoucherCode struct {
 	ID           int64      `json:"id,omitempty"`
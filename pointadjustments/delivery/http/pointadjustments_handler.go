@@ -0,0 +1,217 @@
+package http
+
+import (
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointadjustments"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+var response models.Response
+
+// PointAdjustmentsHandler represent the httphandler for point adjustments
+type PointAdjustmentsHandler struct {
+	PointAdjustmentUseCase pointadjustments.UseCase
+}
+
+// NewPointAdjustmentsHandler represent to register point adjustments endpoint
+func NewPointAdjustmentsHandler(echoGroup models.EchoGroup, adjUs pointadjustments.UseCase) {
+	handler := &PointAdjustmentsHandler{
+		PointAdjustmentUseCase: adjUs,
+	}
+
+	// End Point For CMS
+	echoGroup.Admin.POST("/point/adjustments", handler.submitAdjustment)
+	echoGroup.Admin.GET("/point/adjustments", handler.getAdjustments)
+	echoGroup.Admin.GET("/point/adjustments/:id", handler.getAdjustment)
+	echoGroup.Admin.POST("/point/adjustments/:id/approve", handler.approveAdjustment)
+	echoGroup.Admin.POST("/point/adjustments/:id/reject", handler.rejectAdjustment)
+}
+
+func (adj *PointAdjustmentsHandler) submitAdjustment(echTx echo.Context) error {
+	var adjustment models.PointAdjustment
+	response = models.Response{}
+	err := echTx.Bind(&adjustment)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(adjustment); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, adjustment)
+	requestLogger.Info("Start to submit a point adjustment.")
+	err = adj.PointAdjustmentUseCase.Submit(echTx, &adjustment)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageSaveSuccess
+	response.Data = adjustment
+	requestLogger.Info("End of submit a point adjustment.")
+
+	return echTx.JSON(http.StatusCreated, response)
+}
+
+func (adj *PointAdjustmentsHandler) getAdjustments(echTx echo.Context) error {
+	response = models.Response{}
+	payload := map[string]interface{}{
+		"status":     echTx.QueryParam("status"),
+		"cif":        echTx.QueryParam("cif"),
+		"createdBy":  echTx.QueryParam("createdBy"),
+		"reviewedBy": echTx.QueryParam("reviewedBy"),
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get point adjustments.")
+
+	for _, key := range []string{"page", "limit"} {
+		value, err := strconv.Atoi("0" + echTx.QueryParam(key))
+
+		if err != nil {
+			requestLogger.Debug(err)
+			response.Status = models.StatusError
+			response.Message = http.StatusText(http.StatusBadRequest)
+
+			return echTx.JSON(http.StatusBadRequest, response)
+		}
+
+		payload[key] = value
+	}
+
+	data, counter, err := adj.PointAdjustmentUseCase.GetAdjustments(echTx, payload)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.TotalCount = counter
+	requestLogger.Info("End of get point adjustments.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (adj *PointAdjustmentsHandler) getAdjustment(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get detail point adjustment.")
+	data, err := adj.PointAdjustmentUseCase.GetAdjustment(echTx, echTx.Param("id"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = data
+	requestLogger.Info("End of get detail point adjustment.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (adj *PointAdjustmentsHandler) approveAdjustment(echTx echo.Context) error {
+	return adj.reviewAdjustment(echTx, "approve", adj.PointAdjustmentUseCase.Approve)
+}
+
+func (adj *PointAdjustmentsHandler) rejectAdjustment(echTx echo.Context) error {
+	return adj.reviewAdjustment(echTx, "reject", adj.PointAdjustmentUseCase.Reject)
+}
+
+func (adj *PointAdjustmentsHandler) reviewAdjustment(echTx echo.Context, action string,
+	review func(echo.Context, string, *models.PayloadPointAdjustmentReview) (*models.PointAdjustment, error)) error {
+	var plReview models.PayloadPointAdjustmentReview
+	response = models.Response{}
+	err := echTx.Bind(&plReview)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plReview); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, plReview)
+	requestLogger.Info("Start to " + action + " a point adjustment.")
+	data, err := review(echTx, echTx.Param("id"), &plReview)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = data
+	requestLogger.Info("End of " + action + " a point adjustment.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	if strings.Contains(err.Error(), "400") {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case models.ErrNotFound, models.ErrNoAdjustment:
+		return http.StatusNotFound
+	case models.ErrConflict, models.ErrAdjustmentReviewed:
+		return http.StatusConflict
+	case models.ErrAdjustmentActor, models.ErrAdjustmentSelfReview:
+		return http.StatusForbidden
+	case models.ErrAdjustmentDeficit:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,16 @@
+package pointadjustments
+
+import (
+	"gade/srv-gade-point/models"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the point adjustments repository contract
+type Repository interface {
+	Create(echo.Context, *models.PointAdjustment) error
+	GetAdjustments(echo.Context, map[string]interface{}) ([]models.PointAdjustment, error)
+	CountAdjustments(echo.Context, map[string]interface{}) (string, error)
+	GetAdjustment(echo.Context, int64) (*models.PointAdjustment, error)
+	Review(echo.Context, *models.PointAdjustment, *models.PointHistory) error
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointadjustments"
+	"gade/srv-gade-point/pointhistories"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/lib/pq"
+)
+
+type psqlPointAdjustmentRepository struct {
+	Conn         *sql.DB
+	pHistoryRepo pointhistories.Repository
+}
+
+// NewPsqlPointAdjustmentRepository will create an object that represent the pointadjustments.Repository interface
+func NewPsqlPointAdjustmentRepository(Conn *sql.DB, pHistoryRepo pointhistories.Repository) pointadjustments.Repository {
+	return &psqlPointAdjustmentRepository{Conn, pHistoryRepo}
+}
+
+func (adjRepo *psqlPointAdjustmentRepository) Create(c echo.Context, adjustment *models.PointAdjustment) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `INSERT INTO point_adjustments (cif, transaction_type, point_amount, reason, ticket_ref, status,
+		created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	tx, err := adjRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	err = tx.QueryRow(query, adjustment.CIF, adjustment.TransactionType, adjustment.PointAmount, adjustment.Reason,
+		adjustment.TicketRef, adjustment.Status, adjustment.CreatedBy, &now).Scan(&adjustment.ID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	err = createLog(tx, adjustment.ID, models.PointAdjustmentLog{
+		Action: models.PointAdjustmentActionSubmit, Actor: adjustment.CreatedBy, Note: adjustment.Reason, CreatedAt: &now,
+	})
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	adjustment.CreatedAt = &now
+
+	return nil
+}
+
+func (adjRepo *psqlPointAdjustmentRepository) GetAdjustments(c echo.Context, payload map[string]interface{}) ([]models.PointAdjustment, error) {
+	var result []models.PointAdjustment
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	where, args := getAdjustmentsFilter(payload)
+	paging := ""
+
+	if payload["page"].(int) > 0 && payload["limit"].(int) > 0 {
+		args = append(args, payload["limit"].(int), (payload["page"].(int)-1)*payload["limit"].(int))
+		paging = fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	query := `SELECT id, cif, transaction_type, point_amount, reason, ticket_ref, status, created_by,
+		coalesce(reviewed_by, ''), coalesce(review_note, ''), reviewed_at, point_history_id, updated_at, created_at
+		FROM point_adjustments WHERE id IS NOT NULL` + where + ` ORDER BY created_at DESC, id DESC` + paging
+	rows, err := adjRepo.Conn.Query(query, args...)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		adjustment, err := scanAdjustment(rows)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		result = append(result, *adjustment)
+	}
+
+	return result, nil
+}
+
+func (adjRepo *psqlPointAdjustmentRepository) CountAdjustments(c echo.Context, payload map[string]interface{}) (string, error) {
+	var counter string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	where, args := getAdjustmentsFilter(payload)
+	query := `SELECT COUNT(id) FROM point_adjustments WHERE id IS NOT NULL` + where
+	err := adjRepo.Conn.QueryRow(query, args...).Scan(&counter)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return "", err
+	}
+
+	return counter, nil
+}
+
+func (adjRepo *psqlPointAdjustmentRepository) GetAdjustment(c echo.Context, id int64) (*models.PointAdjustment, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT id, cif, transaction_type, point_amount, reason, ticket_ref, status, created_by,
+		coalesce(reviewed_by, ''), coalesce(review_note, ''), reviewed_at, point_history_id, updated_at, created_at
+		FROM point_adjustments WHERE id = $1`
+	adjustment, err := scanAdjustment(adjRepo.Conn.QueryRow(query, id))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	query = `SELECT id, action, actor, coalesce(note, ''), created_at FROM point_adjustment_logs
+		WHERE adjustment_id = $1 ORDER BY created_at ASC, id ASC`
+	rows, err := adjRepo.Conn.Query(query, id)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log models.PointAdjustmentLog
+		var createdAt pq.NullTime
+
+		if err = rows.Scan(&log.ID, &log.Action, &log.Actor, &log.Note, &createdAt); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		log.CreatedAt = &createdAt.Time
+		adjustment.Logs = append(adjustment.Logs, log)
+	}
+
+	return adjustment, nil
+}
+
+func (adjRepo *psqlPointAdjustmentRepository) Review(c echo.Context, adjustment *models.PointAdjustment,
+	pointHistory *models.PointHistory) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	action := models.PointAdjustmentActionReject
+	tx, err := adjRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	// only a pending adjustment is updated, so two reviewers can never post the same adjustment
+	query := `UPDATE point_adjustments SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4,
+		updated_at = $4 WHERE id = $5 AND status = $6`
+	result, err := tx.Exec(query, adjustment.Status, adjustment.ReviewedBy, adjustment.ReviewNote, &now,
+		adjustment.ID, models.PointAdjustmentPending)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if counter, err := result.RowsAffected(); err != nil || counter == 0 {
+		requestLogger.Debug(models.ErrAdjustmentReviewed)
+		_ = tx.Rollback()
+
+		return models.ErrAdjustmentReviewed
+	}
+
+	if pointHistory != nil {
+		action = models.PointAdjustmentActionApprove
+
+		if err = adjRepo.pHistoryRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return err
+		}
+
+		query = `UPDATE point_adjustments SET point_history_id = $1 WHERE id = $2`
+
+		if _, err = tx.Exec(query, pointHistory.ID, adjustment.ID); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return err
+		}
+
+		adjustment.PointHistoryID = &pointHistory.ID
+	}
+
+	err = createLog(tx, adjustment.ID, models.PointAdjustmentLog{
+		Action: action, Actor: adjustment.ReviewedBy, Note: adjustment.ReviewNote, CreatedAt: &now,
+	})
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	adjustment.ReviewedAt = &now
+	adjustment.UpdatedAt = &now
+
+	return nil
+}
+
+func createLog(tx *sql.Tx, adjustmentID int64, log models.PointAdjustmentLog) error {
+	query := `INSERT INTO point_adjustment_logs (adjustment_id, action, actor, note, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+	_, err := tx.Exec(query, adjustmentID, log.Action, log.Actor, log.Note, log.CreatedAt)
+
+	return err
+}
+
+func getAdjustmentsFilter(payload map[string]interface{}) (string, []interface{}) {
+	var args []interface{}
+	where := ""
+
+	for _, filter := range []struct{ key, column string }{
+		{"status", "status"}, {"cif", "cif"}, {"createdBy", "created_by"}, {"reviewedBy", "reviewed_by"},
+	} {
+		if value, ok := payload[filter.key]; ok && value != "" {
+			args = append(args, value)
+			where += fmt.Sprintf(" AND %s = $%d", filter.column, len(args))
+		}
+	}
+
+	return where, args
+}
+
+type scanner interface {
+	Scan(...interface{}) error
+}
+
+func scanAdjustment(row scanner) (*models.PointAdjustment, error) {
+	var adjustment models.PointAdjustment
+	var reviewedAt, updatedAt, createdAt pq.NullTime
+	var pointHistoryID sql.NullInt64
+
+	err := row.Scan(
+		&adjustment.ID, &adjustment.CIF, &adjustment.TransactionType, &adjustment.PointAmount, &adjustment.Reason,
+		&adjustment.TicketRef, &adjustment.Status, &adjustment.CreatedBy, &adjustment.ReviewedBy,
+		&adjustment.ReviewNote, &reviewedAt, &pointHistoryID, &updatedAt, &createdAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reviewedAt.Valid {
+		adjustment.ReviewedAt = &reviewedAt.Time
+	}
+
+	if pointHistoryID.Valid {
+		adjustment.PointHistoryID = &pointHistoryID.Int64
+	}
+
+	adjustment.UpdatedAt = &updatedAt.Time
+	adjustment.CreatedAt = &createdAt.Time
+
+	return &adjustment, nil
+}
@@ -0,0 +1,16 @@
+package pointadjustments
+
+import (
+	"gade/srv-gade-point/models"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the point adjustments usecases
+type UseCase interface {
+	Submit(echo.Context, *models.PointAdjustment) error
+	GetAdjustments(echo.Context, map[string]interface{}) ([]models.PointAdjustment, string, error)
+	GetAdjustment(echo.Context, string) (*models.PointAdjustment, error)
+	Approve(echo.Context, string, *models.PayloadPointAdjustmentReview) (*models.PointAdjustment, error)
+	Reject(echo.Context, string, *models.PayloadPointAdjustmentReview) (*models.PointAdjustment, error)
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointadjustments"
+	"gade/srv-gade-point/users"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+type pointAdjustmentUseCase struct {
+	adjustmentRepo pointadjustments.Repository
+	userRepo       users.Repository
+}
+
+// NewPointAdjustmentUseCase will create new an pointAdjustmentUseCase object representation of pointadjustments.UseCase interface
+func NewPointAdjustmentUseCase(adjRepo pointadjustments.Repository, usrRepo users.Repository) pointadjustments.UseCase {
+	return &pointAdjustmentUseCase{
+		adjustmentRepo: adjRepo,
+		userRepo:       usrRepo,
+	}
+}
+
+func (adj *pointAdjustmentUseCase) Submit(c echo.Context, adjustment *models.PointAdjustment) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	maker, err := adj.checkAdmin(c)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	adjustment.CreatedBy = maker
+
+	pointAmount := float64(int64(*adjustment.PointAmount))
+
+	if pointAmount <= 0 {
+		requestLogger.Debug(models.ErrAdjustmentFailed)
+
+		return models.ErrAdjustmentFailed
+	}
+
+	adjustment.PointAmount = &pointAmount
+	adjustment.Status = &models.PointAdjustmentPending
+	adjustment.ReviewedBy = ""
+	adjustment.ReviewNote = ""
+
+	if err = adj.adjustmentRepo.Create(c, adjustment); err != nil {
+		requestLogger.Debug(models.ErrAdjustmentFailed)
+
+		return models.ErrAdjustmentFailed
+	}
+
+	return nil
+}
+
+func (adj *pointAdjustmentUseCase) GetAdjustments(c echo.Context, payload map[string]interface{}) ([]models.PointAdjustment, string, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	counter, err := adj.adjustmentRepo.CountAdjustments(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetAdjustment)
+
+		return nil, "", models.ErrGetAdjustment
+	}
+
+	data, err := adj.adjustmentRepo.GetAdjustments(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetAdjustment)
+
+		return nil, "", models.ErrGetAdjustment
+	}
+
+	return data, counter, nil
+}
+
+func (adj *pointAdjustmentUseCase) GetAdjustment(c echo.Context, id string) (*models.PointAdjustment, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	adjustmentID, err := strconv.ParseInt(id, 10, 64)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, errors.New("Something went wrong with input ID")
+	}
+
+	adjustment, err := adj.adjustmentRepo.GetAdjustment(c, adjustmentID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrNoAdjustment)
+
+		return nil, models.ErrNoAdjustment
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetAdjustment)
+
+		return nil, models.ErrGetAdjustment
+	}
+
+	return adjustment, nil
+}
+
+func (adj *pointAdjustmentUseCase) Approve(c echo.Context, id string, review *models.PayloadPointAdjustmentReview) (*models.PointAdjustment, error) {
+	return adj.review(c, id, review, models.PointAdjustmentApproved)
+}
+
+func (adj *pointAdjustmentUseCase) Reject(c echo.Context, id string, review *models.PayloadPointAdjustmentReview) (*models.PointAdjustment, error) {
+	return adj.review(c, id, review, models.PointAdjustmentRejected)
+}
+
+func (adj *pointAdjustmentUseCase) review(c echo.Context, id string, review *models.PayloadPointAdjustmentReview,
+	status int64) (*models.PointAdjustment, error) {
+	var pointHistory *models.PointHistory
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	reviewer, err := adj.checkAdmin(c)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	adjustment, err := adj.GetAdjustment(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = adjustment.Review(reviewer); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	adjustment.Status = &status
+	adjustment.ReviewedBy = reviewer
+	adjustment.ReviewNote = review.Note
+
+	// only an approved adjustment is posted to the point history
+	if status == models.PointAdjustmentApproved {
+		if pointHistory, err = adjustment.GetPointHistory(time.Now()); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, models.ErrReviewAdjustment
+		}
+	}
+
+	err = adj.adjustmentRepo.Review(c, adjustment, pointHistory)
+
+	switch err {
+	case nil:
+		adjustment.Logs = nil
+
+		return adjustment, nil
+	case models.ErrAdjustmentReviewed:
+		return nil, err
+	case models.ErrPointDeficit:
+		requestLogger.Debug(models.ErrAdjustmentDeficit)
+
+		return nil, models.ErrAdjustmentDeficit
+	}
+
+	requestLogger.Debug(models.ErrReviewAdjustment)
+
+	return nil, models.ErrReviewAdjustment
+}
+
+// checkAdmin to get the username of the logged in admin user that makes or checks an adjustment,
+// the token of a service account is shared, so it is never an actor
+func (adj *pointAdjustmentUseCase) checkAdmin(c echo.Context) (string, error) {
+	claims := models.GetTokenUser(c)
+
+	if claims == nil {
+		return "", models.ErrAdjustmentActor
+	}
+
+	user := &models.User{Username: claims.Name}
+
+	if err := adj.userRepo.GetByUsername(c.Request().Context(), user); err != nil {
+		return "", models.ErrAdjustmentActor
+	}
+
+	if user.ID != claims.UserID || user.Role == nil || *user.Role != models.UserRoleAdmin {
+		return "", models.ErrAdjustmentActor
+	}
+
+	return user.Username, nil
+}
@@ -26,10 +26,12 @@ func NewPointHistoriesHandler(echoGroup models.EchoGroup, pointHistUs pointhisto
 	echoGroup.Admin.POST("/point", handler.GetUserPoint)
 	echoGroup.Admin.POST("/point/history", handler.GetUserPointHistory)
 	echoGroup.Admin.POST("/point/histories", handler.GetUsers)
+	echoGroup.Admin.POST("/point/expiry", handler.GetUserPointExpiry)
 
 	// End Point For External
 	echoGroup.API.POST("/point", handler.GetUserPoint)
 	echoGroup.API.POST("/point/history", handler.GetUserPointHistory)
+	echoGroup.API.POST("/point/expiry", handler.GetUserPointExpiry)
 }
 
 // GetUsers a handler to create a pointHistory
@@ -224,6 +226,45 @@ func (pointHist *PointHistoriesHandler) GetUserPoint(c echo.Context) error {
 
 }
 
+// GetUserPointExpiry to get how many points of a user expire and when
+func (pointHist *PointHistoriesHandler) GetUserPointExpiry(c echo.Context) error {
+	response = models.Response{}
+	CIF := c.QueryParam("CIF")
+	payload := map[string]interface{}{
+		"CIF": CIF,
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(c, payload)
+	requestLogger.Info("Start to get user point expiry.")
+
+	if CIF == "" {
+		response.Status = models.StatusError
+		response.Message = http.StatusText(http.StatusBadRequest)
+
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	data, err := pointHist.PointHistoryUseCase.GetUserPointExpiry(c, CIF)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return c.JSON(getStatusCode(err), response)
+	}
+
+	response.Data = data
+	response.Status = models.StatusSuccess
+	response.Message = models.MessagePointSuccess
+	requestLogger.Info("End of get user point expiry.")
+
+	return c.JSON(http.StatusOK, response)
+}
+
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
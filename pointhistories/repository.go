@@ -1,16 +1,28 @@
 package pointhistories
 
 import (
+	"database/sql"
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
 
 // Repository represent the pointhistories repository contract
 type Repository interface {
+	Create(echo.Context, *models.PointHistory) error
 	GetUsers(echo.Context, map[string]interface{}) ([]models.PointHistory, error)
 	CountUsers(echo.Context, map[string]interface{}) (string, error)
 	CountUserPointHistory(echo.Context, map[string]interface{}) (string, error)
 	GetUserPointHistory(echo.Context, map[string]interface{}) ([]models.PointHistory, error)
 	GetUserPoint(echo.Context, string) (float64, error)
+	GetPendingPoint(echo.Context, string) (float64, error)
+	GetPointLots(echo.Context, string) ([]models.PointLot, int64, error)
+	GetExpiredCIFs(echo.Context, time.Time) ([]string, error)
+	ExpirePoint(echo.Context, string, time.Time) (int64, error)
+	CreatePointHistory(*sql.Tx, *models.PointHistory, time.Time) error
+	SucceedPointHistories(*sql.Tx, string, string, time.Time) error
+	DeletePendingPointHistories(*sql.Tx, string) error
+	GetRemainingPointLots(*sql.Tx, string) ([]models.PointLot, error)
+	GetSucceededPointHistories(*sql.Tx, string) ([]*models.PointHistory, error)
 }
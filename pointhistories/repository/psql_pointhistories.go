@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/pointhistories"
+	"time"
 
 	"github.com/labstack/echo"
+	"github.com/lib/pq"
 )
 
 const (
@@ -22,18 +24,416 @@ func NewPsqlPointHistoryRepository(Conn *sql.DB) pointhistories.Repository {
 	return &psqlPointHistoryRepository{Conn}
 }
 
+func (psqlRepo *psqlPointHistoryRepository) Create(c echo.Context, pointHistory *models.PointHistory) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	tx, err := psqlRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	if err = psqlRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (psqlRepo *psqlPointHistoryRepository) GetPointLots(c echo.Context, CIF string) ([]models.PointLot, int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	lots, spent, err := getPointLots(psqlRepo.Conn, CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, 0, err
+	}
+
+	return lots, spent, nil
+}
+
+func (psqlRepo *psqlPointHistoryRepository) GetExpiredCIFs(c echo.Context, now time.Time) ([]string, error) {
+	var CIFs []string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// only the lots that are expired after the last expiry of the account are left to expire
+	query := `SELECT DISTINCT pa.code FROM point_journals pj
+		JOIN point_entries pe ON pe.journal_id = pj.id AND pe.amount > 0
+		JOIN point_accounts pa ON pa.id = pe.account_id AND pa.type = $1
+		WHERE pj.expired_date <= $2 AND (pa.expired_at IS NULL OR pj.expired_date > pa.expired_at)`
+	rows, err := psqlRepo.Conn.Query(query, models.PointAccountTypeCustomer, now)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var CIF string
+
+		if err = rows.Scan(&CIF); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		CIFs = append(CIFs, CIF)
+	}
+
+	return CIFs, nil
+}
+
+func (psqlRepo *psqlPointHistoryRepository) ExpirePoint(c echo.Context, CIF string, now time.Time) (int64, error) {
+	var accountID int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	tx, err := psqlRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	// lock the account, so no other journal is spending the lots in the meantime
+	query := `SELECT id FROM point_accounts WHERE code = $1 AND type = $2 FOR UPDATE`
+	err = tx.QueryRow(query, CIF, models.PointAccountTypeCustomer).Scan(&accountID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	lots, spent, err := getPointLots(tx, CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	expired := models.GetExpiredPoint(models.SpendPointLots(lots, spent), now)
+
+	if expired > 0 {
+		pointAmount := float64(expired)
+		pointHistory := &models.PointHistory{
+			CIF:             CIF,
+			PointAmount:     &pointAmount,
+			TransactionType: models.TransactionPointTypeKredit,
+			TransactionDate: &now,
+			UsedFor:         models.PointUsedForExpiry,
+			Status:          &models.PointHistoryStatusSuccess,
+		}
+
+		if err = psqlRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE point_accounts SET expired_at = $1 WHERE id = $2`, now, accountID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// CreatePointHistory to store the point history within the transaction, a succeeded point history
+// is posted to the ledger as well. It is shared with the repositories that post points along their own changes
+func (psqlRepo *psqlPointHistoryRepository) CreatePointHistory(tx *sql.Tx, pointHistory *models.PointHistory, now time.Time) error {
+	var rewardID, voucherCodeID *int64
+	query := `INSERT INTO point_histories (cif, point_amount, transaction_type, transaction_date, used_for, ref_core,
+		ref_id, status, reward_id, voucher_code_id, expired_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+
+	if pointHistory.Reward != nil {
+		rewardID = &pointHistory.Reward.ID
+	}
+
+	if pointHistory.VoucherCode != nil {
+		voucherCodeID = &pointHistory.VoucherCode.ID
+	}
+
+	err := tx.QueryRow(query,
+		&pointHistory.CIF, &pointHistory.PointAmount, &pointHistory.TransactionType, &pointHistory.TransactionDate,
+		&pointHistory.UsedFor, &pointHistory.RefCore, &pointHistory.RefID, &pointHistory.Status, rewardID,
+		voucherCodeID, pointHistory.ExpiredDate, &now,
+	).Scan(&pointHistory.ID)
+
+	if err != nil {
+		return err
+	}
+
+	pointHistory.CreatedAt = &now
+
+	// only a succeeded point history moves the balance
+	if pointHistory.Status == nil || *pointHistory.Status != models.PointHistoryStatusSuccess {
+		return nil
+	}
+
+	journal, err := models.NewPointJournal(pointHistory)
+
+	if err != nil {
+		return err
+	}
+
+	return postJournal(tx, journal, now)
+}
+
+// SucceedPointHistories to make the pending point histories of a reward transaction spendable within the transaction,
+// they take the core reference of the succeeded transaction and are posted to the ledger
+func (psqlRepo *psqlPointHistoryRepository) SucceedPointHistories(tx *sql.Tx, refID, refCore string, now time.Time) error {
+	var pointHistories []*models.PointHistory
+	query := `UPDATE point_histories SET status = $1, ref_core = $2, updated_at = $3
+		WHERE ref_id = $4 AND status = $5 AND transaction_type = $6
+		RETURNING id, cif, point_amount, transaction_type, transaction_date, coalesce(used_for, ''), ref_id, expired_date`
+	rows, err := tx.Query(query, models.PointHistoryStatusSuccess, refCore, &now, refID,
+		models.PointHistoryStatusPending, models.TransactionPointTypeDebet)
+
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var expiredDate pq.NullTime
+		pointHistory := &models.PointHistory{RefCore: refCore, Status: &models.PointHistoryStatusSuccess}
+
+		err = rows.Scan(&pointHistory.ID, &pointHistory.CIF, &pointHistory.PointAmount, &pointHistory.TransactionType,
+			&pointHistory.TransactionDate, &pointHistory.UsedFor, &pointHistory.RefID, &expiredDate)
+
+		if err != nil {
+			rows.Close()
+
+			return err
+		}
+
+		if expiredDate.Valid {
+			pointHistory.ExpiredDate = &expiredDate.Time
+		}
+
+		pointHistories = append(pointHistories, pointHistory)
+	}
+
+	// the rows have to be closed before the transaction runs the next query
+	rows.Close()
+
+	for _, pointHistory := range pointHistories {
+		journal, err := models.NewPointJournal(pointHistory)
+
+		if err != nil {
+			return err
+		}
+
+		if err = postJournal(tx, journal, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeletePendingPointHistories to remove the pending point histories of a rejected or timed out reward transaction
+func (psqlRepo *psqlPointHistoryRepository) DeletePendingPointHistories(tx *sql.Tx, refID string) error {
+	query := `DELETE FROM point_histories WHERE ref_id = $1 AND status = $2 AND transaction_type = $3`
+	_, err := tx.Exec(query, refID, models.PointHistoryStatusPending, models.TransactionPointTypeDebet)
+
+	return err
+}
+
+// GetRemainingPointLots to get the lots of a customer with the points that are left of them within the transaction,
+// the account should be locked so no other journal is spending the lots in the meantime
+func (psqlRepo *psqlPointHistoryRepository) GetRemainingPointLots(tx *sql.Tx, CIF string) ([]models.PointLot, error) {
+	lots, spent, err := getPointLots(tx, CIF)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.SpendPointLots(lots, spent), nil
+}
+
+// GetSucceededPointHistories to get the succeeded point histories of a reference within the transaction in their order
+func (psqlRepo *psqlPointHistoryRepository) GetSucceededPointHistories(tx *sql.Tx, refID string) ([]*models.PointHistory, error) {
+	var pointHistories []*models.PointHistory
+	query := `SELECT id, cif, point_amount, transaction_type, transaction_date, coalesce(used_for, ''), ref_id, expired_date
+		FROM point_histories WHERE ref_id = $1 AND status = $2 ORDER BY id ASC`
+	rows, err := tx.Query(query, refID, models.PointHistoryStatusSuccess)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var expiredDate pq.NullTime
+		pointHistory := &models.PointHistory{Status: &models.PointHistoryStatusSuccess}
+
+		err = rows.Scan(&pointHistory.ID, &pointHistory.CIF, &pointHistory.PointAmount, &pointHistory.TransactionType,
+			&pointHistory.TransactionDate, &pointHistory.UsedFor, &pointHistory.RefID, &expiredDate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if expiredDate.Valid {
+			pointHistory.ExpiredDate = &expiredDate.Time
+		}
+
+		pointHistories = append(pointHistories, pointHistory)
+	}
+
+	return pointHistories, nil
+}
+
+// queryer is the common query methods of a db connection and a db transaction
+type queryer interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+	QueryRow(string, ...interface{}) *sql.Row
+}
+
+// getPointLots to get the earned lots of a customer from the oldest one and the total of spent points
+func getPointLots(conn queryer, CIF string) ([]models.PointLot, int64, error) {
+	var lots []models.PointLot
+	var spent int64
+	query := `SELECT pj.id, pe.amount, pj.transaction_date, pj.expired_date FROM point_entries pe
+		JOIN point_journals pj ON pj.id = pe.journal_id
+		JOIN point_accounts pa ON pa.id = pe.account_id
+		WHERE pa.code = $1 AND pa.type = $2 AND pe.amount > 0 ORDER BY pj.transaction_date ASC, pj.id ASC`
+	rows, err := conn.Query(query, CIF, models.PointAccountTypeCustomer)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var lot models.PointLot
+		var trxDate, expiredDate pq.NullTime
+
+		if err = rows.Scan(&lot.JournalID, &lot.Amount, &trxDate, &expiredDate); err != nil {
+			return nil, 0, err
+		}
+
+		lot.TransactionDate = &trxDate.Time
+
+		if expiredDate.Valid {
+			lot.ExpiredDate = &expiredDate.Time
+		}
+
+		lots = append(lots, lot)
+	}
+
+	query = `SELECT coalesce(-sum(pe.amount), 0) FROM point_entries pe
+		JOIN point_accounts pa ON pa.id = pe.account_id
+		WHERE pa.code = $1 AND pa.type = $2 AND pe.amount < 0`
+	err = conn.QueryRow(query, CIF, models.PointAccountTypeCustomer).Scan(&spent)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lots, spent, nil
+}
+
+// postJournal to store the journal entries and move the balance of each account within the transaction,
+// a customer balance is never allowed to go below zero unless the journal allows it
+func postJournal(tx *sql.Tx, journal *models.PointJournal, now time.Time) error {
+	if !journal.IsBalanced() {
+		return fmt.Errorf("point journal of %s %d is not balanced", journal.Source, journal.SourceID)
+	}
+
+	queryJournal := `INSERT INTO point_journals (journal_type, source, source_id, ref_id, ref_core, transaction_date,
+		expired_date, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err := tx.QueryRow(queryJournal, journal.JournalType, journal.Source, journal.SourceID, journal.RefID,
+		journal.RefCore, journal.TransactionDate, journal.ExpiredDate, &now).Scan(&journal.ID)
+
+	if err != nil {
+		return err
+	}
+
+	queryAccount := `INSERT INTO point_accounts (code, type, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (code) DO UPDATE SET code = EXCLUDED.code RETURNING id`
+	queryEntry := `INSERT INTO point_entries (journal_id, account_id, amount, created_at)
+		VALUES ($1, $2, $3, $4) RETURNING id`
+	queryBalance := `INSERT INTO point_balances (account_id, balance, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (account_id) DO UPDATE SET balance = point_balances.balance + EXCLUDED.balance,
+		updated_at = EXCLUDED.updated_at RETURNING balance`
+
+	for i := range journal.Entries {
+		var accountID, balance int64
+		entry := &journal.Entries[i]
+		err = tx.QueryRow(queryAccount, entry.AccountCode, entry.AccountType, &now).Scan(&accountID)
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(queryEntry, journal.ID, accountID, entry.Amount, &now).Scan(&entry.ID)
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(queryBalance, accountID, entry.Amount, &now).Scan(&balance)
+
+		if err != nil {
+			return err
+		}
+
+		if entry.AccountType == models.PointAccountTypeCustomer && entry.Amount < 0 && balance < 0 && !journal.AllowNegative {
+			return models.ErrPointDeficit
+		}
+	}
+
+	journal.CreatedAt = &now
+
+	return nil
+}
+
 func (psqlRepo *psqlPointHistoryRepository) CountUsers(c echo.Context, payload map[string]interface{}) (string, error) {
 	var counter string
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 
-	queryCounter := `SELECT COUNT(*) counter FROM
-					( SELECT CIF
-						FROM point_histories
-						group by CIF
-					) tmp;`
+	queryCounter := `SELECT COUNT(*) counter FROM point_accounts WHERE type = $1;`
 
-	err := psqlRepo.Conn.QueryRow(queryCounter).Scan(&counter)
+	err := psqlRepo.Conn.QueryRow(queryCounter, models.PointAccountTypeCustomer).Scan(&counter)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -50,16 +450,16 @@ func (psqlRepo *psqlPointHistoryRepository) GetUsers(c echo.Context, payload map
 	requestLogger := logger.GetRequestLogger(c, nil)
 	paging := ""
 
-	query := `SELECT ph.CIF, sum(tmp.conv_point_amt) point_amount FROM point_histories ph left join (select id, 
-		CASE WHEN transaction_type = 'K' THEN 0-point_amount ELSE point_amount end conv_point_amt from point_histories) tmp on tmp.id = ph.id
-		group by CIF order by point_amount desc`
+	query := `SELECT pa.code, coalesce(pb.balance, 0) point_amount FROM point_accounts pa
+		LEFT JOIN point_balances pb ON pb.account_id = pa.id
+		WHERE pa.type = $1 order by point_amount desc`
 
 	if payload["page"].(int) > 0 || payload["limit"].(int) > 0 {
 		paging = fmt.Sprintf(" LIMIT %d OFFSET %d", payload["limit"].(int), ((payload["page"].(int) - 1) * payload["limit"].(int)))
 	}
 
 	query += paging + ";"
-	rows, err := psqlRepo.Conn.Query(query)
+	rows, err := psqlRepo.Conn.Query(query, models.PointAccountTypeCustomer)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -124,24 +524,27 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPointHistory(c echo.Context,
 	startDateRg := payload["startDateRg"].(string)
 	endDateRg := payload["endDateRg"].(string)
 
-	// TODO update the query to a reward base
 	query := `select
 				ph.id,
 				ph.CIF,
 				ph.point_amount,
 				ph.transaction_type,
 				ph.transaction_date,
-				coalesce(ph.reff_core, '') reff_core,
-				coalesce(ph.campaign_id, 0) campaign_id,
-				coalesce(c.name, '') campaign_name,
-				coalesce(c.description, '') campaign_description,
+				ph.expired_date,
+				coalesce(ph.used_for, '') used_for,
+				coalesce(ph.ref_core, '') ref_core,
+				coalesce(ph.ref_id, '') ref_id,
+				ph.status,
+				coalesce(ph.reward_id, 0) reward_id,
+				coalesce(r.name, '') reward_name,
+				coalesce(r.description, '') reward_description,
 				coalesce(ph.voucher_code_id, 0) voucher_code_id,
 				coalesce(pc.promo_code, '') promo_code,
 				coalesce(pc.voucher_id, 0) voucher_id,
 				coalesce(v.name, '') voucher_name,
 				coalesce(v.description, '') voucher_description
 			from point_histories ph
-			left join campaigns c on ph.campaign_id = c.id
+			left join rewards r on ph.reward_id = r.id
 			left join voucher_codes pc on pc.id = ph.voucher_code_id
 			left join vouchers v on pc.voucher_id = v.id
 			where ph.CIF = $1`
@@ -169,7 +572,7 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPointHistory(c echo.Context,
 
 	for rows.Next() {
 		var ph models.PointHistory
-		var campaign models.Campaign
+		var reward models.Reward
 		var voucherCodes models.VoucherCode
 		var voucher models.Voucher
 
@@ -179,10 +582,14 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPointHistory(c echo.Context,
 			&ph.PointAmount,
 			&ph.TransactionType,
 			&ph.TransactionDate,
+			&ph.ExpiredDate,
+			&ph.UsedFor,
 			&ph.RefCore,
-			&campaign.ID,
-			&campaign.Name,
-			&campaign.Description,
+			&ph.RefID,
+			&ph.Status,
+			&reward.ID,
+			&reward.Name,
+			&reward.Description,
 			&voucherCodes.ID,
 			&voucherCodes.PromoCode,
 			&voucher.ID,
@@ -196,9 +603,8 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPointHistory(c echo.Context,
 			return nil, err
 		}
 
-		if campaign.ID != 0 {
-			// TODO change this to a reward base
-			// ph.Reward = &campaign
+		if reward.ID != 0 {
+			ph.Reward = &reward
 		}
 
 		if voucherCodes.ID != 0 {
@@ -217,13 +623,16 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPointHistory(c echo.Context,
 }
 
 func (psqlRepo *psqlPointHistoryRepository) GetUserPoint(c echo.Context, CIF string) (float64, error) {
+	var balance float64
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	var pointDebet float64
-	var pointKredit float64
-	queryDebet := `SELECT coalesce(sum(point_amount), 0) as debet FROM point_histories
-		WHERE cif = $1 AND transaction_type = 'D' AND to_char(transaction_date, 'YYYY') = to_char(NOW(), 'YYYY')`
-	err := psqlRepo.Conn.QueryRow(queryDebet, CIF).Scan(&pointDebet)
+	query := `SELECT pb.balance FROM point_accounts pa JOIN point_balances pb ON pb.account_id = pa.id
+		WHERE pa.code = $1 AND pa.type = $2`
+	err := psqlRepo.Conn.QueryRow(query, CIF, models.PointAccountTypeCustomer).Scan(&balance)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -231,9 +640,16 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPoint(c echo.Context, CIF str
 		return 0, err
 	}
 
-	queryKredit := `SELECT coalesce(sum(point_amount), 0) as debet FROM point_histories
-		WHERE cif = $1 AND transaction_type = 'K' AND to_char(transaction_date, 'YYYY') = to_char(NOW(), 'YYYY')`
-	err = psqlRepo.Conn.QueryRow(queryKredit, CIF).Scan(&pointKredit)
+	return balance, nil
+}
+
+func (psqlRepo *psqlPointHistoryRepository) GetPendingPoint(c echo.Context, CIF string) (float64, error) {
+	var pending float64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT coalesce(sum(point_amount), 0) FROM point_histories
+		WHERE cif = $1 AND status = $2 AND transaction_type = $3`
+	err := psqlRepo.Conn.QueryRow(query, CIF, models.PointHistoryStatusPending, models.TransactionPointTypeDebet).Scan(&pending)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -241,7 +657,5 @@ func (psqlRepo *psqlPointHistoryRepository) GetUserPoint(c echo.Context, CIF str
 		return 0, err
 	}
 
-	pointAmount := pointDebet - pointKredit
-
-	return pointAmount, nil
+	return pending, nil
 }
@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"gade/srv-gade-point/dbtest"
+	"gade/srv-gade-point/models"
+	_pointHistoryRepository "gade/srv-gade-point/pointhistories/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpirePointAfterEarnAndRedeem(t *testing.T) {
+	conn := dbtest.GetConn(t)
+	pHistoryRepo := _pointHistoryRepository.NewPsqlPointHistoryRepository(conn)
+	CIF := dbtest.UniqueCIF()
+	earnedDate := time.Date(2019, 1, 10, 0, 0, 0, 0, time.UTC)
+	now := earnedDate.AddDate(0, 6, 0)
+	pointHistories := []struct {
+		transactionType string
+		pointAmount     float64
+		expiredDate     time.Time
+	}{
+		{models.TransactionPointTypeDebet, 100, earnedDate.AddDate(0, 3, 0)},
+		{models.TransactionPointTypeDebet, 30, now.AddDate(0, 3, 0)},
+		{models.TransactionPointTypeKredit, 20, time.Time{}},
+	}
+
+	for i, ph := range pointHistories {
+		pointAmount := ph.pointAmount
+		transactionDate := earnedDate.AddDate(0, 0, i)
+		pointHistory := &models.PointHistory{
+			CIF:             CIF,
+			PointAmount:     &pointAmount,
+			TransactionType: ph.transactionType,
+			TransactionDate: &transactionDate,
+			Status:          &models.PointHistoryStatusSuccess,
+		}
+
+		if !ph.expiredDate.IsZero() {
+			expiredDate := ph.expiredDate
+			pointHistory.ExpiredDate = &expiredDate
+		}
+
+		assert.NoError(t, pHistoryRepo.Create(nil, pointHistory))
+	}
+
+	CIFs, err := pHistoryRepo.GetExpiredCIFs(nil, now)
+
+	assert.NoError(t, err)
+	assert.Contains(t, CIFs, CIF)
+
+	// the redeemed points are spent from the oldest lot, so only its rest is expired
+	expired, err := pHistoryRepo.ExpirePoint(nil, CIF, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(80), expired)
+
+	balance, err := pHistoryRepo.GetUserPoint(nil, CIF)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(30), balance)
+
+	CIFs, err = pHistoryRepo.GetExpiredCIFs(nil, now)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, CIFs, CIF)
+}
@@ -2,6 +2,7 @@ package pointhistories
 
 import (
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
@@ -11,4 +12,6 @@ type UseCase interface {
 	GetUsers(echo.Context, map[string]interface{}) ([]models.PointHistory, string, error)
 	GetUserPointHistory(echo.Context, map[string]interface{}) ([]models.PointHistory, string, error)
 	GetUserPoint(echo.Context, string) (*models.UserPoint, error)
+	GetUserPointExpiry(echo.Context, string) (*models.UserPointExpiry, error)
+	ExpirePoints(time.Time) (int64, error)
 }
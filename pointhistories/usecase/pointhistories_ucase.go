@@ -3,8 +3,10 @@ package usecase
 import (
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/pointhistories"
+	"time"
 
 	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
 )
 
 type pointHistoryUseCase struct {
@@ -79,14 +81,67 @@ func (pntHstryUs *pointHistoryUseCase) GetUserPoint(c echo.Context, CIF string)
 		return p, models.ErrGetUserPoint
 	}
 
-	if pointAmount == 0 {
-		requestLogger.Debug(models.ErrUserPointNA)
+	// the pending points are shown apart, they could not be spent until the transaction is succeeded
+	pendingPoint, err := pntHstryUs.pointHistoryRepo.GetPendingPoint(c, CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetUserPoint)
 		p.UserPoint = &zero
 
-		return p, models.ErrUserPointNA
+		return p, models.ErrGetUserPoint
 	}
 
 	p.UserPoint = &pointAmount
+	p.PendingPoint = &pendingPoint
+
+	if pointAmount == 0 && pendingPoint == 0 {
+		requestLogger.Debug(models.ErrUserPointNA)
+
+		return p, models.ErrUserPointNA
+	}
 
 	return p, nil
 }
+
+func (pntHstryUs *pointHistoryUseCase) GetUserPointExpiry(c echo.Context, CIF string) (*models.UserPointExpiry, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	lots, spent, err := pntHstryUs.pointHistoryRepo.GetPointLots(c, CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetUserPointExpiry)
+
+		return nil, models.ErrGetUserPointExpiry
+	}
+
+	return &models.UserPointExpiry{
+		CIF:         CIF,
+		Expirations: models.GetPointExpirations(models.SpendPointLots(lots, spent)),
+	}, nil
+}
+
+func (pntHstryUs *pointHistoryUseCase) ExpirePoints(now time.Time) (int64, error) {
+	var expired int64
+	CIFs, err := pntHstryUs.pointHistoryRepo.GetExpiredCIFs(nil, now)
+
+	if err != nil {
+		logrus.Debug("Expire Points: ", err)
+
+		return 0, err
+	}
+
+	// a failing account is retried on the next run, it should not hold the other accounts
+	for _, CIF := range CIFs {
+		counter, err := pntHstryUs.pointHistoryRepo.ExpirePoint(nil, CIF, now)
+
+		if err != nil {
+			logrus.Debug("Expire Points of "+CIF+": ", err)
+
+			continue
+		}
+
+		expired += counter
+	}
+
+	return expired, nil
+}
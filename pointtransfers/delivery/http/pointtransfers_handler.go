@@ -0,0 +1,329 @@
+package http
+
+import (
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointtransfers"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+var response models.Response
+
+// PointTransfersHandler represent the httphandler for point transfers
+type PointTransfersHandler struct {
+	PointTransferUseCase pointtransfers.UseCase
+}
+
+// NewPointTransfersHandler represent to register point transfers endpoint
+func NewPointTransfersHandler(echoGroup models.EchoGroup, trfUs pointtransfers.UseCase) {
+	handler := &PointTransfersHandler{
+		PointTransferUseCase: trfUs,
+	}
+
+	// End Point For CMS
+	echoGroup.Admin.GET("/point/transfers", handler.getTransfers)
+	echoGroup.Admin.GET("/point/transfers/:id", handler.getTransfer)
+	echoGroup.Admin.POST("/point/transfers/:id/reverse", handler.reverseTransfer)
+
+	// End Point For External
+	echoGroup.API.POST("/point/transfer", handler.transfer)
+	echoGroup.API.GET("/point/transfer/recipients", handler.getRecipients)
+	echoGroup.API.POST("/point/transfer/recipients", handler.addRecipient)
+	echoGroup.API.DELETE("/point/transfer/recipients/:recipientCif", handler.removeRecipient)
+}
+
+func (trf *PointTransfersHandler) transfer(echTx echo.Context) error {
+	var plTransfer models.PayloadPointTransfer
+	response = models.Response{}
+	err := echTx.Bind(&plTransfer)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plTransfer); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, plTransfer)
+	requestLogger.Info("Start to transfer point.")
+	data, err := trf.PointTransferUseCase.Transfer(echTx, &plTransfer)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageSaveSuccess
+	response.Data = data
+	requestLogger.Info("End of transfer point.")
+
+	return echTx.JSON(http.StatusCreated, response)
+}
+
+func (trf *PointTransfersHandler) getTransfers(echTx echo.Context) error {
+	response = models.Response{}
+	payload := map[string]interface{}{
+		"status": echTx.QueryParam("status"),
+		"cif":    echTx.QueryParam("cif"),
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get point transfers.")
+
+	for _, key := range []string{"page", "limit"} {
+		value, err := strconv.Atoi("0" + echTx.QueryParam(key))
+
+		if err != nil {
+			requestLogger.Debug(err)
+			response.Status = models.StatusError
+			response.Message = http.StatusText(http.StatusBadRequest)
+
+			return echTx.JSON(http.StatusBadRequest, response)
+		}
+
+		payload[key] = value
+	}
+
+	data, counter, err := trf.PointTransferUseCase.GetTransfers(echTx, payload)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.TotalCount = counter
+	requestLogger.Info("End of get point transfers.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (trf *PointTransfersHandler) getTransfer(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get detail point transfer.")
+	data, err := trf.PointTransferUseCase.GetTransfer(echTx, echTx.Param("id"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = data
+	requestLogger.Info("End of get detail point transfer.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (trf *PointTransfersHandler) reverseTransfer(echTx echo.Context) error {
+	var plReversal models.PayloadPointTransferReversal
+	response = models.Response{}
+	err := echTx.Bind(&plReversal)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plReversal); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, plReversal)
+	requestLogger.Info("Start to reverse a point transfer.")
+	data, err := trf.PointTransferUseCase.Reverse(echTx, echTx.Param("id"), &plReversal)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = data
+	requestLogger.Info("End of reverse a point transfer.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (trf *PointTransfersHandler) getRecipients(echTx echo.Context) error {
+	response = models.Response{}
+	CIF := echTx.QueryParam("CIF")
+	payload := map[string]interface{}{
+		"CIF": CIF,
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, payload)
+	requestLogger.Info("Start to get allowed transfer recipients.")
+
+	if CIF == "" {
+		response.Status = models.StatusError
+		response.Message = models.ErrBadParamInput.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	data, err := trf.PointTransferUseCase.GetRecipients(echTx, CIF)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	requestLogger.Info("End of get allowed transfer recipients.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (trf *PointTransfersHandler) addRecipient(echTx echo.Context) error {
+	var recipient models.PointTransferRecipient
+	response = models.Response{}
+	err := echTx.Bind(&recipient)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(recipient); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, recipient)
+	requestLogger.Info("Start to add an allowed transfer recipient.")
+
+	if err = trf.PointTransferUseCase.AddRecipient(echTx, &recipient); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageSaveSuccess
+	response.Data = recipient
+	requestLogger.Info("End of add an allowed transfer recipient.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (trf *PointTransfersHandler) removeRecipient(echTx echo.Context) error {
+	response = models.Response{}
+	CIF := echTx.QueryParam("CIF")
+	payload := map[string]interface{}{
+		"CIF":          CIF,
+		"recipientCif": echTx.Param("recipientCif"),
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, payload)
+	requestLogger.Info("Start to remove an allowed transfer recipient.")
+
+	if CIF == "" {
+		response.Status = models.StatusError
+		response.Message = models.ErrBadParamInput.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	err := trf.PointTransferUseCase.RemoveRecipient(echTx, CIF, echTx.Param("recipientCif"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDeleteSuccess
+	requestLogger.Info("End of remove an allowed transfer recipient.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	if strings.Contains(err.Error(), "400") {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case models.ErrNotFound, models.ErrNoTransfer:
+		return http.StatusNotFound
+	case models.ErrConflict, models.ErrTransferProcessed, models.ErrTransferReversed:
+		return http.StatusConflict
+	case models.ErrTransferReversalActor, models.ErrTransferRecipient:
+		return http.StatusForbidden
+	case models.ErrTransferAmount, models.ErrTransferDailyLimit, models.ErrTransferDeficit,
+		models.ErrTransferReversalDeficit:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
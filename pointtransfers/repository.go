@@ -0,0 +1,20 @@
+package pointtransfers
+
+import (
+	"gade/srv-gade-point/models"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the point transfers repository contract
+type Repository interface {
+	Create(echo.Context, *models.PointTransfer, *models.PointTransferConfig) error
+	GetTransfers(echo.Context, map[string]interface{}) ([]models.PointTransfer, error)
+	CountTransfers(echo.Context, map[string]interface{}) (string, error)
+	GetTransfer(echo.Context, int64) (*models.PointTransfer, error)
+	Reverse(echo.Context, *models.PointTransfer) error
+	GetRecipients(echo.Context, string) ([]models.PointTransferRecipient, error)
+	IsRecipientAllowed(echo.Context, string, string) (bool, error)
+	CreateRecipient(echo.Context, *models.PointTransferRecipient) error
+	DeleteRecipient(echo.Context, string, string) (int64, error)
+}
@@ -0,0 +1,425 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointhistories"
+	"gade/srv-gade-point/pointtransfers"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/lib/pq"
+)
+
+type psqlPointTransferRepository struct {
+	Conn         *sql.DB
+	pHistoryRepo pointhistories.Repository
+}
+
+// NewPsqlPointTransferRepository will create an object that represent the pointtransfers.Repository interface
+func NewPsqlPointTransferRepository(Conn *sql.DB, pHistoryRepo pointhistories.Repository) pointtransfers.Repository {
+	return &psqlPointTransferRepository{Conn, pHistoryRepo}
+}
+
+func (trfRepo *psqlPointTransferRepository) Create(c echo.Context, transfer *models.PointTransfer,
+	config *models.PointTransferConfig) error {
+	var transferred float64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	tx, err := trfRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	query := `INSERT INTO point_transfers (ref_id, cif, recipient_cif, point_amount, fee, note, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (ref_id) DO NOTHING RETURNING id`
+	err = tx.QueryRow(query, transfer.RefID, transfer.CIF, transfer.RecipientCIF, transfer.PointAmount, transfer.Fee,
+		transfer.Note, transfer.Status, &now).Scan(&transfer.ID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrTransferProcessed)
+		_ = tx.Rollback()
+
+		return models.ErrTransferProcessed
+	}
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	// both accounts are locked in the same order, so the transfers of the sender are counted one at a time
+	// and two customers sending to each other never wait on one another
+	if err = lockAccounts(tx, transfer.CIF, transfer.RecipientCIF); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	from, until := models.GetTransferDay(now, models.GetBusinessLocation())
+	query = `SELECT coalesce(sum(point_amount), 0) FROM point_transfers WHERE cif = $1 AND status = $2
+		AND created_at >= $3 AND created_at < $4 AND id <> $5`
+	err = tx.QueryRow(query, transfer.CIF, models.PointTransferSucceeded, from, until, transfer.ID).Scan(&transferred)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = config.CheckDailyLimit(transferred, *transfer.PointAmount); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	// the points are taken from the lots of the sender that are left while the account is locked
+	if transfer.Lots, err = trfRepo.pHistoryRepo.GetRemainingPointLots(tx, transfer.CIF); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	pointHistories, err := transfer.GetPointHistories(now)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = trfRepo.createPointHistories(tx, pointHistories, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	transfer.CreatedAt = &now
+
+	return nil
+}
+
+func (trfRepo *psqlPointTransferRepository) GetTransfers(c echo.Context, payload map[string]interface{}) ([]models.PointTransfer, error) {
+	var result []models.PointTransfer
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	where, args := getTransfersFilter(payload)
+	paging := ""
+
+	if payload["page"].(int) > 0 && payload["limit"].(int) > 0 {
+		args = append(args, payload["limit"].(int), (payload["page"].(int)-1)*payload["limit"].(int))
+		paging = fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	query := `SELECT ` + transferColumns + ` FROM point_transfers WHERE id IS NOT NULL` + where +
+		` ORDER BY created_at DESC, id DESC` + paging
+	rows, err := trfRepo.Conn.Query(query, args...)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		result = append(result, *transfer)
+	}
+
+	return result, nil
+}
+
+func (trfRepo *psqlPointTransferRepository) CountTransfers(c echo.Context, payload map[string]interface{}) (string, error) {
+	var counter string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	where, args := getTransfersFilter(payload)
+	query := `SELECT COUNT(id) FROM point_transfers WHERE id IS NOT NULL` + where
+	err := trfRepo.Conn.QueryRow(query, args...).Scan(&counter)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return "", err
+	}
+
+	return counter, nil
+}
+
+func (trfRepo *psqlPointTransferRepository) GetTransfer(c echo.Context, id int64) (*models.PointTransfer, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT ` + transferColumns + ` FROM point_transfers WHERE id = $1`
+	transfer, err := scanTransfer(trfRepo.Conn.QueryRow(query, id))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (trfRepo *psqlPointTransferRepository) Reverse(c echo.Context, transfer *models.PointTransfer) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	tx, err := trfRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	// only a succeeded transfer is updated, so two admins can never reverse the same transfer
+	query := `UPDATE point_transfers SET status = $1, reversed_by = $2, reversal_note = $3, reversed_at = $4,
+		updated_at = $4 WHERE id = $5 AND status = $6`
+	result, err := tx.Exec(query, models.PointTransferReversed, transfer.ReversedBy, transfer.ReversalNote, &now,
+		transfer.ID, models.PointTransferSucceeded)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if counter, err := result.RowsAffected(); err != nil || counter == 0 {
+		requestLogger.Debug(models.ErrTransferReversed)
+		_ = tx.Rollback()
+
+		return models.ErrTransferReversed
+	}
+
+	if err = lockAccounts(tx, transfer.CIF, transfer.RecipientCIF); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	pointHistories, err := trfRepo.pHistoryRepo.GetSucceededPointHistories(tx, transfer.RefID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if pointHistories, err = transfer.GetReversalHistories(pointHistories, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = trfRepo.createPointHistories(tx, pointHistories, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	transfer.Status = &models.PointTransferReversed
+	transfer.ReversedAt = &now
+	transfer.UpdatedAt = &now
+
+	return nil
+}
+
+func (trfRepo *psqlPointTransferRepository) GetRecipients(c echo.Context, CIF string) ([]models.PointTransferRecipient, error) {
+	var recipients []models.PointTransferRecipient
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT id, cif, recipient_cif, created_at FROM point_transfer_recipients WHERE cif = $1
+		ORDER BY created_at ASC, id ASC`
+	rows, err := trfRepo.Conn.Query(query, CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipient models.PointTransferRecipient
+		var createdAt pq.NullTime
+
+		if err = rows.Scan(&recipient.ID, &recipient.CIF, &recipient.RecipientCIF, &createdAt); err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		recipient.CreatedAt = &createdAt.Time
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+func (trfRepo *psqlPointTransferRepository) IsRecipientAllowed(c echo.Context, CIF, recipientCIF string) (bool, error) {
+	var counter int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT COUNT(id) FROM point_transfer_recipients WHERE cif = $1 AND recipient_cif = $2`
+	err := trfRepo.Conn.QueryRow(query, CIF, recipientCIF).Scan(&counter)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return false, err
+	}
+
+	return counter > 0, nil
+}
+
+func (trfRepo *psqlPointTransferRepository) CreateRecipient(c echo.Context, recipient *models.PointTransferRecipient) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `INSERT INTO point_transfer_recipients (cif, recipient_cif, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (cif, recipient_cif) DO UPDATE SET cif = EXCLUDED.cif RETURNING id, created_at`
+	err := trfRepo.Conn.QueryRow(query, recipient.CIF, recipient.RecipientCIF, &now).Scan(&recipient.ID, &now)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	recipient.CreatedAt = &now
+
+	return nil
+}
+
+func (trfRepo *psqlPointTransferRepository) DeleteRecipient(c echo.Context, CIF, recipientCIF string) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `DELETE FROM point_transfer_recipients WHERE cif = $1 AND recipient_cif = $2`
+	result, err := trfRepo.Conn.Exec(query, CIF, recipientCIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// lockAccounts to lock the point accounts of both customers that exist, ordered by their code
+func lockAccounts(tx *sql.Tx, CIFs ...string) error {
+	query := `SELECT id FROM point_accounts WHERE code = ANY($1) AND type = $2 ORDER BY code FOR UPDATE`
+	rows, err := tx.Query(query, pq.Array(CIFs), models.PointAccountTypeCustomer)
+
+	if err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+// createPointHistories to store the point histories of a transfer in their order within the transaction
+func (trfRepo *psqlPointTransferRepository) createPointHistories(tx *sql.Tx, pointHistories []*models.PointHistory,
+	now time.Time) error {
+	for _, pointHistory := range pointHistories {
+		if err := trfRepo.pHistoryRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getTransfersFilter(payload map[string]interface{}) (string, []interface{}) {
+	var args []interface{}
+	where := ""
+
+	// a transfer belongs to the sender and the recipient
+	if value, ok := payload["cif"]; ok && value != "" {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND (cif = $%d OR recipient_cif = $%d)", len(args), len(args))
+	}
+
+	if value, ok := payload["status"]; ok && value != "" {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	return where, args
+}
+
+// transferColumns to store the selected columns of a transfer in the order of scanTransfer
+const transferColumns = `id, ref_id, cif, recipient_cif, point_amount, fee, coalesce(note, ''), status,
+	coalesce(reversed_by, ''), coalesce(reversal_note, ''), reversed_at, updated_at, created_at`
+
+type scanner interface {
+	Scan(...interface{}) error
+}
+
+func scanTransfer(row scanner) (*models.PointTransfer, error) {
+	var transfer models.PointTransfer
+	var reversedAt, updatedAt, createdAt pq.NullTime
+
+	err := row.Scan(
+		&transfer.ID, &transfer.RefID, &transfer.CIF, &transfer.RecipientCIF, &transfer.PointAmount, &transfer.Fee,
+		&transfer.Note, &transfer.Status, &transfer.ReversedBy, &transfer.ReversalNote, &reversedAt, &updatedAt,
+		&createdAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reversedAt.Valid {
+		transfer.ReversedAt = &reversedAt.Time
+	}
+
+	if updatedAt.Valid {
+		transfer.UpdatedAt = &updatedAt.Time
+	}
+
+	transfer.CreatedAt = &createdAt.Time
+
+	return &transfer, nil
+}
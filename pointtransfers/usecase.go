@@ -0,0 +1,18 @@
+package pointtransfers
+
+import (
+	"gade/srv-gade-point/models"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the point transfers usecases
+type UseCase interface {
+	Transfer(echo.Context, *models.PayloadPointTransfer) (*models.PointTransfer, error)
+	GetTransfers(echo.Context, map[string]interface{}) ([]models.PointTransfer, string, error)
+	GetTransfer(echo.Context, string) (*models.PointTransfer, error)
+	Reverse(echo.Context, string, *models.PayloadPointTransferReversal) (*models.PointTransfer, error)
+	GetRecipients(echo.Context, string) ([]models.PointTransferRecipient, error)
+	AddRecipient(echo.Context, *models.PointTransferRecipient) error
+	RemoveRecipient(echo.Context, string, string) error
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointtransfers"
+	"gade/srv-gade-point/users"
+	"strconv"
+
+	"github.com/labstack/echo"
+)
+
+type pointTransferUseCase struct {
+	transferRepo pointtransfers.Repository
+	userRepo     users.Repository
+}
+
+// NewPointTransferUseCase will create new an pointTransferUseCase object representation of pointtransfers.UseCase interface
+func NewPointTransferUseCase(trfRepo pointtransfers.Repository, usrRepo users.Repository) pointtransfers.UseCase {
+	return &pointTransferUseCase{
+		transferRepo: trfRepo,
+		userRepo:     usrRepo,
+	}
+}
+
+func (trf *pointTransferUseCase) Transfer(c echo.Context, plTransfer *models.PayloadPointTransfer) (*models.PointTransfer, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	config := models.GetPointTransferConfig()
+	transfer := models.NewPointTransfer(plTransfer, config)
+
+	if err := config.CheckAmount(*transfer.PointAmount); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	if config.AllowList {
+		allowed, err := trf.transferRepo.IsRecipientAllowed(c, transfer.CIF, transfer.RecipientCIF)
+
+		if err != nil {
+			requestLogger.Debug(models.ErrTransferFailed)
+
+			return nil, models.ErrTransferFailed
+		}
+
+		if !allowed {
+			requestLogger.Debug(models.ErrTransferRecipient)
+
+			return nil, models.ErrTransferRecipient
+		}
+	}
+
+	err := trf.transferRepo.Create(c, transfer, config)
+
+	switch err {
+	case nil:
+		return transfer, nil
+	case models.ErrTransferProcessed, models.ErrTransferDailyLimit:
+		return nil, err
+	case models.ErrPointDeficit:
+		requestLogger.Debug(models.ErrTransferDeficit)
+
+		return nil, models.ErrTransferDeficit
+	}
+
+	requestLogger.Debug(models.ErrTransferFailed)
+
+	return nil, models.ErrTransferFailed
+}
+
+func (trf *pointTransferUseCase) GetTransfers(c echo.Context, payload map[string]interface{}) ([]models.PointTransfer, string, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	counter, err := trf.transferRepo.CountTransfers(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetTransfer)
+
+		return nil, "", models.ErrGetTransfer
+	}
+
+	data, err := trf.transferRepo.GetTransfers(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetTransfer)
+
+		return nil, "", models.ErrGetTransfer
+	}
+
+	return data, counter, nil
+}
+
+func (trf *pointTransferUseCase) GetTransfer(c echo.Context, id string) (*models.PointTransfer, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	transferID, err := strconv.ParseInt(id, 10, 64)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, errors.New("Something went wrong with input ID")
+	}
+
+	transfer, err := trf.transferRepo.GetTransfer(c, transferID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrNoTransfer)
+
+		return nil, models.ErrNoTransfer
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetTransfer)
+
+		return nil, models.ErrGetTransfer
+	}
+
+	return transfer, nil
+}
+
+func (trf *pointTransferUseCase) Reverse(c echo.Context, id string, plReversal *models.PayloadPointTransferReversal) (*models.PointTransfer, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	transfer, err := trf.GetTransfer(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if *transfer.Status != models.PointTransferSucceeded {
+		requestLogger.Debug(models.ErrTransferReversed)
+
+		return nil, models.ErrTransferReversed
+	}
+
+	reverser, err := trf.checkAdmin(c)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	transfer.ReversedBy = reverser
+	transfer.ReversalNote = plReversal.Note
+	err = trf.transferRepo.Reverse(c, transfer)
+
+	switch err {
+	case nil:
+		return transfer, nil
+	case models.ErrTransferReversed:
+		return nil, err
+	case models.ErrPointDeficit:
+		requestLogger.Debug(models.ErrTransferReversalDeficit)
+
+		return nil, models.ErrTransferReversalDeficit
+	}
+
+	requestLogger.Debug(models.ErrReverseTransfer)
+
+	return nil, models.ErrReverseTransfer
+}
+
+func (trf *pointTransferUseCase) GetRecipients(c echo.Context, CIF string) ([]models.PointTransferRecipient, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	recipients, err := trf.transferRepo.GetRecipients(c, CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrTransferRecipientFailed)
+
+		return nil, models.ErrTransferRecipientFailed
+	}
+
+	return recipients, nil
+}
+
+func (trf *pointTransferUseCase) AddRecipient(c echo.Context, recipient *models.PointTransferRecipient) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if err := trf.transferRepo.CreateRecipient(c, recipient); err != nil {
+		requestLogger.Debug(models.ErrTransferRecipientFailed)
+
+		return models.ErrTransferRecipientFailed
+	}
+
+	return nil
+}
+
+func (trf *pointTransferUseCase) RemoveRecipient(c echo.Context, CIF, recipientCIF string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	counter, err := trf.transferRepo.DeleteRecipient(c, CIF, recipientCIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrTransferRecipientFailed)
+
+		return models.ErrTransferRecipientFailed
+	}
+
+	if counter == 0 {
+		requestLogger.Debug(models.ErrNotFound)
+
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// checkAdmin to get the username of the logged in admin user that reverses a transfer,
+// the token of a service account is shared, so it is never a reverser
+func (trf *pointTransferUseCase) checkAdmin(c echo.Context) (string, error) {
+	claims := models.GetTokenUser(c)
+
+	if claims == nil {
+		return "", models.ErrTransferReversalActor
+	}
+
+	user := &models.User{Username: claims.Name}
+
+	if err := trf.userRepo.GetByUsername(c.Request().Context(), user); err != nil {
+		return "", models.ErrTransferReversalActor
+	}
+
+	if user.ID != claims.UserID || user.Role == nil || *user.Role != models.UserRoleAdmin {
+		return "", models.ErrTransferReversalActor
+	}
+
+	return user.Username, nil
+}
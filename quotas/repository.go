@@ -1,7 +1,9 @@
 package quotas
 
 import (
+	"database/sql"
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
@@ -10,5 +12,7 @@ import (
 type Repository interface {
 	Create(echo.Context, *models.Quota, int64) error
 	DeleteByReward(echo.Context, int64) error
-	CheckQuota(echo.Context, int64) (int64, error)
+	GetByReward(echo.Context, int64) ([]models.Quota, error)
+	UseQuota(echo.Context, int64, string, string, time.Time) (*models.Quota, error)
+	RefundQuotaUsages(*sql.Tx, string, time.Time) error
 }
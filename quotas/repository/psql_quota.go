@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/lib/pq"
 )
 
 type psqlQuotaRepository struct {
@@ -22,8 +23,8 @@ func (quotRepo *psqlQuotaRepository) Create(c echo.Context, quota *models.Quota,
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	now := time.Now()
-	query := `INSERT INTO quotas (number_of_days, amount, is_per_user, reward_id, created_at)
-		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `INSERT INTO quotas (number_of_days, amount, is_per_user, period_type, available, reward_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 	stmt, err := quotRepo.Conn.Prepare(query)
 
 	if err != nil {
@@ -40,7 +41,13 @@ func (quotRepo *psqlQuotaRepository) Create(c echo.Context, quota *models.Quota,
 		return err
 	}
 
-	err = stmt.QueryRow(quota.NumberOfDays, quota.Amount, quota.IsPerUser, rewardID, &now).Scan(&lastID)
+	if quota.PeriodType == nil {
+		quota.PeriodType = &models.QuotaPeriodRolling
+	}
+
+	err = stmt.QueryRow(
+		quota.NumberOfDays, quota.Amount, quota.IsPerUser, quota.PeriodType, quota.Amount, rewardID, &now,
+	).Scan(&lastID)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -78,27 +85,160 @@ func (quotRepo *psqlQuotaRepository) DeleteByReward(c echo.Context, rewardID int
 	return nil
 }
 
-func (quotRepo *psqlQuotaRepository) CheckQuota(c echo.Context, rewardID int64) (int64, error) {
+func (quotRepo *psqlQuotaRepository) GetByReward(c echo.Context, rewardID int64) ([]models.Quota, error) {
+	var quotas []models.Quota
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	var available int64
+	query := `SELECT id, number_of_days, amount, is_per_user, period_type, available, created_at, updated_at
+		FROM quotas WHERE reward_id = $1 ORDER BY id ASC`
+	rows, err := quotRepo.Conn.Query(query, rewardID)
 
-	query := `SELECT available FROM metrics where job = $1`
-	stmt, err := quotRepo.Conn.Prepare(query)
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return quotas, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var quota models.Quota
+		var createDate, updateDate pq.NullTime
+
+		err = rows.Scan(&quota.ID, &quota.NumberOfDays, &quota.Amount, &quota.IsPerUser, &quota.PeriodType,
+			&quota.Available, &createDate, &updateDate)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		quota.CreatedAt = &createDate.Time
+		quota.UpdatedAt = &updateDate.Time
+		quotas = append(quotas, quota)
+	}
+
+	return quotas, nil
+}
+
+func (quotRepo *psqlQuotaRepository) UseQuota(c echo.Context, rewardID int64, cif string, refID string,
+	usedDate time.Time) (*models.Quota, error) {
+	var rewardQuotas []models.Quota
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	tx, err := quotRepo.Conn.Begin()
 
 	if err != nil {
 		requestLogger.Debug(err)
 
-		return 0, err
+		return nil, err
 	}
 
-	err = stmt.QueryRow(&rewardID).Scan(&available)
+	// lock every quota of the reward, so the concurrent inquiries will be counted one by one
+	query := `SELECT id, number_of_days, amount, is_per_user, period_type, created_at
+		FROM quotas WHERE reward_id = $1 ORDER BY id ASC FOR UPDATE`
+	rows, err := tx.Query(query, rewardID)
 
 	if err != nil {
 		requestLogger.Debug(err)
+		_ = tx.Rollback()
 
-		return 0, err
+		return nil, err
 	}
 
-	return available, nil
+	for rows.Next() {
+		var quota models.Quota
+		var createdAt pq.NullTime
+
+		err = rows.Scan(&quota.ID, &quota.NumberOfDays, &quota.Amount, &quota.IsPerUser, &quota.PeriodType, &createdAt)
+
+		if err != nil {
+			requestLogger.Debug(err)
+			rows.Close()
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		if createdAt.Valid {
+			quota.CreatedAt = &createdAt.Time
+		}
+
+		rewardQuotas = append(rewardQuotas, quota)
+	}
+
+	rows.Close()
+
+	for _, quota := range rewardQuotas {
+		var used int64
+		start, end := quota.GetWindow(usedDate)
+		cifFilter := ""
+
+		if quota.IsUserQuota() {
+			cifFilter = cif
+		}
+
+		query = `SELECT COUNT(id) FROM quota_usages WHERE quota_id = $1 AND used_date >= $2
+			AND ($3::timestamp IS NULL OR used_date < $3) AND ($4 = '' OR cif = $4)`
+		err = tx.QueryRow(query, quota.ID, start, end, cifFilter).Scan(&used)
+
+		if err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		available := *quota.Amount - used
+
+		if available <= 0 {
+			_ = tx.Rollback()
+			quota.Available = &available
+
+			return &quota, nil
+		}
+
+		query = `INSERT INTO quota_usages (quota_id, cif, ref_id, used_date, created_at) VALUES ($1, $2, $3, $4, $5)`
+
+		if _, err = tx.Exec(query, quota.ID, cif, refID, usedDate, &now); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		if quota.IsUserQuota() {
+			continue
+		}
+
+		query = `UPDATE quotas SET available = $1, updated_at = $2 WHERE id = $3`
+
+		if _, err = tx.Exec(query, available-1, &now, quota.ID); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// RefundQuotaUsages to remove the quota usages of a reward transaction and give back the global quotas it took
+func (quotRepo *psqlQuotaRepository) RefundQuotaUsages(tx *sql.Tx, refID string, now time.Time) error {
+	query := `WITH refunded AS (DELETE FROM quota_usages WHERE ref_id = $1 RETURNING quota_id)
+		UPDATE quotas q SET available = LEAST(q.available + r.total, q.amount), updated_at = $2
+		FROM (SELECT quota_id, COUNT(quota_id) total FROM refunded GROUP BY quota_id) r
+		WHERE q.id = r.quota_id AND q.is_per_user = $3`
+	_, err := tx.Exec(query, refID, &now, &models.IsPerUserFalse)
+
+	return err
 }
@@ -10,5 +10,6 @@ import (
 type UseCase interface {
 	Create(echo.Context, *models.Quota, int64) error
 	DeleteByReward(echo.Context, int64) error
-	CheckQuota(echo.Context, int64) (bool, error)
+	GetByReward(echo.Context, int64) ([]models.Quota, error)
+	UseQuota(echo.Context, *models.Reward, *models.PayloadValidator, string) error
 }
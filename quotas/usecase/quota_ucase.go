@@ -3,6 +3,7 @@ package usecase
 import (
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/quotas"
+	"time"
 
 	"github.com/labstack/echo"
 )
@@ -46,21 +47,39 @@ func (quot *quotaUseCase) DeleteByReward(c echo.Context, rewardID int64) error {
 	return nil
 }
 
-func (quot *quotaUseCase) CheckQuota(c echo.Context, rewardID int64) (bool, error) {
+func (quot *quotaUseCase) GetByReward(c echo.Context, rewardID int64) ([]models.Quota, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	available, err := quot.quotaRepo.CheckQuota(c, rewardID)
+	quotas, err := quot.quotaRepo.GetByReward(c, rewardID)
 
 	if err != nil {
-		requestLogger.Debug(models.ErrDelQuotaFailed)
+		requestLogger.Debug(models.ErrGetQuota)
+
+		return nil, models.ErrGetQuota
+	}
+
+	return quotas, nil
+}
+
+func (quot *quotaUseCase) UseQuota(c echo.Context, reward *models.Reward, plValidator *models.PayloadValidator, refID string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	// the quota windows follow the server clock, so a client can not pick a date to reach a fresh window
+	exhausted, err := quot.quotaRepo.UseQuota(c, reward.ID, plValidator.CIF, refID, time.Now())
+
+	if err != nil {
+		requestLogger.Debug(models.ErrUseQuotaFailed)
+
+		return models.ErrUseQuotaFailed
+	}
 
-		return false, models.ErrDelQuotaFailed
+	if exhausted == nil {
+		return nil
 	}
 
-	valid := true
-	if available <= 0 {
-		valid = false
+	if exhausted.IsUserQuota() {
+		return models.ErrUserQuotaExhausted
 	}
 
-	return valid, nil
+	return models.ErrQuotaExhausted
 }
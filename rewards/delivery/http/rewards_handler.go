@@ -3,6 +3,7 @@ package http
 import (
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/rewards"
+	"gade/srv-gade-point/rewardtrxs"
 	"net/http"
 	"strings"
 
@@ -13,19 +14,175 @@ var response models.Response
 
 // RewardHandler represent the httphandler for reward
 type RewardHandler struct {
-	RewardUseCase rewards.UseCase
+	RewardUseCase    rewards.UseCase
+	RewardTrxUseCase rewardtrxs.UseCase
 }
 
 // NewRewardHandler represent to register reward endpoint
-func NewRewardHandler(echoGroup models.EchoGroup, us rewards.UseCase) {
+func NewRewardHandler(echoGroup models.EchoGroup, us rewards.UseCase, rwdTrxUs rewardtrxs.UseCase) {
 	handler := &RewardHandler{
-		RewardUseCase: us,
+		RewardUseCase:    us,
+		RewardTrxUseCase: rwdTrxUs,
 	}
 
-	// Dummy End Point
+	// End Point For CMS
+	echoGroup.Admin.GET("/rewards", handler.getRewards)
+	echoGroup.Admin.GET("/rewards/:id", handler.getRewardDetail)
+	echoGroup.Admin.PUT("/rewards/:id", handler.updateReward)
+	echoGroup.Admin.PUT("/rewards/status/:id", handler.updateRewardStatus)
+	echoGroup.Admin.DELETE("/rewards/:id", handler.deleteReward)
+	echoGroup.Admin.POST("/rewards/simulation", handler.rewardSimulation)
+
+	// End Point For External
 	echoGroup.API.POST("/rewards/inquiry", handler.rewardInquiry)
 	echoGroup.API.POST("/rewards/succeeded", handler.rewardSucceeded)
 	echoGroup.API.POST("/rewards/rejected", handler.rewardRejected)
+	echoGroup.API.POST("/rewards/reversed", handler.rewardReversed)
+}
+
+func (rwd *RewardHandler) getRewards(echTx echo.Context) error {
+	response = models.Response{}
+	payload := map[string]interface{}{
+		"campaignId": echTx.QueryParam("campaignId"),
+		"type":       echTx.QueryParam("type"),
+		"status":     echTx.QueryParam("status"),
+		"promoCode":  echTx.QueryParam("promoCode"),
+		"tag":        echTx.QueryParam("tag"),
+		"page":       echTx.QueryParam("page"),
+		"limit":      echTx.QueryParam("limit"),
+	}
+
+	logger := models.RequestLogger{
+		Payload: payload,
+	}
+
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get rewards.")
+	countReward, data, err := rwd.RewardUseCase.GetRewards(echTx, payload)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.TotalCount = countReward
+	requestLogger.Info("End of get rewards.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (rwd *RewardHandler) getRewardDetail(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get detail reward.")
+	responseData, err := rwd.RewardUseCase.GetRewardDetail(echTx, echTx.Param("id"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = responseData
+	requestLogger.Info("End of get detail reward.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (rwd *RewardHandler) updateReward(echTx echo.Context) error {
+	var reward models.Reward
+	response = models.Response{}
+	err := echTx.Bind(&reward)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, reward)
+	requestLogger.Info("Start to update a reward.")
+	err = rwd.RewardUseCase.UpdateReward(echTx, echTx.Param("id"), &reward)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = reward
+	requestLogger.Info("End of update a reward.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (rwd *RewardHandler) updateRewardStatus(echTx echo.Context) error {
+	var reward models.Reward
+	response = models.Response{}
+	err := echTx.Bind(&reward)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, reward)
+	requestLogger.Info("Start to update a reward status.")
+	err = rwd.RewardUseCase.UpdateRewardStatus(echTx, echTx.Param("id"), &reward)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	requestLogger.Info("End of update a reward status.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (rwd *RewardHandler) deleteReward(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to delete a reward.")
+	err := rwd.RewardUseCase.DeleteReward(echTx, echTx.Param("id"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDeleteSuccess
+	requestLogger.Info("End of delete a reward.")
+
+	return echTx.JSON(http.StatusOK, response)
 }
 
 func (rwd *RewardHandler) rewardInquiry(echTx echo.Context) error {
@@ -43,6 +200,7 @@ func (rwd *RewardHandler) rewardInquiry(echTx echo.Context) error {
 	if err = echTx.Validate(plValidator); err != nil {
 		response.Status = models.StatusError
 		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
 
 		return echTx.JSON(http.StatusBadRequest, response)
 	}
@@ -55,6 +213,7 @@ func (rwd *RewardHandler) rewardInquiry(echTx echo.Context) error {
 	if err != nil {
 		response.Status = models.StatusError
 		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
 
 		return echTx.JSON(getStatusCode(err), response)
 	}
@@ -73,10 +232,55 @@ func (rwd *RewardHandler) rewardInquiry(echTx echo.Context) error {
 	return echTx.JSON(getStatusCode(err), response)
 }
 
+func (rwd *RewardHandler) rewardSimulation(echTx echo.Context) error {
+	var plSimulation models.PayloadRewardSimulation
+	response = models.Response{}
+	err := echTx.Bind(&plSimulation)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plSimulation); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, plSimulation)
+	requestLogger.Info("Start to simulate rewards.")
+	responseData, err := rwd.RewardUseCase.Simulation(echTx, &plSimulation)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessagePointSuccess
+
+	if len(responseData) > 0 {
+		response.Data = responseData
+	} else {
+		response.Message = models.MessageNoRewards
+	}
+
+	requestLogger.Info("End of simulate rewards.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
 func (rwd *RewardHandler) rewardSucceeded(echTx echo.Context) error {
-	var reward models.Reward
+	var rwdPayment models.RewardPayment
 	response = models.Response{}
-	err := echTx.Bind(&reward)
+	err := echTx.Bind(&rwdPayment)
 
 	if err != nil {
 		response.Status = models.StatusError
@@ -85,16 +289,36 @@ func (rwd *RewardHandler) rewardSucceeded(echTx echo.Context) error {
 		return echTx.JSON(http.StatusUnprocessableEntity, response)
 	}
 
+	if err = echTx.Validate(rwdPayment); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, rwdPayment)
+	requestLogger.Info("Start to update succeeded reward transaction.")
+	err = rwd.RewardTrxUseCase.UpdateSuccess(echTx, getPaymentPayload(rwdPayment))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
 	response.Status = models.StatusSuccess
-	response.Message = models.MessageDataSuccess
+	response.Message = models.MessageUpdateSuccess
+	requestLogger.Info("End of update succeeded reward transaction.")
 
 	return echTx.JSON(http.StatusOK, response)
 }
 
 func (rwd *RewardHandler) rewardRejected(echTx echo.Context) error {
-	var reward models.Reward
+	var rwdPayment models.RewardPayment
 	response = models.Response{}
-	err := echTx.Bind(&reward)
+	err := echTx.Bind(&rwdPayment)
 
 	if err != nil {
 		response.Status = models.StatusError
@@ -103,12 +327,80 @@ func (rwd *RewardHandler) rewardRejected(echTx echo.Context) error {
 		return echTx.JSON(http.StatusUnprocessableEntity, response)
 	}
 
+	if err = echTx.Validate(rwdPayment); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, rwdPayment)
+	requestLogger.Info("Start to update rejected reward transaction.")
+	err = rwd.RewardTrxUseCase.UpdateReject(echTx, getPaymentPayload(rwdPayment))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
 	response.Status = models.StatusSuccess
-	response.Message = models.MessageDataSuccess
+	response.Message = models.MessageUpdateSuccess
+	requestLogger.Info("End of update rejected reward transaction.")
 
 	return echTx.JSON(http.StatusOK, response)
 }
 
+func (rwd *RewardHandler) rewardReversed(echTx echo.Context) error {
+	var plReversal models.PayloadRewardReversal
+	response = models.Response{}
+	err := echTx.Bind(&plReversal)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plReversal); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, plReversal)
+	requestLogger.Info("Start to reverse reward transaction.")
+	data, err := rwd.RewardTrxUseCase.Reverse(echTx, &plReversal)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = data
+	requestLogger.Info("End of reverse reward transaction.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func getPaymentPayload(rwdPayment models.RewardPayment) map[string]interface{} {
+	return map[string]interface{}{
+		"cif":     rwdPayment.CIF,
+		"refTrx":  rwdPayment.RefTrx,
+		"refCore": rwdPayment.RefCore,
+	}
+}
+
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
@@ -119,12 +411,15 @@ func getStatusCode(err error) int {
 	}
 
 	switch err {
-	case models.ErrInternalServerError:
+	case models.ErrInternalServerError, models.ErrReversalFailed:
 		return http.StatusInternalServerError
-	case models.ErrNotFound:
+	case models.ErrNotFound, models.ErrRefTrxNotFound, models.ErrNoReward, models.ErrRefCoreNotFound:
 		return http.StatusNotFound
-	case models.ErrConflict:
+	case models.ErrConflict, models.ErrRewardTrxProcessed, models.ErrInquiryConflict, models.ErrInquiryInProgress,
+		models.ErrRewardInUse, models.ErrReversalProcessed:
 		return http.StatusConflict
+	case models.ErrReversalExceeded, models.ErrReversalAmount:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusOK
 	}
@@ -14,4 +14,11 @@ type Repository interface {
 	DeleteRewardTag(echo.Context, int64) error
 	GetRewardByCampaign(echo.Context, int64) ([]models.Reward, error)
 	GetRewardTags(echo.Context, *models.Reward) (*models.Reward, error)
+	GetRewards(echo.Context, map[string]interface{}) ([]models.Reward, error)
+	CountRewards(echo.Context, map[string]interface{}) (int, error)
+	GetRewardByID(echo.Context, int64) (*models.Reward, error)
+	UpdateReward(echo.Context, *models.Reward) error
+	UpdateRewardStatus(echo.Context, int64, int8) error
+	Delete(echo.Context, int64) error
+	CountRewardTrx(echo.Context, int64) (int64, error)
 }
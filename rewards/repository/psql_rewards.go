@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/rewards"
 	"time"
@@ -27,8 +28,8 @@ func (rwdRepo *psqlRewardRepository) CreateReward(c echo.Context, reward *models
 	now := time.Now()
 	query := `INSERT INTO rewards (name, description, terms_and_conditions, how_to_use,
 		journal_account, promo_code, is_promo_code, custom_period, type, validators,
-		campaign_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
-		$12) RETURNING id`
+		campaign_id, priority, point_expiry, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		$11, $12, $13, $14) RETURNING id`
 	stmt, err := rwdRepo.Conn.Prepare(query)
 
 	if err != nil {
@@ -45,10 +46,26 @@ func (rwdRepo *psqlRewardRepository) CreateReward(c echo.Context, reward *models
 		return err
 	}
 
+	customPeriod, err := getCustomPeriod(reward)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	pointExpiry, err := getPointExpiry(reward)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
 	err = stmt.QueryRow(
 		reward.Name, reward.Description, reward.TermsAndConditions, reward.HowToUse,
-		reward.JournalAccount, reward.PromoCode, reward.IsPromoCode, reward.CustomPeriod,
-		reward.Type, string(validator), campaignID, &now).Scan(&lastID)
+		reward.JournalAccount, reward.PromoCode, reward.IsPromoCode, customPeriod,
+		reward.Type, string(validator), campaignID, reward.GetPriority(), pointExpiry, &now).Scan(&lastID)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -143,13 +160,223 @@ func (rwdRepo *psqlRewardRepository) DeleteRewardTag(c echo.Context, rewardID in
 }
 
 func (rwdRepo *psqlRewardRepository) GetRewardByCampaign(c echo.Context, campaignID int64) ([]models.Reward, error) {
+	query := `SELECT id, name, description, terms_and_conditions, how_to_use, journal_account, promo_code, is_promo_code,
+		custom_period, type, validators, campaign_id, priority, status, point_expiry, created_at, updated_at FROM rewards
+		WHERE campaign_id = $1 ORDER BY priority ASC, id ASC`
+
+	return rwdRepo.getRewards(c, query, campaignID)
+}
+
+func (rwdRepo *psqlRewardRepository) GetRewards(c echo.Context, payload map[string]interface{}) ([]models.Reward, error) {
+	where, args := rwdRepo.getRewardsFilter(payload)
+	paging := ""
+
+	if payload["page"].(int) > 0 && payload["limit"].(int) > 0 {
+		args = append(args, payload["limit"].(int), (payload["page"].(int)-1)*payload["limit"].(int))
+		paging = fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	query := `SELECT r.id, r.name, r.description, r.terms_and_conditions, r.how_to_use, r.journal_account, r.promo_code,
+		r.is_promo_code, r.custom_period, r.type, r.validators, r.campaign_id, r.priority, r.status, r.point_expiry,
+		r.created_at, r.updated_at FROM rewards r WHERE r.id IS NOT NULL` + where + ` ORDER BY r.created_at DESC, r.id DESC` + paging
+
+	return rwdRepo.getRewards(c, query, args...)
+}
+
+func (rwdRepo *psqlRewardRepository) CountRewards(c echo.Context, payload map[string]interface{}) (int, error) {
+	var total int
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	where, args := rwdRepo.getRewardsFilter(payload)
+	query := `SELECT coalesce(COUNT(r.id), 0) FROM rewards r WHERE r.id IS NOT NULL` + where
+	err := rwdRepo.Conn.QueryRow(query, args...).Scan(&total)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (rwdRepo *psqlRewardRepository) GetRewardByID(c echo.Context, id int64) (*models.Reward, error) {
+	query := `SELECT id, name, description, terms_and_conditions, how_to_use, journal_account, promo_code, is_promo_code,
+		custom_period, type, validators, campaign_id, priority, status, point_expiry, created_at, updated_at FROM rewards
+		WHERE id = $1`
+	rewards, err := rwdRepo.getRewards(c, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rewards) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &rewards[0], nil
+}
+
+func (rwdRepo *psqlRewardRepository) UpdateReward(c echo.Context, reward *models.Reward) error {
+	var lastID int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE rewards SET name = $1, description = $2, terms_and_conditions = $3, how_to_use = $4,
+		journal_account = $5, promo_code = $6, is_promo_code = $7, validators = $8, priority = $9,
+		custom_period = $10, point_expiry = $11, updated_at = $12 WHERE id = $13 RETURNING id`
+	stmt, err := rwdRepo.Conn.Prepare(query)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	validator, err := json.Marshal(reward.Validators)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	customPeriod, err := getCustomPeriod(reward)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	pointExpiry, err := getPointExpiry(reward)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	err = stmt.QueryRow(
+		reward.Name, reward.Description, reward.TermsAndConditions, reward.HowToUse, reward.JournalAccount,
+		reward.PromoCode, reward.IsPromoCode, string(validator), reward.GetPriority(), customPeriod, pointExpiry,
+		&now, reward.ID).Scan(&lastID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	reward.UpdatedAt = &now
+
+	return nil
+}
+
+func (rwdRepo *psqlRewardRepository) UpdateRewardStatus(c echo.Context, id int64, status int8) error {
+	var lastID int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE rewards SET status = $1, updated_at = $2 WHERE id = $3 RETURNING id`
+	stmt, err := rwdRepo.Conn.Prepare(query)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	err = stmt.QueryRow(status, &now, id).Scan(&lastID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (rwdRepo *psqlRewardRepository) Delete(c echo.Context, id int64) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `DELETE FROM rewards WHERE id = $1`
+	stmt, err := rwdRepo.Conn.Prepare(query)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	result, err := stmt.Exec(id)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	requestLogger.Debug("reward deleted: ", result)
+
+	return nil
+}
+
+func (rwdRepo *psqlRewardRepository) CountRewardTrx(c echo.Context, id int64) (int64, error) {
+	var total int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT coalesce(COUNT(id), 0) FROM reward_transactions WHERE reward_id = $1`
+	err := rwdRepo.Conn.QueryRow(query, id).Scan(&total)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (rwdRepo *psqlRewardRepository) getRewardsFilter(payload map[string]interface{}) (string, []interface{}) {
+	var args []interface{}
+	where := ""
+
+	if payload["campaignId"].(string) != "" {
+		args = append(args, payload["campaignId"].(string))
+		where += fmt.Sprintf(" AND r.campaign_id = $%d", len(args))
+	}
+
+	if payload["type"].(string) != "" {
+		args = append(args, payload["type"].(string))
+		where += fmt.Sprintf(" AND r.type = $%d", len(args))
+	}
+
+	if payload["status"].(string) != "" {
+		args = append(args, payload["status"].(string))
+		where += fmt.Sprintf(" AND r.status = $%d", len(args))
+	}
+
+	if payload["promoCode"].(string) != "" {
+		args = append(args, payload["promoCode"].(string))
+		where += fmt.Sprintf(" AND r.promo_code = $%d", len(args))
+	}
+
+	if payload["tag"].(string) != "" {
+		args = append(args, payload["tag"].(string))
+		where += fmt.Sprintf(` AND EXISTS (SELECT 1 FROM reward_tags rt JOIN tags t ON rt.tag_id = t.id
+			WHERE rt.reward_id = r.id AND t.name = $%d)`, len(args))
+	}
+
+	return where, args
+}
+
+func (rwdRepo *psqlRewardRepository) getRewards(c echo.Context, query string, args ...interface{}) ([]models.Reward, error) {
 	var rewards []models.Reward
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	query := `SELECT id, name, description, terms_and_conditions, how_to_use, journal_account, promo_code, is_promo_code, custom_period, type, validators,
-		campaign_id, created_at, updated_at FROM rewards WHERE campaign_id = $1`
-	rows, err := rwdRepo.Conn.Query(query, campaignID)
-	defer rows.Close()
+	rows, err := rwdRepo.Conn.Query(query, args...)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -157,14 +384,17 @@ func (rwdRepo *psqlRewardRepository) GetRewardByCampaign(c echo.Context, campaig
 		return rewards, err
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
 		var reward models.Reward
 		var createDate, updateDate pq.NullTime
-		var validator json.RawMessage
+		var validator, customPeriod, pointExpiry json.RawMessage
 
 		err = rows.Scan(
 			&reward.ID, &reward.Name, &reward.Description, &reward.TermsAndConditions, &reward.HowToUse, &reward.JournalAccount, &reward.PromoCode,
-			&reward.IsPromoCode, &reward.CustomPeriod, &reward.Type, &validator, &reward.CampaignID, &createDate, &updateDate,
+			&reward.IsPromoCode, &customPeriod, &reward.Type, &validator, &reward.CampaignID, &reward.Priority, &reward.Status,
+			&pointExpiry, &createDate, &updateDate,
 		)
 
 		if err != nil {
@@ -182,6 +412,22 @@ func (rwdRepo *psqlRewardRepository) GetRewardByCampaign(c echo.Context, campaig
 			return nil, err
 		}
 
+		if len(customPeriod) > 0 {
+			if err = json.Unmarshal([]byte(customPeriod), &reward.CustomPeriod); err != nil {
+				requestLogger.Debug(err)
+
+				return nil, err
+			}
+		}
+
+		if len(pointExpiry) > 0 {
+			if err = json.Unmarshal([]byte(pointExpiry), &reward.PointExpiry); err != nil {
+				requestLogger.Debug(err)
+
+				return nil, err
+			}
+		}
+
 		rewards = append(rewards, reward)
 	}
 
@@ -220,3 +466,33 @@ func (rwdRepo *psqlRewardRepository) GetRewardTags(c echo.Context, reward *model
 
 	return reward, nil
 }
+
+// getCustomPeriod to get the custom period as json, a reward without custom period is stored as null
+func getCustomPeriod(reward *models.Reward) (interface{}, error) {
+	if reward.CustomPeriod == nil {
+		return nil, nil
+	}
+
+	customPeriod, err := json.Marshal(reward.CustomPeriod)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(customPeriod), nil
+}
+
+// getPointExpiry to get the point expiry as json, a reward without point expiry follows the global rule
+func getPointExpiry(reward *models.Reward) (interface{}, error) {
+	if reward.PointExpiry == nil {
+		return nil, nil
+	}
+
+	pointExpiry, err := json.Marshal(reward.PointExpiry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(pointExpiry), nil
+}
@@ -11,4 +11,10 @@ type UseCase interface {
 	CreateReward(echo.Context, *models.Reward, int64) error
 	DeleteByCampaign(echo.Context, int64) error
 	Inquiry(echo.Context, *models.PayloadValidator) (models.RewardsInquiry, error)
+	Simulation(echo.Context, *models.PayloadRewardSimulation) ([]models.RewardSimulation, error)
+	GetRewards(echo.Context, map[string]interface{}) (string, []models.Reward, error)
+	GetRewardDetail(echo.Context, string) (*models.Reward, error)
+	UpdateReward(echo.Context, string, *models.Reward) error
+	UpdateRewardStatus(echo.Context, string, *models.Reward) error
+	DeleteReward(echo.Context, string) error
 }
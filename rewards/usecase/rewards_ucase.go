@@ -1,10 +1,16 @@
 package usecase
 
 import (
+	"database/sql"
+	"errors"
 	"gade/srv-gade-point/campaigns"
+	"gade/srv-gade-point/goldprices"
+	"gade/srv-gade-point/membershiptiers"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/quotas"
 	"gade/srv-gade-point/rewards"
+	"gade/srv-gade-point/rewardtrxs"
+	"gade/srv-gade-point/segments"
 	"gade/srv-gade-point/tags"
 	"gade/srv-gade-point/vouchers"
 	"strconv"
@@ -19,6 +25,10 @@ type rewardUseCase struct {
 	tagUC        tags.UseCase
 	quotaUC      quotas.UseCase
 	voucherUC    vouchers.UseCase
+	rewardTrxUC  rewardtrxs.UseCase
+	goldPriceUC  goldprices.UseCase
+	segmentUC    segments.UseCase
+	tierUC       membershiptiers.UseCase
 }
 
 // NewRewardUseCase will create new an rewardUseCase object representation of rewards.UseCase interface
@@ -28,6 +38,10 @@ func NewRewardUseCase(
 	tagUC tags.UseCase,
 	quotaUC quotas.UseCase,
 	voucherUC vouchers.UseCase,
+	rewardTrxUC rewardtrxs.UseCase,
+	goldPriceUC goldprices.UseCase,
+	segmentUC segments.UseCase,
+	tierUC membershiptiers.UseCase,
 ) rewards.UseCase {
 	return &rewardUseCase{
 		rewardRepo:   rwdRepo,
@@ -35,6 +49,10 @@ func NewRewardUseCase(
 		tagUC:        tagUC,
 		quotaUC:      quotaUC,
 		voucherUC:    voucherUC,
+		rewardTrxUC:  rewardTrxUC,
+		goldPriceUC:  goldPriceUC,
+		segmentUC:    segmentUC,
+		tierUC:       tierUC,
 	}
 }
 
@@ -104,9 +122,242 @@ func (rwd *rewardUseCase) DeleteByCampaign(c echo.Context, campaignID int64) err
 	return nil
 }
 
+func (rwd *rewardUseCase) GetRewards(c echo.Context, payload map[string]interface{}) (string, []models.Reward, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	for _, key := range []string{"page", "limit"} {
+		value := 0
+
+		if payload[key].(string) != "" {
+			number, err := strconv.Atoi(payload[key].(string))
+
+			if err != nil {
+				requestLogger.Debug(err)
+
+				return "", nil, errors.New("Something went wrong with input " + key)
+			}
+
+			value = number
+		}
+
+		payload[key] = value
+	}
+
+	listReward, err := rwd.rewardRepo.GetRewards(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetReward)
+
+		return "", nil, models.ErrGetReward
+	}
+
+	countReward, err := rwd.rewardRepo.CountRewards(c, payload)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetReward)
+
+		return "", nil, models.ErrGetReward
+	}
+
+	return strconv.Itoa(countReward), listReward, nil
+}
+
+func (rwd *rewardUseCase) GetRewardDetail(c echo.Context, id string) (*models.Reward, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	reward, err := rwd.getReward(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = rwd.rewardRepo.GetRewardTags(c, reward); err != nil {
+		requestLogger.Debug(models.ErrGetReward)
+
+		return nil, models.ErrGetReward
+	}
+
+	quotas, err := rwd.quotaUC.GetByReward(c, reward.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reward.Quotas = &quotas
+
+	return reward, nil
+}
+
+func (rwd *rewardUseCase) UpdateReward(c echo.Context, id string, updateReward *models.Reward) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	reward, err := rwd.getReward(c, id)
+
+	if err != nil {
+		return err
+	}
+
+	updated := *reward
+	updated.Name = getString(updateReward.Name, reward.Name)
+	updated.Description = getString(updateReward.Description, reward.Description)
+	updated.TermsAndConditions = getString(updateReward.TermsAndConditions, reward.TermsAndConditions)
+	updated.HowToUse = getString(updateReward.HowToUse, reward.HowToUse)
+	updated.JournalAccount = getString(updateReward.JournalAccount, reward.JournalAccount)
+	updated.PromoCode = getString(updateReward.PromoCode, reward.PromoCode)
+
+	if updateReward.IsPromoCode != nil {
+		updated.IsPromoCode = updateReward.IsPromoCode
+	}
+
+	if updateReward.Priority != nil {
+		updated.Priority = updateReward.Priority
+	}
+
+	if updateReward.CustomPeriod != nil {
+		if err = updateReward.CustomPeriod.Check(); err != nil {
+			requestLogger.Debug(err)
+
+			return err
+		}
+
+		updated.CustomPeriod = updateReward.CustomPeriod
+	}
+
+	if updateReward.PointExpiry != nil {
+		if err = updateReward.PointExpiry.Check(); err != nil {
+			requestLogger.Debug(err)
+
+			return err
+		}
+
+		updated.PointExpiry = updateReward.PointExpiry
+	}
+
+	if updateReward.Validators != nil {
+		if err = updateReward.Validators.Check(); err != nil {
+			requestLogger.Debug(err)
+
+			return err
+		}
+
+		updated.Validators = updateReward.Validators
+	}
+
+	// the benefit of a reward cannot be changed once a reward transaction is referencing it
+	if reward.IsBenefitChanged(updated) {
+		if err = rwd.checkRewardInUse(c, reward.ID); err != nil {
+			return err
+		}
+	}
+
+	if err = rwd.rewardRepo.UpdateReward(c, &updated); err != nil {
+		requestLogger.Debug(models.ErrRewardUpdateFailed)
+
+		return models.ErrRewardUpdateFailed
+	}
+
+	*updateReward = updated
+
+	return nil
+}
+
+func (rwd *rewardUseCase) UpdateRewardStatus(c echo.Context, id string, updateReward *models.Reward) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if updateReward.Status == nil || (*updateReward.Status != models.RewardStatusActive &&
+		*updateReward.Status != models.RewardStatusInactive) {
+		requestLogger.Debug(models.ErrRewardStatus)
+
+		return models.ErrRewardStatus
+	}
+
+	reward, err := rwd.getReward(c, id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = rwd.rewardRepo.UpdateRewardStatus(c, reward.ID, *updateReward.Status); err != nil {
+		requestLogger.Debug(models.ErrRewardUpdateFailed)
+
+		return models.ErrRewardUpdateFailed
+	}
+
+	return nil
+}
+
+func (rwd *rewardUseCase) DeleteReward(c echo.Context, id string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	reward, err := rwd.getReward(c, id)
+
+	if err != nil {
+		return err
+	}
+
+	// a used reward can only be disabled
+	if err = rwd.checkRewardInUse(c, reward.ID); err != nil {
+		return err
+	}
+
+	if err = rwd.quotaUC.DeleteByReward(c, reward.ID); err != nil {
+		return err
+	}
+
+	if err = rwd.rewardRepo.DeleteRewardTag(c, reward.ID); err != nil {
+		requestLogger.Debug(models.ErrDelRewardFailed)
+
+		return models.ErrDelRewardFailed
+	}
+
+	if err = rwd.rewardRepo.Delete(c, reward.ID); err != nil {
+		requestLogger.Debug(models.ErrDelRewardFailed)
+
+		return models.ErrDelRewardFailed
+	}
+
+	return nil
+}
+
 func (rwd *rewardUseCase) Inquiry(c echo.Context, plValidator *models.PayloadValidator) (models.RewardsInquiry, error) {
+	// validate the inquiry request, if ref channel exist
+	storedInquiry, err := rwd.rewardTrxUC.CheckInquiry(c, plValidator)
+
+	if err != nil {
+		return models.RewardsInquiry{}, err
+	}
+
+	if storedInquiry != nil {
+		return *storedInquiry, nil
+	}
+
+	rwdInquiry, err := rwd.inquiry(c, plValidator)
+
+	if err != nil {
+		_ = rwd.rewardTrxUC.CancelInquiry(c, plValidator)
+
+		return rwdInquiry, err
+	}
+
+	if err = rwd.rewardTrxUC.SaveInquiry(c, plValidator, rwdInquiry); err != nil {
+		if rwdInquiry.RefTrx != "" {
+			_ = rwd.rewardTrxUC.Release(c, rwdInquiry.RefTrx)
+		}
+
+		_ = rwd.rewardTrxUC.CancelInquiry(c, plValidator)
+
+		return models.RewardsInquiry{}, err
+	}
+
+	return rwdInquiry, nil
+}
+
+func (rwd *rewardUseCase) inquiry(c echo.Context, plValidator *models.PayloadValidator) (models.RewardsInquiry, error) {
 	var rwdInquiry models.RewardsInquiry
 	var rwdResponse []models.RewardResponse
+	var validationErrs models.ValidationErrors
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	trxDate, err := time.Parse(time.RFC3339, plValidator.TransactionDate)
@@ -117,7 +368,8 @@ func (rwd *rewardUseCase) Inquiry(c echo.Context, plValidator *models.PayloadVal
 		return rwdInquiry, models.ErrTrxDateFormat
 	}
 
-	// TODO: validate the inquiry request, if ref_id exist
+	// history rules read the customer history through the provider
+	plValidator.History = rwd.rewardTrxUC.GetHistoryProvider(c)
 
 	// check available campaign
 	campaigns, err := rwd.campaignRepo.GetCampaignAvailable(c, trxDate.Format(models.TimeFormat))
@@ -128,82 +380,443 @@ func (rwd *rewardUseCase) Inquiry(c echo.Context, plValidator *models.PayloadVal
 		return rwdInquiry, models.ErrNoCampaign
 	}
 
-	// create array rewards
-	rewards := rwd.putRewards(c, campaigns)
+	// exclusive campaigns are evaluated first
+	campaigns = models.SortCampaigns(campaigns)
+
+	// generate an unique ref ID, every evaluated transaction is a part of the customer history
+	// whether it is rewarded or not
+	refID, err := rwd.rewardTrxUC.GenerateRefID(c, models.NewTransactionHistory(plValidator, "", trxDate))
+
+	if err != nil {
+		return rwdInquiry, err
+	}
+
+	for _, campaign := range campaigns {
+		candidates, campaignErrs := rwd.getCandidates(c, campaign, plValidator, trxDate)
+		validationErrs = append(validationErrs, campaignErrs...)
+
+		// order the eligible rewards based on the campaign stacking policy
+		candidates = campaign.SortCandidates(candidates)
+		maxRewards := campaign.GetMaxRewards()
+		granted := 0
+
+		for _, candidate := range candidates {
+			if maxRewards > 0 && granted >= maxRewards {
+				break
+			}
+
+			rwdResp, err := rwd.grantReward(c, candidate, plValidator, refID)
+
+			// try the next candidate when the reward quota is exhausted
+			if err == models.ErrQuotaExhausted {
+				validationErrs = append(validationErrs,
+					models.NewValidationErrorFrom(models.ValidationCodeQuotaExhausted, "quota", err))
+
+				continue
+			}
+
+			if err == models.ErrUserQuotaExhausted {
+				validationErrs = append(validationErrs,
+					models.NewValidationErrorFrom(models.ValidationCodeUserQuotaExhausted, "quota", err))
+
+				continue
+			}
+
+			if err != nil {
+				_ = rwd.rewardTrxUC.Release(c, refID)
+
+				return rwdInquiry, err
+			}
+
+			rwdResponse = append(rwdResponse, rwdResp)
+			granted++
+		}
+
+		// an exclusive campaign that gives any reward blocks the other campaigns
+		if campaign.IsExclusive() && granted > 0 {
+			break
+		}
+	}
+
+	if len(rwdResponse) > 0 {
+		// insert data to reward transactions
+		_, err = rwd.rewardTrxUC.Create(c, *plValidator, refID, rwdResponse)
+
+		if err != nil {
+			_ = rwd.rewardTrxUC.Release(c, refID)
+
+			return rwdInquiry, err
+		}
+
+		rwdInquiry.RefTrx = refID
+		rwdInquiry.Rewards = &rwdResponse
+	}
+
+	// every failed rule is exposed when the transaction is not given any reward
+	if len(rwdResponse) == 0 && len(validationErrs) > 0 {
+		requestLogger.Debug(validationErrs)
+
+		return rwdInquiry, &models.ValidationFailure{Err: models.ErrRewardUnavailable, Errors: validationErrs}
+	}
+
+	return rwdInquiry, nil
+}
+
+func (rwd *rewardUseCase) Simulation(c echo.Context, plSimulation *models.PayloadRewardSimulation) ([]models.RewardSimulation, error) {
+	var simulations []models.RewardSimulation
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	plValidator := &plSimulation.PayloadValidator
+	asOfDate, err := time.Parse(time.RFC3339, plValidator.TransactionDate)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrTrxDateFormat)
+
+		return simulations, models.ErrTrxDateFormat
+	}
+
+	if plSimulation.AsOfDate != "" {
+		asOfDate, err = time.Parse(time.RFC3339, plSimulation.AsOfDate)
+
+		if err != nil {
+			requestLogger.Debug(models.ErrAsOfDateFormat)
+
+			return simulations, models.ErrAsOfDateFormat
+		}
+	}
+
+	// simulate the history rules with the same history as the inquiry
+	plValidator.History = rwd.rewardTrxUC.GetHistoryProvider(c)
+
+	// check available campaign
+	campaigns, err := rwd.campaignRepo.GetCampaignAvailable(c, asOfDate.Format(models.TimeFormat))
+
+	if err != nil {
+		requestLogger.Debug(models.ErrNoCampaign)
+
+		return simulations, models.ErrNoCampaign
+	}
+
+	// simulate the campaigns with the same order as the inquiry
+	campaigns = models.SortCampaigns(campaigns)
+	blocked := false
+
+	for _, campaign := range campaigns {
+		var candidates []models.RewardCandidate
+		cmpSimulations := rwd.simulateRewards(c, campaign, plValidator, asOfDate)
+
+		for _, simulation := range cmpSimulations {
+			if simulation.Eligible {
+				candidates = append(candidates, simulation.candidate)
+			}
+		}
+
+		// mark the rewards that would be selected by the stacking policy, the quota is not considered
+		selected := map[int64]bool{}
+		maxRewards := campaign.GetMaxRewards()
+
+		for _, candidate := range campaign.SortCandidates(candidates) {
+			if blocked || (maxRewards > 0 && len(selected) >= maxRewards) {
+				break
+			}
+
+			selected[candidate.Reward.ID] = true
+		}
+
+		for _, simulation := range cmpSimulations {
+			simulation.Selected = selected[simulation.RewardID]
+			simulations = append(simulations, simulation.RewardSimulation)
+		}
+
+		if campaign.IsExclusive() && len(selected) > 0 {
+			blocked = true
+		}
+	}
+
+	return simulations, nil
+}
+
+type rewardSimulation struct {
+	models.RewardSimulation
+	candidate models.RewardCandidate
+}
+
+func (rwd *rewardUseCase) simulateRewards(c echo.Context, campaign *models.Campaign,
+	plValidator *models.PayloadValidator, asOfDate time.Time) []rewardSimulation {
+	var simulations []rewardSimulation
+
+	for _, reward := range rwd.putRewards(c, campaign) {
+		simulation := rewardSimulation{}
+		simulation.CampaignID = campaign.ID
+		simulation.CampaignName = campaign.Name
+		simulation.StackingPolicy = campaign.GetStackingPolicyText()
+		simulation.RewardID = reward.ID
+		simulation.RewardName = reward.Name
+
+		// validate promo code
+		promoErr := rwd.validatePromoCode(*reward.Tags, reward.PromoCode, plValidator.PromoCode)
+		simulation.PromoCodeMatch = promoErr == nil
+
+		// validate each reward
+		failedRule, err := reward.Validators.ValidateRule(plValidator)
+
+		if err == nil && promoErr != nil {
+			failedRule, err = "promoCode", promoErr
+		}
+
+		if err == nil && !reward.IsInPeriod(asOfDate) {
+			failedRule, err = "customPeriod", models.ErrRewardOutOfPeriod
+		}
+
+		if err == nil {
+			_, err = reward.Validators.GetTier(plValidator)
+			failedRule = "tiers"
+		}
+
+		if err == nil {
+			err = rwd.segmentUC.CheckSegments(c, reward.Validators, plValidator.CIF)
+			failedRule = "segments"
+		}
+
+		if err == nil {
+			err = rwd.tierUC.CheckMembershipTiers(c, reward.Validators, plValidator.CIF)
+			failedRule = "membershipTiers"
+		}
+
+		// get the rewards value/benefit
+		if err == nil {
+			simulation.Value, err = reward.Validators.GetRewardValue(plValidator)
+			failedRule = "formula"
+		}
+
+		if err != nil {
+			simulation.FailedRule = failedRule
+			simulation.Reason = err.Error()
+			simulations = append(simulations, simulation)
+
+			continue
+		}
+
+		simulation.Eligible = true
+		simulation.candidate = models.RewardCandidate{Reward: reward, Value: simulation.Value}
+		simulations = append(simulations, simulation)
+	}
+
+	return simulations
+}
+
+func (rwd *rewardUseCase) getReward(c echo.Context, id string) (*models.Reward, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	rewardID, err := strconv.ParseInt(id, 10, 64)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, errors.New("Something went wrong with input ID")
+	}
 
-	for _, reward := range rewards {
-		var rwdResp models.RewardResponse
+	reward, err := rwd.rewardRepo.GetRewardByID(c, rewardID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrNoReward)
+
+		return nil, models.ErrNoReward
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetReward)
+
+		return nil, models.ErrGetReward
+	}
+
+	return reward, nil
+}
+
+func (rwd *rewardUseCase) checkRewardInUse(c echo.Context, rewardID int64) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	counter, err := rwd.rewardRepo.CountRewardTrx(c, rewardID)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetReward)
+
+		return models.ErrGetReward
+	}
+
+	if counter > 0 {
+		requestLogger.Debug(models.ErrRewardInUse)
+
+		return models.ErrRewardInUse
+	}
+
+	return nil
+}
+
+func (rwd *rewardUseCase) putRewards(c echo.Context, campaign *models.Campaign) []models.Reward {
+	var rewards []models.Reward
+
+	if campaign.Rewards == nil {
+		return rewards
+	}
+
+	for _, reward := range *campaign.Rewards {
+		// disabled rewards are not given to the customer
+		if !reward.IsActive() {
+			continue
+		}
+
+		rwd.rewardRepo.GetRewardTags(c, &reward)
+		rewards = append(rewards, reward)
+	}
+
+	return rewards
+}
+
+// getCandidates to get the eligible rewards of a campaign along the failed validations of the other rewards
+func (rwd *rewardUseCase) getCandidates(c echo.Context, campaign *models.Campaign,
+	plValidator *models.PayloadValidator, trxDate time.Time) ([]models.RewardCandidate, models.ValidationErrors) {
+	var candidates []models.RewardCandidate
+	var validationErrs models.ValidationErrors
+	logger := models.RequestLogger{}
+
+	for _, reward := range rwd.putRewards(c, campaign) {
 		rewardLogger := logger.GetRequestLogger(c, reward.Validators)
 
-		// TODO: validate reward quota
+		// validate the reward schedule
+		if !reward.IsInPeriod(trxDate) {
+			rewardLogger.Debug(models.ErrRewardOutOfPeriod)
+			validationErrs = append(validationErrs, models.NewValidationErrorFrom(models.ValidationCodeOutOfPeriod,
+				"period", models.ErrRewardOutOfPeriod))
+
+			continue
+		}
 
 		// validate promo code
-		if err = rwd.validatePromoCode(*reward.Tags, reward.PromoCode, plValidator.PromoCode); err != nil {
+		if err := rwd.validatePromoCode(*reward.Tags, reward.PromoCode, plValidator.PromoCode); err != nil {
 			rewardLogger.Debug(err)
+			validationErrs = append(validationErrs, models.NewValidationErrorFrom(models.ValidationCodePromoCodeNotMatch,
+				"promoCode", err))
 
 			continue
 		}
 
 		// validate each reward
-		if err = reward.Validators.Validate(plValidator); err != nil {
+		if err := reward.Validators.Validate(plValidator); err != nil {
 			rewardLogger.Debug(err)
+			validationErrs = append(validationErrs, models.GetValidationErrors(err)...)
 
 			continue
 		}
 
-		// get the rewards value/benefit
-		rwdValue, _ := reward.Validators.GetRewardValue(plValidator)
-
-		// check rewards voucher if any
-		rwdVoucher, _ := reward.Validators.GetVoucherResult()
+		// validate the customer segments
+		if err := rwd.segmentUC.CheckSegments(c, reward.Validators, plValidator.CIF); err != nil {
+			code := models.ValidationCodeSegmentNotMatch
 
-		if rwdVoucher != 0 {
-			plVoucherBuy := &models.PayloadVoucherBuy{
-				CIF:       plValidator.CIF,
-				VoucherID: strconv.FormatInt(rwdVoucher, 10),
+			if err == models.ErrGetSegment {
+				code = models.ValidationCodeSegmentUnavailable
 			}
 
-			voucherCode, err := rwd.voucherUC.VoucherGive(c, plVoucherBuy)
+			rewardLogger.Debug(err)
+			validationErrs = append(validationErrs, models.NewValidationErrorFrom(code, "segments", err))
 
-			if err != nil {
-				return rwdInquiry, nil
+			continue
+		}
+
+		// validate the customer membership tier
+		if err := rwd.tierUC.CheckMembershipTiers(c, reward.Validators, plValidator.CIF); err != nil {
+			code := models.ValidationCodeMembershipTierNotMatch
+
+			if err != models.ErrMembershipTierNotTargeted {
+				code = models.ValidationCodeMembershipTierUnavailable
 			}
 
-			rwdResp.VoucherName = voucherCode.Voucher.Name
-			rwdValue = 0 // if voucher reward is exist then reward value should be nil
+			rewardLogger.Debug(err)
+			validationErrs = append(validationErrs, models.NewValidationErrorFrom(code, "membershipTiers", err))
+
+			continue
 		}
 
-		// populate reward response
-		rwdResp.Type = reward.GetRewardTypeText()
-		rwdResp.Value = rwdValue
-		rwdResp.JournalAccount = reward.JournalAccount
-		rwdResponse = append(rwdResponse, rwdResp)
+		// get the rewards value/benefit, a reward without a value is not given
+		rwdValue, err := reward.Validators.GetRewardValue(plValidator)
+
+		if err != nil {
+			rewardLogger.Debug(err)
+			validationErrs = append(validationErrs, models.GetValidationErrors(err)...)
+
+			continue
+		}
+
+		candidates = append(candidates, models.RewardCandidate{Reward: reward, Value: rwdValue})
 	}
 
-	if len(rwdResponse) == 0 {
-		return rwdInquiry, nil
+	return candidates, validationErrs
+}
+
+func (rwd *rewardUseCase) grantReward(c echo.Context, candidate models.RewardCandidate,
+	plValidator *models.PayloadValidator, refID string) (models.RewardResponse, error) {
+	var rwdResp models.RewardResponse
+	logger := models.RequestLogger{}
+	reward := candidate.Reward
+	rwdValue := candidate.Value
+	rewardLogger := logger.GetRequestLogger(c, reward.Validators)
+
+	// use the reward quota
+	if err := rwd.quotaUC.UseQuota(c, &reward, plValidator, refID); err != nil {
+		rewardLogger.Debug(err)
+
+		return rwdResp, err
 	}
 
-	// TODO insert data to reward history
+	// check rewards voucher if any
+	rwdVoucher, _ := reward.Validators.GetVoucherResult()
 
-	// TODO: generate an unique ref ID
-	rwdInquiry.RefTrx = "1122334455"
-	rwdInquiry.Rewards = &rwdResponse
+	if rwdVoucher != 0 {
+		plVoucherBuy := &models.PayloadVoucherBuy{
+			CIF:       plValidator.CIF,
+			VoucherID: strconv.FormatInt(rwdVoucher, 10),
+			RefID:     refID,
+		}
 
-	return rwdInquiry, nil
-}
+		voucherCode, err := rwd.voucherUC.VoucherGive(c, plVoucherBuy)
 
-func (rwd *rewardUseCase) putRewards(c echo.Context, campaigns []*models.Campaign) []models.Reward {
-	var rewards []models.Reward
+		if err != nil {
+			return rwdResp, err
+		}
 
-	for _, campaign := range campaigns {
-		for _, reward := range *campaign.Rewards {
-			rwd.rewardRepo.GetRewardTags(c, &reward)
-			rewards = append(rewards, reward)
+		rwdResp.VoucherName = voucherCode.Voucher.Name
+		rwdValue = 0 // if voucher reward is exist then reward value should be nil
+	}
+
+	// convert goldback value into gram of gold with the gold price snapshot
+	if reward.Type != nil && *reward.Type == models.RewardTypeGoldback && rwdValue > 0 {
+		trxDate, _ := time.Parse(time.RFC3339, plValidator.TransactionDate)
+		gram, goldPrice, err := rwd.goldPriceUC.GetGoldback(c, rwdValue, trxDate)
+
+		if err != nil {
+			return rwdResp, err
 		}
+
+		rwdResp.Gram = &gram
+		rwdResp.GoldPrice = goldPrice
 	}
 
-	return rewards
+	// the earned points are multiplied by the membership tier of the customer, the expired date of them
+	// is fixed at inquiry, so a later rule change is not affecting them
+	if reward.Type != nil && *reward.Type == models.RewardTypePoint && rwdValue > 0 {
+		rwdValue = rwdValue * rwd.tierUC.GetMultiplier(c, plValidator.CIF)
+		trxDate, _ := time.Parse(time.RFC3339, plValidator.TransactionDate)
+		rwdResp.ExpiredDate = reward.GetPointExpiry().GetExpiredDate(trxDate, models.GetBusinessLocation())
+	}
+
+	// populate reward response
+	rewardID := reward.ID
+	rwdResp.RewardID = &rewardID
+	rwdResp.Type = reward.GetRewardTypeText()
+	rwdResp.Value = rwdValue
+	rwdResp.JournalAccount = reward.JournalAccount
+
+	return rwdResp, nil
 }
 
 func (rwd *rewardUseCase) validatePromoCode(tags []models.Tag, validPC, promoCode string) error {
@@ -219,3 +832,11 @@ func (rwd *rewardUseCase) validatePromoCode(tags []models.Tag, validPC, promoCod
 
 	return models.ErrPromoCode
 }
+
+func getString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
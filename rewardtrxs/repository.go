@@ -2,13 +2,25 @@ package rewardtrxs
 
 import (
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
 
 // Repository represent the reward transactions repository contract
 type Repository interface {
-	Create(echo.Context, models.PayloadValidator, int64) (models.RewardTrx, error)
-	UpdateSuccess(echo.Context, map[string]interface{}) error
-	UpdateReject(echo.Context, map[string]interface{}) error
+	Create(echo.Context, models.PayloadValidator, string, []models.RewardResponse) ([]models.RewardTrx, error)
+	GetByRefID(echo.Context, string, string) ([]models.RewardTrx, error)
+	GetByRefCore(echo.Context, string, string) ([]models.RewardTrx, error)
+	UpdateSuccess(echo.Context, map[string]interface{}) (int64, error)
+	UpdateReject(echo.Context, map[string]interface{}) (int64, error)
+	UpdateTimeout(echo.Context, time.Time) ([]string, error)
+	Release(echo.Context, string) error
+	Reverse(echo.Context, *models.PointReversal) error
+	CreateInquiryLog(echo.Context, *models.RewardInquiryLog) (bool, error)
+	GetInquiryLog(echo.Context, string, string) (*models.RewardInquiryLog, error)
+	UpdateInquiryLog(echo.Context, *models.RewardInquiryLog) error
+	DeleteInquiryLog(echo.Context, int64) error
+	CreateHistory(echo.Context, *models.TransactionHistory) (bool, error)
+	GetHistory(echo.Context, models.HistoryQuery) (models.HistorySummary, error)
 }
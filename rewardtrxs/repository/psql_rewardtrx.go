@@ -4,95 +4,348 @@ import (
 	"database/sql"
 	"encoding/json"
 	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointhistories"
+	"gade/srv-gade-point/quotas"
 	"gade/srv-gade-point/rewardtrxs"
-	"math/rand"
+	"gade/srv-gade-point/vouchers"
 	"time"
 
 	"github.com/labstack/echo"
+	"github.com/lib/pq"
 )
 
+const historyRefIDKey = "transaction_histories_ref_id_key"
+
 type psqlRewardTrxRepository struct {
-	Conn *sql.DB
+	Conn         *sql.DB
+	pHistoryRepo pointhistories.Repository
+	voucherRepo  vouchers.Repository
+	quotaRepo    quotas.Repository
 }
 
-const letterBytes = "1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-
-// NewPsqlRewardTrxRepository will create an object that represent the quotas.Repository interface
-func NewPsqlRewardTrxRepository(Conn *sql.DB) rewardtrxs.Repository {
-	return &psqlRewardTrxRepository{Conn}
+// NewPsqlRewardTrxRepository will create an object that represent the rewardtrxs.Repository interface
+func NewPsqlRewardTrxRepository(Conn *sql.DB, pHistoryRepo pointhistories.Repository, voucherRepo vouchers.Repository,
+	quotaRepo quotas.Repository) rewardtrxs.Repository {
+	return &psqlRewardTrxRepository{Conn, pHistoryRepo, voucherRepo, quotaRepo}
 }
 
-func (quotTrxRepo *psqlRewardTrxRepository) Create(c echo.Context, payload models.PayloadValidator, rewardID int64) (models.RewardTrx, error) {
-	var rewardTrx models.RewardTrx
+func (quotTrxRepo *psqlRewardTrxRepository) Create(c echo.Context, payload models.PayloadValidator, refID string,
+	rwdResponses []models.RewardResponse) ([]models.RewardTrx, error) {
+	var rewardTrxs []models.RewardTrx
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	now := time.Now()
-	refID := randRefID(20)
+	requestData, err := json.Marshal(payload)
 
-	query := `INSERT INTO reward_transactions (status, ref_id, cif, reward_id, used_promo_code, transaction_date, inquiry_date, request_data, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	if err != nil {
+		requestLogger.Debug(err)
 
-	stmt, err := quotTrxRepo.Conn.Prepare(query)
+		return nil, err
+	}
+
+	trxDate, err := time.Parse(time.RFC3339, payload.TransactionDate)
 
 	if err != nil {
-		requestLogger.Debug(err)
+		requestLogger.Debug(models.ErrTrxDateFormat)
 
-		return rewardTrx, err
+		return nil, err
 	}
 
-	var lastID int64
-	requestData, err := json.Marshal(payload)
+	query := `INSERT INTO reward_transactions (status, ref_id, cif, reward_id, used_promo_code, transaction_date,
+		inquired_date, request_data, response_data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	tx, err := quotTrxRepo.Conn.Begin()
 
 	if err != nil {
 		requestLogger.Debug(err)
 
-		return rewardTrx, err
+		return nil, err
 	}
 
-	trxDate, err := time.Parse(time.RFC3339, payload.TransactionDate)
+	stmt, err := tx.Prepare(query)
 
 	if err != nil {
-		requestLogger.Debug(models.ErrTrxDateFormat)
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
 
-		return rewardTrx, err
+		return nil, err
 	}
 
-	rewardTrx = models.RewardTrx{
-		Status:          &models.RewardTrxInquired,
-		RefID:           refID,
-		RewardID:        &rewardID,
-		CIF:             payload.CIF,
-		UsedPromoCode:   payload.PromoCode,
-		TransactionDate: &trxDate,
-		InquiryDate:     &now,
-		RequestData:     string(requestData),
-		CreatedAt:       &now,
+	defer stmt.Close()
+
+	// one reward transaction for each granted reward, all of them share the same ref id
+	for _, rwdResponse := range rwdResponses {
+		responseData, err := json.Marshal(rwdResponse)
+
+		if err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		rewardTrx := models.RewardTrx{
+			Status:          &models.RewardTrxInquired,
+			RefID:           refID,
+			RewardID:        rwdResponse.RewardID,
+			CIF:             payload.CIF,
+			UsedPromoCode:   payload.PromoCode,
+			TransactionDate: &trxDate,
+			InquiryDate:     &now,
+			RequestData:     string(requestData),
+			ResponseData:    string(responseData),
+			CreatedAt:       &now,
+		}
+
+		err = stmt.QueryRow(
+			&rewardTrx.Status, &rewardTrx.RefID, &rewardTrx.CIF, &rewardTrx.RewardID, &rewardTrx.UsedPromoCode,
+			&rewardTrx.TransactionDate, &rewardTrx.InquiryDate, &rewardTrx.RequestData, &rewardTrx.ResponseData,
+			&rewardTrx.CreatedAt,
+		).Scan(&rewardTrx.ID)
+
+		if err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		// the earned points are kept pending until the transaction is succeeded
+		if pointHistory := rewardTrx.GetPointHistory(rwdResponse); pointHistory != nil {
+			if err = quotTrxRepo.pHistoryRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+				requestLogger.Debug(err)
+				_ = tx.Rollback()
+
+				return nil, err
+			}
+		}
+
+		rewardTrxs = append(rewardTrxs, rewardTrx)
 	}
 
-	err = stmt.QueryRow(
-		&rewardTrx.Status, &rewardTrx.RefID, &rewardTrx.CIF, &rewardTrx.RewardID, &rewardTrx.UsedPromoCode,
-		&rewardTrx.TransactionDate, &rewardTrx.InquiryDate, &rewardTrx.RequestData, &rewardTrx.CreatedAt,
-	).Scan(&lastID)
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return rewardTrxs, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) GetByRefID(c echo.Context, refID string, cif string) ([]models.RewardTrx, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT ` + rewardTrxColumns + ` FROM reward_transactions WHERE ref_id = $1 AND cif = $2 ORDER BY id ASC`
+	rewardTrxs, err := getRewardTrxs(quotTrxRepo.Conn, query, refID, cif)
 
 	if err != nil {
 		requestLogger.Debug(err)
 
-		return rewardTrx, err
+		return nil, err
 	}
 
-	return rewardTrx, nil
+	return rewardTrxs, nil
 }
 
-func (quotTrxRepo *psqlRewardTrxRepository) UpdateSuccess(c echo.Context, payload map[string]interface{}) error {
+func (quotTrxRepo *psqlRewardTrxRepository) GetByRefCore(c echo.Context, refCore string, cif string) ([]models.RewardTrx, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT ` + rewardTrxColumns + ` FROM reward_transactions WHERE ref_core = $1 AND cif = $2
+		AND status IN ($3, $4) ORDER BY id ASC`
+	rewardTrxs, err := getRewardTrxs(quotTrxRepo.Conn, query, refCore, cif, models.RewardTrxSucceeded,
+		models.RewardTrxReversed)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return rewardTrxs, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) UpdateSuccess(c echo.Context, payload map[string]interface{}) (int64, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	now := time.Now()
-	status := int8(1)
 	refCore := payload["refCore"].(string)
 	cif := payload["cif"].(string)
 	refID := payload["refTrx"].(string)
-	query := `UPDATE reward_transactions SET status = $1, ref_core = $2, successed_date = $3, updated_date = $4 WHERE cif = $5 and ref_id = $6`
-	stmt, err := quotTrxRepo.Conn.Prepare(query)
+	query := `UPDATE reward_transactions SET status = $1, ref_core = $2, succeeded_date = $3, updated_at = $4
+		WHERE cif = $5 AND ref_id = $6 AND status = $7`
+	tx, err := quotTrxRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	result, err := tx.Exec(query, &models.RewardTrxSucceeded, &refCore, &now, &now, &cif, &refID, &models.RewardTrxInquired)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	counter, err := result.RowsAffected()
+
+	if err != nil || counter == 0 {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = quotTrxRepo.pHistoryRepo.SucceedPointHistories(tx, refID, refCore, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	// finalize the vouchers that has been given on inquiry
+	if err = quotTrxRepo.voucherRepo.ConfirmPromoCodes(tx, refID, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return counter, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) UpdateReject(c echo.Context, payload map[string]interface{}) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	cif := payload["cif"].(string)
+	refID := payload["refTrx"].(string)
+	query := `UPDATE reward_transactions SET status = $1, rejected_date = $2, updated_at = $3
+		WHERE cif = $4 AND ref_id = $5 AND status = $6`
+	tx, err := quotTrxRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	result, err := tx.Exec(query, &models.RewardTrxRejected, &now, &now, &cif, &refID, &models.RewardTrxInquired)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	counter, err := result.RowsAffected()
+
+	if err != nil || counter == 0 {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = quotTrxRepo.releaseRewardTrx(tx, refID, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return counter, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) UpdateTimeout(c echo.Context, inquiredBefore time.Time) ([]string, error) {
+	var refIDs []string
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE reward_transactions SET status = $1, timeout_date = $2, updated_at = $3
+		WHERE status = $4 AND inquired_date < $5 RETURNING ref_id`
+	tx, err := quotTrxRepo.Conn.Begin()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	rows, err := tx.Query(query, &models.RewardTrxTimeout, &now, &now, &models.RewardTrxInquired, &inquiredBefore)
+
+	if err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return nil, err
+	}
+
+	swept := map[string]bool{}
+
+	for rows.Next() {
+		var refID string
+
+		if err = rows.Scan(&refID); err != nil {
+			requestLogger.Debug(err)
+			rows.Close()
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+
+		if swept[refID] {
+			continue
+		}
+
+		swept[refID] = true
+		refIDs = append(refIDs, refID)
+	}
+
+	rows.Close()
+
+	for _, refID := range refIDs {
+		if err = quotTrxRepo.releaseRewardTrx(tx, refID, now); err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return refIDs, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) Release(c echo.Context, refID string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE reward_transactions SET status = $1, rejected_date = $2, updated_at = $3
+		WHERE ref_id = $4 AND status = $5`
+	tx, err := quotTrxRepo.Conn.Begin()
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -100,7 +353,44 @@ func (quotTrxRepo *psqlRewardTrxRepository) UpdateSuccess(c echo.Context, payloa
 		return err
 	}
 
-	var lastID int64
+	if _, err = tx.Exec(query, &models.RewardTrxRejected, &now, &now, &refID, &models.RewardTrxInquired); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = quotTrxRepo.releaseRewardTrx(tx, refID, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	// a failed inquiry is not a part of the customer history
+	query = `DELETE FROM transaction_histories WHERE ref_id = $1`
+
+	if _, err = tx.Exec(query, refID); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) Reverse(c echo.Context, reversal *models.PointReversal) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	tx, err := quotTrxRepo.Conn.Begin()
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -108,26 +398,141 @@ func (quotTrxRepo *psqlRewardTrxRepository) UpdateSuccess(c echo.Context, payloa
 		return err
 	}
 
-	err = stmt.QueryRow(&status, &refCore, &now, &now, &cif, &refID).Scan(&lastID)
+	// a retried ref reversal never reaches the reward transactions, so it is never taken back twice
+	query := `INSERT INTO point_reversals (ref_reversal, ref_core, cif, reversed_amount, ratio, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (ref_reversal) DO NOTHING RETURNING id`
+	err = tx.QueryRow(query, reversal.RefReversal, reversal.RefCore, reversal.CIF, reversal.ReversedAmount,
+		reversal.Ratio, &now).Scan(&reversal.ID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrReversalProcessed)
+		_ = tx.Rollback()
+
+		return models.ErrReversalProcessed
+	}
 
 	if err != nil {
 		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	// the ratio is only moved from the one that has been read, so two reversals could not take back the same part
+	query = `UPDATE reward_transactions SET reversed_ratio = $1, status = $2, updated_at = $3
+		WHERE id = $4 AND reversed_ratio = $5 AND status = $6`
+
+	for _, rwdReversal := range reversal.Rewards {
+		status := models.RewardTrxSucceeded
+
+		if rwdReversal.ReversedRatio == 1 {
+			status = models.RewardTrxReversed
+		}
+
+		result, err := tx.Exec(query, rwdReversal.ReversedRatio, status, &now, rwdReversal.RewardTrxID,
+			rwdReversal.PreviousRatio, models.RewardTrxSucceeded)
+
+		if err != nil {
+			requestLogger.Debug(err)
+			_ = tx.Rollback()
+
+			return err
+		}
+
+		if counter, err := result.RowsAffected(); err != nil || counter == 0 {
+			requestLogger.Debug(models.ErrRewardTrxProcessed)
+			_ = tx.Rollback()
+
+			return models.ErrRewardTrxProcessed
+		}
+	}
+
+	if err = quotTrxRepo.reversePoint(tx, reversal, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
 
 		return err
 	}
 
+	if err = voidVouchers(tx, reversal, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	reversal.CreatedAt = &now
+
 	return nil
 }
 
-func (quotTrxRepo *psqlRewardTrxRepository) UpdateReject(c echo.Context, payload map[string]interface{}) error {
+func (quotTrxRepo *psqlRewardTrxRepository) CreateInquiryLog(c echo.Context, inquiryLog *models.RewardInquiryLog) (bool, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	now := time.Now()
-	status := int8(2)
-	cif := payload["cif"].(string)
-	refID := payload["refTrx"].(string)
-	query := `UPDATE reward_transactions SET status = $1, rejected_date = $2, updated_date = $3 WHERE cif = $4 and ref_id = $5`
-	stmt, err := quotTrxRepo.Conn.Prepare(query)
+	query := `INSERT INTO reward_inquiries (ref_channel, cif, request_hash, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ref_channel, cif) DO NOTHING RETURNING id`
+	err := quotTrxRepo.Conn.QueryRow(
+		query, inquiryLog.RefChannel, inquiryLog.CIF, inquiryLog.RequestHash, &now,
+	).Scan(&inquiryLog.ID)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return false, err
+	}
+
+	inquiryLog.CreatedAt = &now
+
+	return true, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) GetInquiryLog(c echo.Context, refChannel string, cif string) (*models.RewardInquiryLog, error) {
+	var createdAt, updatedAt pq.NullTime
+	inquiryLog := models.RewardInquiryLog{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT id, ref_channel, cif, coalesce(ref_id, ''), request_hash, response_data, created_at, updated_at
+		FROM reward_inquiries WHERE ref_channel = $1 AND cif = $2`
+	err := quotTrxRepo.Conn.QueryRow(query, refChannel, cif).Scan(
+		&inquiryLog.ID,
+		&inquiryLog.RefChannel,
+		&inquiryLog.CIF,
+		&inquiryLog.RefID,
+		&inquiryLog.RequestHash,
+		&inquiryLog.ResponseData,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	inquiryLog.CreatedAt = nullTime(createdAt)
+	inquiryLog.UpdatedAt = nullTime(updatedAt)
+
+	return &inquiryLog, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) UpdateInquiryLog(c echo.Context, inquiryLog *models.RewardInquiryLog) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `UPDATE reward_inquiries SET ref_id = NULLIF($1, ''), response_data = $2, updated_at = $3 WHERE id = $4`
+	_, err := quotTrxRepo.Conn.Exec(query, inquiryLog.RefID, inquiryLog.ResponseData, &now, inquiryLog.ID)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -135,7 +540,16 @@ func (quotTrxRepo *psqlRewardTrxRepository) UpdateReject(c echo.Context, payload
 		return err
 	}
 
-	var lastID int64
+	inquiryLog.UpdatedAt = &now
+
+	return nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) DeleteInquiryLog(c echo.Context, id int64) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `DELETE FROM reward_inquiries WHERE id = $1`
+	_, err := quotTrxRepo.Conn.Exec(query, id)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -143,23 +557,204 @@ func (quotTrxRepo *psqlRewardTrxRepository) UpdateReject(c echo.Context, payload
 		return err
 	}
 
-	err = stmt.QueryRow(&status, &now, &now, &cif, &refID).Scan(&lastID)
+	return nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) CreateHistory(c echo.Context, trxHistory *models.TransactionHistory) (bool, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+
+	// a retried inquiry of the same reference channel keeps its record and the ref id of it,
+	// so it is only counted once and still follows the status of its reward transactions
+	query := `INSERT INTO transaction_histories (ref_id, ref_channel, cif, product, transaction_amount,
+		transaction_date, created_at) VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), $5, $6, $7)
+		ON CONFLICT (ref_channel, cif) WHERE ref_channel IS NOT NULL DO UPDATE SET ref_channel = EXCLUDED.ref_channel
+		RETURNING id, ref_id`
+	err := quotTrxRepo.Conn.QueryRow(query, trxHistory.RefID, trxHistory.RefChannel, trxHistory.CIF,
+		trxHistory.Product, trxHistory.TransactionAmount, trxHistory.TransactionDate, &now).Scan(&trxHistory.ID,
+		&trxHistory.RefID)
+
+	// the ref id is already used by another transaction
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == historyRefIDKey {
+		return false, nil
+	}
 
 	if err != nil {
 		requestLogger.Debug(err)
 
+		return false, err
+	}
+
+	trxHistory.CreatedAt = &now
+
+	return true, nil
+}
+
+func (quotTrxRepo *psqlRewardTrxRepository) GetHistory(c echo.Context, historyQuery models.HistoryQuery) (models.HistorySummary, error) {
+	var summary models.HistorySummary
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// the transactions that are failed on the core are left out by the status of their reward transactions
+	query := `SELECT count(th.id), coalesce(sum(th.transaction_amount), 0) FROM transaction_histories th
+		WHERE th.cif = $1 AND th.transaction_date >= $2 AND th.transaction_date < $3 AND ($4 = '' OR th.product = $4)
+		AND NOT EXISTS (SELECT 1 FROM reward_transactions rt WHERE rt.ref_id = th.ref_id
+			AND rt.status IN ($5, $6, $7))`
+	err := quotTrxRepo.Conn.QueryRow(query, historyQuery.CIF, historyQuery.From, historyQuery.Until,
+		historyQuery.Product, models.RewardTrxRejected, models.RewardTrxTimeout, models.RewardTrxReversed,
+	).Scan(&summary.Count, &summary.Amount)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// releaseRewardTrx to drop the pending points and release the promo codes and quotas reserved by a reward transaction
+func (quotTrxRepo *psqlRewardTrxRepository) releaseRewardTrx(tx *sql.Tx, refID string, now time.Time) error {
+	if err := quotTrxRepo.pHistoryRepo.DeletePendingPointHistories(tx, refID); err != nil {
+		return err
+	}
+
+	if err := quotTrxRepo.voucherRepo.ReleasePromoCodes(tx, refID, now); err != nil {
 		return err
 	}
 
+	return quotTrxRepo.quotaRepo.RefundQuotaUsages(tx, refID, now)
+}
+
+// reversePoint to take back the reversed points from the locked customer balance
+func (quotTrxRepo *psqlRewardTrxRepository) reversePoint(tx *sql.Tx, reversal *models.PointReversal, now time.Time) error {
+	var balance float64
+	pointHistory := reversal.GetPointHistory(now)
+
+	if pointHistory == nil {
+		return nil
+	}
+
+	query := `SELECT pb.balance FROM point_balances pb JOIN point_accounts pa ON pa.id = pb.account_id
+		WHERE pa.code = $1 AND pa.type = $2 FOR UPDATE OF pb`
+	err := tx.QueryRow(query, reversal.CIF, models.PointAccountTypeCustomer).Scan(&balance)
+
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	reversal.SetPointBalance(pointHistory, balance)
+
+	if *pointHistory.PointAmount > 0 {
+		if err = quotTrxRepo.pHistoryRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+			return err
+		}
+	}
+
+	query = `UPDATE point_reversals SET point_amount = $1, point_shortfall = $2 WHERE id = $3`
+	_, err = tx.Exec(query, reversal.PointAmount, reversal.PointShortfall, reversal.ID)
+
+	return err
+}
+
+// voidVouchers to void the bought reward vouchers of the fully reversed transactions, a redeemed one is kept
+func voidVouchers(tx *sql.Tx, reversal *models.PointReversal, now time.Time) error {
+	voided := map[string]bool{}
+	query := `UPDATE voucher_codes SET status = $1, updated_at = $2 WHERE ref_id = $3 AND user_id = $4
+		AND status = $5 RETURNING promo_code`
+
+	for _, rwdReversal := range reversal.Rewards {
+		if !rwdReversal.VoidVoucher || voided[rwdReversal.RefID] {
+			continue
+		}
+
+		voided[rwdReversal.RefID] = true
+		rows, err := tx.Query(query, models.VoucherCodeStatusVoid, &now, rwdReversal.RefID, reversal.CIF,
+			models.VoucherCodeStatusBought)
+
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var promoCode string
+
+			if err = rows.Scan(&promoCode); err != nil {
+				rows.Close()
+
+				return err
+			}
+
+			reversal.VoidedVouchers = append(reversal.VoidedVouchers, promoCode)
+		}
+
+		rows.Close()
+	}
+
 	return nil
 }
 
-func randRefID(n int) string {
-	rand.Seed(time.Now().UnixNano())
+// rewardTrxColumns to store the selected columns of a reward transaction in the order of getRewardTrxs scan
+const rewardTrxColumns = `id, status, coalesce(ref_core, ''), ref_id, reward_id, cif, coalesce(used_promo_code, ''),
+	transaction_date, inquired_date, succeeded_date, rejected_date, timeout_date, request_data,
+	coalesce(response_data, '{}'), reversed_ratio, created_at, updated_at`
+
+func getRewardTrxs(conn *sql.DB, query string, args ...interface{}) ([]models.RewardTrx, error) {
+	var rewardTrxs []models.RewardTrx
+	rows, err := conn.Query(query, args...)
 
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letterBytes[rand.Int63()%int64(len(letterBytes))]
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var rewardTrx models.RewardTrx
+		var trxDate, inquiryDate, succeededDate, rejectedDate, timeoutDate, createdAt, updatedAt pq.NullTime
+
+		err = rows.Scan(
+			&rewardTrx.ID,
+			&rewardTrx.Status,
+			&rewardTrx.RefCore,
+			&rewardTrx.RefID,
+			&rewardTrx.RewardID,
+			&rewardTrx.CIF,
+			&rewardTrx.UsedPromoCode,
+			&trxDate,
+			&inquiryDate,
+			&succeededDate,
+			&rejectedDate,
+			&timeoutDate,
+			&rewardTrx.RequestData,
+			&rewardTrx.ResponseData,
+			&rewardTrx.ReversedRatio,
+			&createdAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rewardTrx.TransactionDate = nullTime(trxDate)
+		rewardTrx.InquiryDate = nullTime(inquiryDate)
+		rewardTrx.SuccessedDate = nullTime(succeededDate)
+		rewardTrx.RejectedDate = nullTime(rejectedDate)
+		rewardTrx.TimeoutDate = nullTime(timeoutDate)
+		rewardTrx.CreatedAt = nullTime(createdAt)
+		rewardTrx.UpdatedAt = nullTime(updatedAt)
+		rewardTrxs = append(rewardTrxs, rewardTrx)
+	}
+
+	return rewardTrxs, nil
+}
+
+func nullTime(nt pq.NullTime) *time.Time {
+	if !nt.Valid {
+		return nil
 	}
-	return string(b)
+
+	return &nt.Time
 }
@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"database/sql"
+	"gade/srv-gade-point/dbtest"
+	"gade/srv-gade-point/models"
+	_pointHistoryRepository "gade/srv-gade-point/pointhistories/repository"
+	_quotaRepository "gade/srv-gade-point/quotas/repository"
+	"gade/srv-gade-point/rewardtrxs"
+	_rewardTrxRepository "gade/srv-gade-point/rewardtrxs/repository"
+	_voucherRepository "gade/srv-gade-point/vouchers/repository"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistoryCountsUnrewardedTransactions(t *testing.T) {
+	conn := dbtest.GetConn(t)
+	rewardTrxRepo := newRewardTrxRepository(conn)
+	CIF := dbtest.UniqueCIF()
+	amount := float64(100000)
+	trxDate := time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC)
+	_, rewardID := dbtest.CreateReward(t, conn, trxDate, trxDate.AddDate(0, 1, 0))
+
+	// four unrewarded transactions, one of them is retried on the same reference channel
+	for i := 0; i < 5; i++ {
+		plValidator := &models.PayloadValidator{
+			CIF:               CIF,
+			RefChannel:        "CH-" + CIF + "-" + strconv.Itoa(i%4),
+			TransactionAmount: &amount,
+			Validators:        &models.Validator{Product: "GD"},
+		}
+		trxHistory := models.NewTransactionHistory(plValidator, CIF+"-"+strconv.Itoa(i), trxDate.Add(time.Duration(i)))
+
+		created, err := rewardTrxRepo.CreateHistory(nil, trxHistory)
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+	}
+
+	// a rewarded transaction that is rejected by the core is not a part of the history
+	rejected := models.NewTransactionHistory(&models.PayloadValidator{CIF: CIF, TransactionAmount: &amount,
+		Validators: &models.Validator{Product: "GD"}}, CIF+"-rejected", trxDate)
+	query := `INSERT INTO reward_transactions (status, ref_id, reward_id, cif, transaction_date, request_data,
+		created_at) VALUES ($1, $2, $3, $4, $5, '{}', now())`
+	_, err := conn.Exec(query, models.RewardTrxRejected, rejected.RefID, rewardID, CIF, trxDate)
+
+	assert.NoError(t, err)
+
+	created, err := rewardTrxRepo.CreateHistory(nil, rejected)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	fifth := models.HistoryRule{Type: models.HistoryRuleNth, Product: "GD", Every: 5}
+	from, until := fifth.GetWindow(trxDate.Add(time.Hour), time.UTC)
+	summary, err := rewardTrxRepo.GetHistory(nil, models.HistoryQuery{CIF: CIF, Product: "GD", From: from,
+		Until: until})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), summary.Count)
+	assert.Equal(t, float64(400000), summary.Amount)
+	assert.True(t, fifth.IsMatch(summary, amount))
+}
+
+func TestCreateHistoryReservesRefID(t *testing.T) {
+	conn := dbtest.GetConn(t)
+	rewardTrxRepo := newRewardTrxRepository(conn)
+	CIF := dbtest.UniqueCIF()
+	amount := float64(100000)
+	trxDate := time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC)
+	plValidator := &models.PayloadValidator{CIF: CIF, TransactionAmount: &amount,
+		Validators: &models.Validator{Product: "GD"}}
+
+	created, err := rewardTrxRepo.CreateHistory(nil, models.NewTransactionHistory(plValidator, CIF, trxDate))
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// another inquiry that draws the same ref id has to generate a new one
+	created, err = rewardTrxRepo.CreateHistory(nil, models.NewTransactionHistory(plValidator, CIF, trxDate))
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	// a retried inquiry of the same reference channel keeps the ref id of its record
+	plValidator.RefChannel = "CH-" + CIF
+	created, err = rewardTrxRepo.CreateHistory(nil, models.NewTransactionHistory(plValidator, CIF+"-1", trxDate))
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	retried := models.NewTransactionHistory(plValidator, CIF+"-2", trxDate)
+	created, err = rewardTrxRepo.CreateHistory(nil, retried)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, CIF+"-1", retried.RefID)
+
+	// a failed inquiry is removed, so the channel is recorded again on its retry
+	assert.NoError(t, rewardTrxRepo.Release(nil, CIF+"-1"))
+
+	retried = models.NewTransactionHistory(plValidator, CIF+"-2", trxDate)
+	created, err = rewardTrxRepo.CreateHistory(nil, retried)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, CIF+"-2", retried.RefID)
+}
+
+func newRewardTrxRepository(conn *sql.DB) rewardtrxs.Repository {
+	pHistoryRepo := _pointHistoryRepository.NewPsqlPointHistoryRepository(conn)
+
+	return _rewardTrxRepository.NewPsqlRewardTrxRepository(conn, pHistoryRepo,
+		_voucherRepository.NewPsqlVoucherRepository(conn, pHistoryRepo), _quotaRepository.NewPsqlQuotaRepository(conn))
+}
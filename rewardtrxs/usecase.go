@@ -2,13 +2,22 @@ package rewardtrxs
 
 import (
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
 
 // UseCase represent the reward transactions usecases
 type UseCase interface {
-	Create(echo.Context, models.PayloadValidator, int64) error
+	GenerateRefID(echo.Context, *models.TransactionHistory) (string, error)
+	Create(echo.Context, models.PayloadValidator, string, []models.RewardResponse) ([]models.RewardTrx, error)
 	UpdateSuccess(echo.Context, map[string]interface{}) error
 	UpdateReject(echo.Context, map[string]interface{}) error
+	UpdateTimeout(time.Duration) (int64, error)
+	Reverse(echo.Context, *models.PayloadRewardReversal) (*models.PointReversal, error)
+	Release(echo.Context, string) error
+	CheckInquiry(echo.Context, *models.PayloadValidator) (*models.RewardsInquiry, error)
+	SaveInquiry(echo.Context, *models.PayloadValidator, models.RewardsInquiry) error
+	CancelInquiry(echo.Context, *models.PayloadValidator) error
+	GetHistoryProvider(echo.Context) models.HistoryProvider
 }
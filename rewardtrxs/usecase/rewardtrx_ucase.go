@@ -1,41 +1,101 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gade/srv-gade-point/membershiptiers"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/rewardtrxs"
+	"time"
 
 	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	letterBytes      = "1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	refIDLength      = 20
+	refIDMaxAttempts = 5
 )
 
 type rewardTrxUseCase struct {
 	rewardTrxRepo rewardtrxs.Repository
+	tierUC        membershiptiers.UseCase
 }
 
 // NewRewardtrxUseCase will create new an rewardtrxUseCase object representation of rewardtrxs.UseCase interface
-func NewRewardtrxUseCase(rwdTrxRepo rewardtrxs.Repository) rewardtrxs.UseCase {
+func NewRewardtrxUseCase(
+	rwdTrxRepo rewardtrxs.Repository,
+	tierUC membershiptiers.UseCase,
+) rewardtrxs.UseCase {
 	return &rewardTrxUseCase{
 		rewardTrxRepo: rwdTrxRepo,
+		tierUC:        tierUC,
 	}
 }
 
-func (rwdTrx *rewardTrxUseCase) Create(c echo.Context, payload models.PayloadValidator, rewardID int64) error {
+func (rwdTrx *rewardTrxUseCase) GenerateRefID(c echo.Context, trxHistory *models.TransactionHistory) (string, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	err := rwdTrx.rewardTrxRepo.Create(c, payload, rewardID)
+
+	// the ref id is reserved by the unique transaction history, it is regenerated whenever
+	// another inquiry has taken it. A retried inquiry of the same channel gets the ref id of its record
+	for i := 0; i < refIDMaxAttempts; i++ {
+		refID, err := randRefID(refIDLength)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return "", models.ErrGenerateRefID
+		}
+
+		trxHistory.RefID = refID
+		created, err := rwdTrx.rewardTrxRepo.CreateHistory(c, trxHistory)
+
+		if err != nil {
+			requestLogger.Debug(models.ErrCreateHistory)
+
+			return "", models.ErrCreateHistory
+		}
+
+		if created {
+			return trxHistory.RefID, nil
+		}
+	}
+
+	requestLogger.Debug(models.ErrGenerateRefID)
+
+	return "", models.ErrGenerateRefID
+}
+
+func (rwdTrx *rewardTrxUseCase) Create(c echo.Context, payload models.PayloadValidator, refID string,
+	rwdResponses []models.RewardResponse) ([]models.RewardTrx, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	rewardTrxs, err := rwdTrx.rewardTrxRepo.Create(c, payload, refID, rwdResponses)
 
 	if err != nil {
 		requestLogger.Debug(models.ErrRewardTrxFailed)
 
-		return models.ErrRewardTrxFailed
+		return nil, models.ErrRewardTrxFailed
 	}
 
-	return nil
+	return rewardTrxs, nil
 }
 
 func (rwdTrx *rewardTrxUseCase) UpdateSuccess(c echo.Context, payload map[string]interface{}) error {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	err := rwdTrx.rewardTrxRepo.UpdateSuccess(c, payload)
+	_, err := rwdTrx.getInquiredRewardTrxs(c, payload)
+
+	if err != nil {
+		return err
+	}
+
+	// the pending points and the reserved vouchers of the transaction are confirmed along with it
+	counter, err := rwdTrx.rewardTrxRepo.UpdateSuccess(c, payload)
 
 	if err != nil {
 		requestLogger.Debug(models.ErrRewardTrxUpdateFailed)
@@ -43,13 +103,30 @@ func (rwdTrx *rewardTrxUseCase) UpdateSuccess(c echo.Context, payload map[string
 		return models.ErrRewardTrxUpdateFailed
 	}
 
+	if counter == 0 {
+		requestLogger.Debug(models.ErrRewardTrxProcessed)
+
+		return models.ErrRewardTrxProcessed
+	}
+
+	// the credited points could move the customer to a higher tier, a failed evaluation is retried on the next one
+	if _, err = rwdTrx.tierUC.Evaluate(c, payload["cif"].(string)); err != nil {
+		requestLogger.Debug(err)
+	}
+
 	return nil
 }
 
 func (rwdTrx *rewardTrxUseCase) UpdateReject(c echo.Context, payload map[string]interface{}) error {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
-	err := rwdTrx.rewardTrxRepo.UpdateReject(c, payload)
+	_, err := rwdTrx.getInquiredRewardTrxs(c, payload)
+
+	if err != nil {
+		return err
+	}
+
+	counter, err := rwdTrx.rewardTrxRepo.UpdateReject(c, payload)
 
 	if err != nil {
 		requestLogger.Debug(models.ErrRewardTrxUpdateFailed)
@@ -57,5 +134,323 @@ func (rwdTrx *rewardTrxUseCase) UpdateReject(c echo.Context, payload map[string]
 		return models.ErrRewardTrxUpdateFailed
 	}
 
+	if counter == 0 {
+		requestLogger.Debug(models.ErrRewardTrxProcessed)
+
+		return models.ErrRewardTrxProcessed
+	}
+
 	return nil
 }
+
+func (rwdTrx *rewardTrxUseCase) UpdateTimeout(ttl time.Duration) (int64, error) {
+	refIDs, err := rwdTrx.rewardTrxRepo.UpdateTimeout(nil, time.Now().Add(-ttl))
+
+	if err != nil {
+		logrus.Debug("Update Reward Transaction Timeout: ", err)
+
+		return 0, models.ErrRewardTrxTimeout
+	}
+
+	return int64(len(refIDs)), nil
+}
+
+func (rwdTrx *rewardTrxUseCase) Reverse(c echo.Context, plReversal *models.PayloadRewardReversal) (*models.PointReversal, error) {
+	var plValidator models.PayloadValidator
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	rewardTrxs, err := rwdTrx.rewardTrxRepo.GetByRefCore(c, plReversal.RefCore, plReversal.CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, models.ErrReversalFailed
+	}
+
+	if len(rewardTrxs) == 0 {
+		requestLogger.Debug(models.ErrRefCoreNotFound)
+
+		return nil, models.ErrRefCoreNotFound
+	}
+
+	// every reward of the core transaction is reversed together, so the first one holds the reversed ratio
+	if err = json.Unmarshal([]byte(rewardTrxs[0].RequestData), &plValidator); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, models.ErrReversalFailed
+	}
+
+	ratio, err := models.GetReversalRatio(plValidator.TransactionAmount, plReversal.ReversedAmount,
+		rewardTrxs[0].ReversedRatio)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	reversal := &models.PointReversal{
+		RefReversal:    plReversal.RefReversal,
+		RefCore:        plReversal.RefCore,
+		CIF:            plReversal.CIF,
+		ReversedAmount: plReversal.ReversedAmount,
+		Ratio:          ratio,
+	}
+
+	for _, rewardTrx := range rewardTrxs {
+		rwdReversal, err := models.NewRewardReversal(rewardTrx, ratio)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, models.ErrReversalFailed
+		}
+
+		reversal.Rewards = append(reversal.Rewards, rwdReversal)
+	}
+
+	err = rwdTrx.rewardTrxRepo.Reverse(c, reversal)
+
+	switch err {
+	case nil:
+		return reversal, nil
+	case models.ErrReversalProcessed, models.ErrRewardTrxProcessed:
+		return nil, err
+	}
+
+	requestLogger.Debug(models.ErrReversalFailed)
+
+	return nil, models.ErrReversalFailed
+}
+
+func (rwdTrx *rewardTrxUseCase) CheckInquiry(c echo.Context, plValidator *models.PayloadValidator) (*models.RewardsInquiry, error) {
+	var rwdInquiry models.RewardsInquiry
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// inquiry without reference channel could not be identified as a retry
+	if plValidator.RefChannel == "" {
+		return nil, nil
+	}
+
+	requestHash, err := getRequestHash(plValidator)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, models.ErrInquiryLog
+	}
+
+	inquiryLog := &models.RewardInquiryLog{
+		RefChannel:  plValidator.RefChannel,
+		CIF:         plValidator.CIF,
+		RequestHash: requestHash,
+	}
+
+	created, err := rwdTrx.rewardTrxRepo.CreateInquiryLog(c, inquiryLog)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return nil, models.ErrInquiryLog
+	}
+
+	if created {
+		return nil, nil
+	}
+
+	inquiryLog, err = rwdTrx.rewardTrxRepo.GetInquiryLog(c, plValidator.RefChannel, plValidator.CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return nil, models.ErrInquiryLog
+	}
+
+	if inquiryLog.RequestHash != requestHash {
+		requestLogger.Debug(models.ErrInquiryConflict)
+
+		return nil, models.ErrInquiryConflict
+	}
+
+	if inquiryLog.ResponseData == nil {
+		requestLogger.Debug(models.ErrInquiryInProgress)
+
+		return nil, models.ErrInquiryInProgress
+	}
+
+	if err = json.Unmarshal([]byte(*inquiryLog.ResponseData), &rwdInquiry); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, models.ErrInquiryLog
+	}
+
+	return &rwdInquiry, nil
+}
+
+func (rwdTrx *rewardTrxUseCase) SaveInquiry(c echo.Context, plValidator *models.PayloadValidator, rwdInquiry models.RewardsInquiry) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if plValidator.RefChannel == "" {
+		return nil
+	}
+
+	inquiryLog, err := rwdTrx.rewardTrxRepo.GetInquiryLog(c, plValidator.RefChannel, plValidator.CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return models.ErrInquiryLog
+	}
+
+	responseData, err := json.Marshal(rwdInquiry)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return models.ErrInquiryLog
+	}
+
+	response := string(responseData)
+	inquiryLog.RefID = rwdInquiry.RefTrx
+	inquiryLog.ResponseData = &response
+
+	if err = rwdTrx.rewardTrxRepo.UpdateInquiryLog(c, inquiryLog); err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return models.ErrInquiryLog
+	}
+
+	return nil
+}
+
+func (rwdTrx *rewardTrxUseCase) CancelInquiry(c echo.Context, plValidator *models.PayloadValidator) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if plValidator.RefChannel == "" {
+		return nil
+	}
+
+	inquiryLog, err := rwdTrx.rewardTrxRepo.GetInquiryLog(c, plValidator.RefChannel, plValidator.CIF)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return models.ErrInquiryLog
+	}
+
+	// only the unfinished inquiry could be cancelled, so the channel is able to retry it
+	if inquiryLog.ResponseData != nil {
+		return nil
+	}
+
+	if err = rwdTrx.rewardTrxRepo.DeleteInquiryLog(c, inquiryLog.ID); err != nil {
+		requestLogger.Debug(models.ErrInquiryLog)
+
+		return models.ErrInquiryLog
+	}
+
+	return nil
+}
+
+func (rwdTrx *rewardTrxUseCase) getInquiredRewardTrxs(c echo.Context, payload map[string]interface{}) ([]models.RewardTrx, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	rewardTrxs, err := rwdTrx.rewardTrxRepo.GetByRefID(c, payload["refTrx"].(string), payload["cif"].(string))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, models.ErrInternalServerError
+	}
+
+	if len(rewardTrxs) == 0 {
+		requestLogger.Debug(models.ErrRefTrxNotFound)
+
+		return nil, models.ErrRefTrxNotFound
+	}
+
+	for _, rewardTrx := range rewardTrxs {
+		if *rewardTrx.Status != models.RewardTrxInquired {
+			requestLogger.Debug(models.ErrRewardTrxProcessed)
+
+			return nil, models.ErrRewardTrxProcessed
+		}
+	}
+
+	return rewardTrxs, nil
+}
+
+func (rwdTrx *rewardTrxUseCase) Release(c echo.Context, refID string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	// the inquired transactions of a failed inquiry are rejected along with their reservations
+	if err := rwdTrx.rewardTrxRepo.Release(c, refID); err != nil {
+		requestLogger.Debug(models.ErrReleaseRewardTrx)
+
+		return models.ErrReleaseRewardTrx
+	}
+
+	return nil
+}
+
+func randRefID(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = letterBytes[int(b[i])%len(letterBytes)]
+	}
+
+	return string(b), nil
+}
+
+func getRequestHash(plValidator *models.PayloadValidator) (string, error) {
+	requestData, err := json.Marshal(plValidator)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(requestData)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (rwdTrx *rewardTrxUseCase) GetHistoryProvider(c echo.Context) models.HistoryProvider {
+	return &historyProvider{
+		c:             c,
+		rewardTrxRepo: rwdTrx.rewardTrxRepo,
+		summaries:     map[models.HistoryQuery]models.HistorySummary{},
+	}
+}
+
+// historyProvider to get the customer history of a single inquiry, the same query
+// of many rewards is only read once
+type historyProvider struct {
+	c             echo.Context
+	rewardTrxRepo rewardtrxs.Repository
+	summaries     map[models.HistoryQuery]models.HistorySummary
+}
+
+func (hp *historyProvider) GetHistory(query models.HistoryQuery) (models.HistorySummary, error) {
+	if summary, ok := hp.summaries[query]; ok {
+		return summary, nil
+	}
+
+	summary, err := hp.rewardTrxRepo.GetHistory(hp.c, query)
+
+	if err != nil {
+		return summary, models.ErrGetHistory
+	}
+
+	hp.summaries[query] = summary
+
+	return summary, nil
+}
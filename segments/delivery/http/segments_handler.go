@@ -0,0 +1,269 @@
+package http
+
+import (
+	"errors"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/segments"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+var response models.Response
+
+// SegmentsHandler represent the httphandler for segments
+type SegmentsHandler struct {
+	SegmentUseCase segments.UseCase
+}
+
+// NewSegmentsHandler represent to register segments endpoint
+func NewSegmentsHandler(echoGroup models.EchoGroup, us segments.UseCase) {
+	handler := &SegmentsHandler{
+		SegmentUseCase: us,
+	}
+
+	// End Point For CMS
+	echoGroup.Admin.POST("/segments", handler.createSegment)
+	echoGroup.Admin.GET("/segments", handler.getSegments)
+	echoGroup.Admin.GET("/segments/:id", handler.getSegment)
+	echoGroup.Admin.POST("/segments/:id/members", handler.addMembers)
+	echoGroup.Admin.DELETE("/segments/:id/members", handler.removeMembers)
+	echoGroup.Admin.POST("/segments/:id/members/upload", handler.uploadMembers)
+}
+
+func (sgmt *SegmentsHandler) createSegment(echTx echo.Context) error {
+	var segment models.Segment
+	response = models.Response{}
+	err := echTx.Bind(&segment)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(segment); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, segment)
+	requestLogger.Info("Start to create a segment.")
+	err = sgmt.SegmentUseCase.Create(echTx, &segment)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageSaveSuccess
+	response.Data = segment
+	requestLogger.Info("End of create a segment.")
+
+	return echTx.JSON(http.StatusCreated, response)
+}
+
+func (sgmt *SegmentsHandler) getSegments(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get segments.")
+	data, err := sgmt.SegmentUseCase.GetSegments(echTx, echTx.QueryParam("name"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	if len(data) > 0 {
+		response.Data = data
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	requestLogger.Info("End of get segments.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (sgmt *SegmentsHandler) getSegment(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to get detail segment.")
+	data, err := sgmt.SegmentUseCase.GetSegment(echTx, echTx.Param("id"))
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageDataSuccess
+	response.Data = data
+	requestLogger.Info("End of get detail segment.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (sgmt *SegmentsHandler) addMembers(echTx echo.Context) error {
+	return sgmt.modifyMembers(echTx, "add", sgmt.SegmentUseCase.AddMembers)
+}
+
+func (sgmt *SegmentsHandler) removeMembers(echTx echo.Context) error {
+	return sgmt.modifyMembers(echTx, "remove", sgmt.SegmentUseCase.RemoveMembers)
+}
+
+func (sgmt *SegmentsHandler) modifyMembers(echTx echo.Context, action string,
+	modify func(echo.Context, string, []string) (*models.SegmentMemberResult, error)) error {
+	var plMember models.PayloadSegmentMember
+	response = models.Response{}
+	err := echTx.Bind(&plMember)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = models.MessageUnprocessableEntity
+
+		return echTx.JSON(http.StatusUnprocessableEntity, response)
+	}
+
+	if err = echTx.Validate(plMember); err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to " + action + " segment members.")
+	data, err := modify(echTx, echTx.Param("id"), plMember.CIFs)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUpdateSuccess
+	response.Data = data
+	requestLogger.Info("End of " + action + " segment members.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+func (sgmt *SegmentsHandler) uploadMembers(echTx echo.Context) error {
+	response = models.Response{}
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(echTx, nil)
+	requestLogger.Info("Start to upload segment members.")
+	reader, format, err := getMembersReader(echTx.Request())
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(http.StatusBadRequest, response)
+	}
+
+	data, err := sgmt.SegmentUseCase.UploadMembers(echTx, echTx.Param("id"), reader, format)
+
+	if err != nil {
+		response.Status = models.StatusError
+		response.Message = err.Error()
+
+		return echTx.JSON(getStatusCode(err), response)
+	}
+
+	response.Status = models.StatusSuccess
+	response.Message = models.MessageUploadSuccess
+	response.Data = data
+	requestLogger.Info("End of upload segment members.")
+
+	return echTx.JSON(http.StatusOK, response)
+}
+
+// getMembersReader to get the members stream without buffering the whole upload,
+// it could be a multipart file field or a raw csv/json request body
+func getMembersReader(req *http.Request) (io.Reader, string, error) {
+	contentType := req.Header.Get(echo.HeaderContentType)
+
+	if !strings.HasPrefix(contentType, echo.MIMEMultipartForm) {
+		return req.Body, getMembersFormat(contentType), nil
+	}
+
+	multipartReader, err := req.MultipartReader()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := multipartReader.NextPart()
+
+		if err == io.EOF {
+			return nil, "", errors.New("Segment members file is not available")
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		format := strings.TrimPrefix(strings.ToLower(filepath.Ext(part.FileName())), ".")
+
+		if format == "" {
+			format = getMembersFormat(part.Header.Get(echo.HeaderContentType))
+		}
+
+		return part, format, nil
+	}
+}
+
+func getMembersFormat(contentType string) string {
+	if strings.Contains(contentType, "json") {
+		return models.SegmentFormatJSON
+	}
+
+	return models.SegmentFormatCSV
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	if strings.Contains(err.Error(), "400") {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case models.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case models.ErrNotFound, models.ErrNoSegment:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusOK
+	}
+}
@@ -0,0 +1,17 @@
+package segments
+
+import (
+	"gade/srv-gade-point/models"
+
+	"github.com/labstack/echo"
+)
+
+// Repository represent the segments repository contract
+type Repository interface {
+	Create(echo.Context, *models.Segment) error
+	GetSegments(echo.Context, string) ([]models.Segment, error)
+	GetSegment(echo.Context, int64) (*models.Segment, error)
+	AddMembers(echo.Context, int64, []string) (int64, error)
+	RemoveMembers(echo.Context, int64, []string) (int64, error)
+	IsMember(echo.Context, []int64, string) (bool, error)
+}
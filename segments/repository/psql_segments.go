@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/segments"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/lib/pq"
+)
+
+type psqlSegmentRepository struct {
+	Conn *sql.DB
+}
+
+// NewPsqlSegmentRepository will create an object that represent the segments.Repository interface
+func NewPsqlSegmentRepository(Conn *sql.DB) segments.Repository {
+	return &psqlSegmentRepository{Conn}
+}
+
+func (sgmtRepo *psqlSegmentRepository) Create(c echo.Context, segment *models.Segment) error {
+	var lastID int64
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `INSERT INTO segments (name, description, created_at) VALUES ($1, $2, $3) RETURNING id`
+	stmt, err := sgmtRepo.Conn.Prepare(query)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	err = stmt.QueryRow(segment.Name, segment.Description, &now).Scan(&lastID)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
+	segment.ID = lastID
+	segment.CreatedAt = &now
+
+	return nil
+}
+
+func (sgmtRepo *psqlSegmentRepository) GetSegments(c echo.Context, name string) ([]models.Segment, error) {
+	var result []models.Segment
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT s.id, s.name, coalesce(s.description, ''), s.created_at, s.updated_at,
+		(SELECT COUNT(sm.cif) FROM segment_members sm WHERE sm.segment_id = s.id)
+		FROM segments s WHERE s.name LIKE $1 ORDER BY s.created_at DESC`
+	rows, err := sgmtRepo.Conn.Query(query, "%"+name+"%")
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		segment, err := scanSegment(rows)
+
+		if err != nil {
+			requestLogger.Debug(err)
+
+			return nil, err
+		}
+
+		result = append(result, *segment)
+	}
+
+	return result, nil
+}
+
+func (sgmtRepo *psqlSegmentRepository) GetSegment(c echo.Context, id int64) (*models.Segment, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT s.id, s.name, coalesce(s.description, ''), s.created_at, s.updated_at,
+		(SELECT COUNT(sm.cif) FROM segment_members sm WHERE sm.segment_id = s.id)
+		FROM segments s WHERE s.id = $1`
+	segment, err := scanSegment(sgmtRepo.Conn.QueryRow(query, id))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return segment, nil
+}
+
+func (sgmtRepo *psqlSegmentRepository) AddMembers(c echo.Context, id int64, cifs []string) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	now := time.Now()
+	query := `INSERT INTO segment_members (segment_id, cif, created_at)
+		SELECT $1, unnest($2::VARCHAR[]), $3 ON CONFLICT DO NOTHING`
+	result, err := sgmtRepo.Conn.Exec(query, id, pq.Array(cifs), &now)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return sgmtRepo.touch(c, id, result)
+}
+
+func (sgmtRepo *psqlSegmentRepository) RemoveMembers(c echo.Context, id int64, cifs []string) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `DELETE FROM segment_members WHERE segment_id = $1 AND cif = ANY($2::VARCHAR[])`
+	result, err := sgmtRepo.Conn.Exec(query, id, pq.Array(cifs))
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return sgmtRepo.touch(c, id, result)
+}
+
+func (sgmtRepo *psqlSegmentRepository) IsMember(c echo.Context, segmentIDs []int64, cif string) (bool, error) {
+	var isMember bool
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	query := `SELECT EXISTS (SELECT 1 FROM segment_members WHERE cif = $1 AND segment_id = ANY($2::INTEGER[]))`
+	err := sgmtRepo.Conn.QueryRow(query, cif, pq.Array(segmentIDs)).Scan(&isMember)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return false, err
+	}
+
+	return isMember, nil
+}
+
+func (sgmtRepo *psqlSegmentRepository) touch(c echo.Context, id int64, result sql.Result) (int64, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	if affected == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	_, err = sgmtRepo.Conn.Exec(`UPDATE segments SET updated_at = $1 WHERE id = $2`, &now, id)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+type segmentScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSegment(row segmentScanner) (*models.Segment, error) {
+	var segment models.Segment
+	var createDate, updateDate pq.NullTime
+	var totalMember int64
+
+	err := row.Scan(&segment.ID, &segment.Name, &segment.Description, &createDate, &updateDate, &totalMember)
+
+	if err != nil {
+		return nil, err
+	}
+
+	segment.CreatedAt = &createDate.Time
+	segment.UpdatedAt = &updateDate.Time
+	segment.TotalMember = &totalMember
+
+	return &segment, nil
+}
@@ -0,0 +1,19 @@
+package segments
+
+import (
+	"gade/srv-gade-point/models"
+	"io"
+
+	"github.com/labstack/echo"
+)
+
+// UseCase represent the segments usecases
+type UseCase interface {
+	Create(echo.Context, *models.Segment) error
+	GetSegments(echo.Context, string) ([]models.Segment, error)
+	GetSegment(echo.Context, string) (*models.Segment, error)
+	AddMembers(echo.Context, string, []string) (*models.SegmentMemberResult, error)
+	RemoveMembers(echo.Context, string, []string) (*models.SegmentMemberResult, error)
+	UploadMembers(echo.Context, string, io.Reader, string) (*models.SegmentMemberResult, error)
+	CheckSegments(echo.Context, *models.Validator, string) error
+}
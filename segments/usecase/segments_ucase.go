@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/segments"
+	"io"
+	"strconv"
+
+	"github.com/labstack/echo"
+)
+
+type segmentUseCase struct {
+	segmentRepo segments.Repository
+}
+
+// NewSegmentUseCase will create new an segmentUseCase object representation of segments.UseCase interface
+func NewSegmentUseCase(sgmtRepo segments.Repository) segments.UseCase {
+	return &segmentUseCase{
+		segmentRepo: sgmtRepo,
+	}
+}
+
+func (sgmt *segmentUseCase) Create(c echo.Context, segment *models.Segment) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if err := sgmt.segmentRepo.Create(c, segment); err != nil {
+		requestLogger.Debug(models.ErrSegmentFailed)
+
+		return models.ErrSegmentFailed
+	}
+
+	return nil
+}
+
+func (sgmt *segmentUseCase) GetSegments(c echo.Context, name string) ([]models.Segment, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	listSegment, err := sgmt.segmentRepo.GetSegments(c, name)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetSegment)
+
+		return nil, models.ErrGetSegment
+	}
+
+	return listSegment, nil
+}
+
+func (sgmt *segmentUseCase) GetSegment(c echo.Context, id string) (*models.Segment, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	segmentID, err := strconv.ParseInt(id, 10, 64)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, errors.New("Something went wrong with input ID")
+	}
+
+	segment, err := sgmt.segmentRepo.GetSegment(c, segmentID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrNoSegment)
+
+		return nil, models.ErrNoSegment
+	}
+
+	if err != nil {
+		requestLogger.Debug(models.ErrGetSegment)
+
+		return nil, models.ErrGetSegment
+	}
+
+	return segment, nil
+}
+
+func (sgmt *segmentUseCase) AddMembers(c echo.Context, id string, cifs []string) (*models.SegmentMemberResult, error) {
+	segment, err := sgmt.GetSegment(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SegmentMemberResult{}
+	result.Total, err = models.ReadSegmentMembersFromList(cifs, models.SegmentBatchSize, func(batch []string) error {
+		return sgmt.storeBatch(c, segment.ID, batch, result, sgmt.segmentRepo.AddMembers)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sgmt *segmentUseCase) RemoveMembers(c echo.Context, id string, cifs []string) (*models.SegmentMemberResult, error) {
+	segment, err := sgmt.GetSegment(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SegmentMemberResult{}
+	result.Total, err = models.ReadSegmentMembersFromList(cifs, models.SegmentBatchSize, func(batch []string) error {
+		return sgmt.storeBatch(c, segment.ID, batch, result, sgmt.segmentRepo.RemoveMembers)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sgmt *segmentUseCase) UploadMembers(c echo.Context, id string, reader io.Reader, format string) (*models.SegmentMemberResult, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	segment, err := sgmt.GetSegment(c, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// members are stored batch by batch while the upload is being read
+	result := &models.SegmentMemberResult{}
+	result.Total, err = models.ReadSegmentMembers(reader, format, models.SegmentBatchSize, func(batch []string) error {
+		return sgmt.storeBatch(c, segment.ID, batch, result, sgmt.segmentRepo.AddMembers)
+	})
+
+	if err == models.ErrSegmentMembers {
+		return nil, err
+	}
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, errors.New("Segment members file is not valid: " + err.Error())
+	}
+
+	return result, nil
+}
+
+func (sgmt *segmentUseCase) CheckSegments(c echo.Context, validator *models.Validator, cif string) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+
+	if validator == nil {
+		return nil
+	}
+
+	if len(validator.IncludeSegments) > 0 {
+		isMember, err := sgmt.segmentRepo.IsMember(c, validator.IncludeSegments, cif)
+
+		if err != nil {
+			requestLogger.Debug(models.ErrGetSegment)
+
+			return models.ErrGetSegment
+		}
+
+		if !isMember {
+			return models.ErrSegmentNotIncluded
+		}
+	}
+
+	if len(validator.ExcludeSegments) > 0 {
+		isMember, err := sgmt.segmentRepo.IsMember(c, validator.ExcludeSegments, cif)
+
+		if err != nil {
+			requestLogger.Debug(models.ErrGetSegment)
+
+			return models.ErrGetSegment
+		}
+
+		if isMember {
+			return models.ErrSegmentExcluded
+		}
+	}
+
+	return nil
+}
+
+func (sgmt *segmentUseCase) storeBatch(c echo.Context, id int64, batch []string, result *models.SegmentMemberResult,
+	store func(echo.Context, int64, []string) (int64, error)) error {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	affected, err := store(c, id, batch)
+
+	if err != nil {
+		requestLogger.Debug(models.ErrSegmentMembers)
+
+		return models.ErrSegmentMembers
+	}
+
+	result.Affected += affected
+
+	return nil
+}
@@ -30,3 +30,14 @@ func AddMetric(job string) error {
 
 	return err
 }
+
+// AddMetricCounter is to add metric with a specific counter to db
+func AddMetricCounter(job string, counter int64) error {
+	err := some.metricUsecase.AddMetricCounter(job, counter)
+
+	if err != nil {
+		return models.ErrCreateMetric
+	}
+
+	return err
+}
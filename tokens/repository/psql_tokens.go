@@ -124,8 +124,8 @@ func stringToDuration(str string) time.Duration {
 
 func createJWTToken(accountToken *models.AccountToken, now time.Time, tokenExp time.Time) (string, error) {
 	claims := models.Token{
-		accountToken.Username,
-		jwt.StandardClaims{
+		Name: accountToken.Username,
+		StandardClaims: jwt.StandardClaims{
 			Id:        accountToken.Username,
 			ExpiresAt: tokenExp.Unix(),
 		},
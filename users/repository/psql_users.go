@@ -23,7 +23,7 @@ func (m *psqlUserRepository) Create(ctx context.Context, usr *models.User) error
 	var lastID int64
 	now := time.Now()
 	defStatus := int8(1)
-	defRole := int8(0)
+	defRole := models.UserRoleAdmin
 
 	usr.CreatedAt = &now
 	usr.Status = &defStatus
@@ -4,8 +4,11 @@ import (
 	"context"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/users"
+	"os"
+	"strconv"
 	"time"
 
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/labstack/gommon/log"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -56,6 +59,13 @@ func (usr *userUseCase) ValidateUser(ctx context.Context, userPayload models.Use
 		return nil, err
 	}
 
+	// the logged in user acts on the admin endpoints by its own token
+	if err = createUserToken(&user, time.Now()); err != nil {
+		log.Error(err)
+
+		return nil, err
+	}
+
 	// rearrange data
 	user.ID = 0
 	user.Password = ""
@@ -74,3 +84,28 @@ func verifyPassword(user *models.User, password string) error {
 
 	return nil
 }
+
+func createUserToken(user *models.User, now time.Time) error {
+	hours, _ := strconv.Atoi(os.Getenv(`JWT_TOKEN_EXP`))
+	tokenExp := now.Add(time.Duration(hours) * time.Hour)
+	claims := models.Token{
+		Name:   user.Username,
+		UserID: user.ID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        user.Username,
+			ExpiresAt: tokenExp.Unix(),
+		},
+	}
+
+	rawToken := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
+	token, err := rawToken.SignedString([]byte(os.Getenv(`JWT_SECRET`)))
+
+	if err != nil {
+		return err
+	}
+
+	user.Token = token
+	user.ExpireAt = &tokenExp
+
+	return nil
+}
@@ -348,13 +348,14 @@ func (vchr *VouchersHandler) GetVoucherAdmin(c echo.Context) error {
 	return c.JSON(getStatusCode(err), response)
 }
 
-// GetVouchers Get all voucher by param name, status, start date and end date
+// GetVouchers Get all voucher by param name, cif, start date and end date
 func (vchr *VouchersHandler) GetVouchers(c echo.Context) error {
 	// metric monitoring
 	go services.AddMetric("get_all_vouchers")
 
 	response = models.Response{}
 	name := c.QueryParam("name")
+	cif := c.QueryParam("cif")
 	startDate := c.QueryParam("startDate")
 	endDate := c.QueryParam("endDate")
 	pageStr := c.QueryParam("page")
@@ -372,6 +373,7 @@ func (vchr *VouchersHandler) GetVouchers(c echo.Context) error {
 	// prepare payload for logger
 	payload := map[string]interface{}{
 		"name":      name,
+		"cif":       cif,
 		"page":      pageStr,
 		"limit":     limitStr,
 		"startDate": startDate,
@@ -664,6 +666,7 @@ func (vchr *VouchersHandler) BadaiEmasGift(c echo.Context) error {
 
 		response.Status = models.StatusError
 		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
 		return c.JSON(getStatusCode(err), response)
 	}
 
@@ -709,6 +712,7 @@ func (vchr *VouchersHandler) VoucherValidate(c echo.Context) error {
 
 		response.Status = models.StatusError
 		response.Message = err.Error()
+		response.Errors = models.GetValidationErrors(err)
 		return c.JSON(getStatusCode(err), response)
 	}
 
@@ -1,7 +1,9 @@
 package vouchers
 
 import (
+	"database/sql"
 	"gade/srv-gade-point/models"
+	"time"
 
 	"github.com/labstack/echo"
 )
@@ -15,7 +17,10 @@ type Repository interface {
 	GetVoucherAdmin(echo.Context, string) (*models.Voucher, error)
 	GetVouchers(echo.Context, map[string]interface{}) ([]*models.Voucher, error)
 	GetVoucher(echo.Context, string) (*models.Voucher, error)
-	UpdatePromoCodeBought(echo.Context, string, string) (*models.VoucherCode, error)
+	UpdatePromoCodeBought(echo.Context, string, string, *models.PointHistory) (*models.VoucherCode, error)
+	UpdatePromoCodeReserved(echo.Context, string, string, string) (*models.VoucherCode, error)
+	ConfirmPromoCodes(*sql.Tx, string, time.Time) error
+	ReleasePromoCodes(*sql.Tx, string, time.Time) error
 	GetVouchersUser(echo.Context, map[string]interface{}) ([]models.VoucherCode, error)
 	CountVouchers(echo.Context, map[string]interface{}, bool) (int, error)
 	DeleteVoucher(echo.Context, int64) error
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"gade/srv-gade-point/models"
+	"gade/srv-gade-point/pointhistories"
 	"gade/srv-gade-point/vouchers"
 	"strings"
 	"time"
@@ -25,12 +26,13 @@ var (
 )
 
 type psqlVoucherRepository struct {
-	Conn *sql.DB
+	Conn         *sql.DB
+	pHistoryRepo pointhistories.Repository
 }
 
 // NewPsqlVoucherRepository will create an object that represent the vouchers. Repository interface
-func NewPsqlVoucherRepository(Conn *sql.DB) vouchers.Repository {
-	return &psqlVoucherRepository{Conn}
+func NewPsqlVoucherRepository(Conn *sql.DB, pHistoryRepo pointhistories.Repository) vouchers.Repository {
+	return &psqlVoucherRepository{Conn, pHistoryRepo}
 }
 
 func (m *psqlVoucherRepository) CreateVoucher(c echo.Context, voucher *models.Voucher) error {
@@ -317,8 +319,9 @@ func (m *psqlVoucherRepository) GetVouchers(c echo.Context, payload map[string]i
 		where += " AND d.end_date::timestamp::date <= '" + payload["endDate"].(string) + "'"
 	}
 
-	query += where + " ORDER BY c.created_at DESC " + paging
-	rows, err := m.Conn.Query(query)
+	segmentFilter, args := getSegmentFilter("c.", payload)
+	query += where + segmentFilter + " ORDER BY c.created_at DESC " + paging
+	rows, err := m.Conn.Query(query, args...)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -411,9 +414,10 @@ func (m *psqlVoucherRepository) UpdateStatusBasedOnStartDate() error {
 func (m *psqlVoucherRepository) GetVoucher(c echo.Context, voucherID string) (*models.Voucher, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
+	var validator string
 	result := new(models.Voucher)
-	query := `SELECT c.id, c.name, c.description, c.start_date, c.end_date, c.point, coalesce(c.day_purchase_limit, 0) as day_purchase_limit, c.image_url, c.stock, coalesce(d.available, 0), c.terms_and_conditions, c.how_to_use, c.limit_per_user
-	FROM vouchers c LEFT JOIN(SELECT b.id, coalesce(count(a.id), 0) as available FROM voucher_codes a LEFT JOIN vouchers b ON b.id=a.voucher_id 
+	query := `SELECT c.id, c.name, c.description, c.start_date, c.end_date, c.point, coalesce(c.day_purchase_limit, 0) as day_purchase_limit, c.image_url, c.stock, coalesce(d.available, 0), c.terms_and_conditions, c.how_to_use, c.limit_per_user,
+	c.validators FROM vouchers c LEFT JOIN(SELECT b.id, coalesce(count(a.id), 0) as available FROM voucher_codes a LEFT JOIN vouchers b ON b.id=a.voucher_id 
 	WHERE a.status = 0 GROUP BY b.id) d ON d.id = c.id WHERE c.id = $1`
 
 	err := m.Conn.QueryRow(query, voucherID).Scan(
@@ -430,6 +434,7 @@ func (m *psqlVoucherRepository) GetVoucher(c echo.Context, voucherID string) (*m
 		&result.TermsAndConditions,
 		&result.HowToUse,
 		&result.LimitPerUser,
+		&validator,
 	)
 
 	if err != nil {
@@ -438,6 +443,14 @@ func (m *psqlVoucherRepository) GetVoucher(c echo.Context, voucherID string) (*m
 		return nil, err
 	}
 
+	err = json.Unmarshal([]byte(validator), &result.Validators)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -528,8 +541,9 @@ func (m *psqlVoucherRepository) CountVouchers(c echo.Context, payload map[string
 		where += " AND end_date::date >= now()"
 	}
 
-	query += where
-	err := m.Conn.QueryRow(query).Scan(&total)
+	segmentFilter, args := getSegmentFilter("", payload)
+	query += where + segmentFilter
+	err := m.Conn.QueryRow(query, args...).Scan(&total)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -596,14 +610,15 @@ func (m *psqlVoucherRepository) CountPromoCode(c echo.Context, payload map[strin
 	return total, nil
 }
 
-func (m *psqlVoucherRepository) UpdatePromoCodeBought(c echo.Context, voucherID string, userID string) (*models.VoucherCode, error) {
+// UpdatePromoCodeBought to give a promo code to the user along with the point history that pays for it
+// within one transaction, so a code is never bought when the points are not debited
+func (m *psqlVoucherRepository) UpdatePromoCodeBought(c echo.Context, voucherID string, userID string,
+	pointHistory *models.PointHistory) (*models.VoucherCode, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 	result := new(models.VoucherCode)
 	now := time.Now()
-
-	querySelect := `SELECT id FROM voucher_codes WHERE status = 0 AND voucher_id = $1 ORDER BY promo_code ASC LIMIT 1`
-	err := m.Conn.QueryRow(querySelect, voucherID).Scan(&result.ID)
+	tx, err := m.Conn.Begin()
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -611,16 +626,50 @@ func (m *psqlVoucherRepository) UpdatePromoCodeBought(c echo.Context, voucherID
 		return nil, err
 	}
 
-	queryUpdate := `UPDATE voucher_codes SET status = 1, user_id = $1, bought_date = $2, updated_at = $3 WHERE id = $4 RETURNING promo_code, bought_date`
-	stmt, err := m.Conn.Prepare(queryUpdate)
+	// skip the codes that are being locked by another purchase, so no code is given twice
+	query := `UPDATE voucher_codes SET status = 1, user_id = $1, bought_date = $2, updated_at = $2
+		WHERE id = (SELECT id FROM voucher_codes WHERE status = 0 AND voucher_id = $3
+		ORDER BY promo_code ASC LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, promo_code, bought_date`
+	err = tx.QueryRow(query, userID, &now, voucherID).Scan(&result.ID, &result.PromoCode, &result.BoughtDate)
 
 	if err != nil {
 		requestLogger.Debug(err)
+		_ = tx.Rollback()
+
+		return nil, err
+	}
+
+	pointHistory.VoucherCode = result
+
+	if err = m.pHistoryRepo.CreatePointHistory(tx, pointHistory, now); err != nil {
+		requestLogger.Debug(err)
+		_ = tx.Rollback()
 
 		return nil, err
 	}
 
-	err = stmt.QueryRow(userID, &now, &now, &result.ID).Scan(&result.PromoCode, &result.BoughtDate)
+	if err = tx.Commit(); err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *psqlVoucherRepository) UpdatePromoCodeReserved(c echo.Context, voucherID string, userID string, refID string) (*models.VoucherCode, error) {
+	logger := models.RequestLogger{}
+	requestLogger := logger.GetRequestLogger(c, nil)
+	result := new(models.VoucherCode)
+	now := time.Now()
+
+	// skip the codes that are being locked by another inquiry, so no code is given twice
+	query := `UPDATE voucher_codes SET status = $1, user_id = $2, ref_id = $3, updated_at = $4
+		WHERE id = (SELECT id FROM voucher_codes WHERE status = $5 AND voucher_id = $6
+		ORDER BY promo_code ASC LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, promo_code, status`
+	err := m.Conn.QueryRow(
+		query, &models.VoucherCodeStatusReserved, userID, refID, &now, &models.VoucherCodeStatusAvailable, voucherID,
+	).Scan(&result.ID, &result.PromoCode, &result.Status)
 
 	if err != nil {
 		requestLogger.Debug(err)
@@ -631,6 +680,24 @@ func (m *psqlVoucherRepository) UpdatePromoCodeBought(c echo.Context, voucherID
 	return result, nil
 }
 
+// ConfirmPromoCodes to make the reserved promo codes of a succeeded reward transaction bought within the transaction
+func (m *psqlVoucherRepository) ConfirmPromoCodes(tx *sql.Tx, refID string, now time.Time) error {
+	query := `UPDATE voucher_codes SET status = $1, bought_date = $2, updated_at = $3 WHERE ref_id = $4 AND status = $5`
+	_, err := tx.Exec(query, &models.VoucherCodeStatusBought, &now, &now, refID, &models.VoucherCodeStatusReserved)
+
+	return err
+}
+
+// ReleasePromoCodes to make the reserved or bought promo codes of a reward transaction available again
+func (m *psqlVoucherRepository) ReleasePromoCodes(tx *sql.Tx, refID string, now time.Time) error {
+	query := `UPDATE voucher_codes SET status = $1, user_id = NULL, bought_date = NULL, ref_id = NULL, updated_at = $2
+		WHERE ref_id = $3 AND status IN ($4, $5)`
+	_, err := tx.Exec(query, &models.VoucherCodeStatusAvailable, &now, refID, &models.VoucherCodeStatusReserved,
+		&models.VoucherCodeStatusBought)
+
+	return err
+}
+
 func (m *psqlVoucherRepository) UpdatePromoCodeRedeemed(c echo.Context, voucherID string, userID string, code string) (*models.VoucherCode, error) {
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
@@ -810,3 +877,25 @@ func arrSpliter(arrSource []*models.VoucherCode) [][]*models.VoucherCode {
 
 	return divided
 }
+
+// getSegmentFilter to filter the vouchers by the customer segments of the cif in the payload,
+// a voucher without included segments is available for everyone and an anonymous customer only sees those
+func getSegmentFilter(prefix string, payload map[string]interface{}) (string, []interface{}) {
+	cif, ok := payload["cif"].(string)
+
+	if !ok {
+		return "", nil
+	}
+
+	if cif == "" {
+		return ` AND coalesce(jsonb_array_length(` + prefix + `validators->'includeSegments'), 0) = 0`, nil
+	}
+
+	filter := ` AND (coalesce(jsonb_array_length(` + prefix + `validators->'includeSegments'), 0) = 0
+	OR EXISTS (SELECT 1 FROM segment_members sm WHERE sm.cif = $1
+	AND sm.segment_id::text IN (SELECT jsonb_array_elements_text(` + prefix + `validators->'includeSegments'))))
+	AND NOT EXISTS (SELECT 1 FROM segment_members sm WHERE sm.cif = $1
+	AND sm.segment_id::text IN (SELECT jsonb_array_elements_text(coalesce(` + prefix + `validators->'excludeSegments', '[]'))))`
+
+	return filter, []interface{}{cif}
+}
@@ -2,11 +2,14 @@ package usecase
 
 import (
 	"bytes"
+	"database/sql"
 	"errors"
 	"fmt"
 	"gade/srv-gade-point/campaigns"
+	"gade/srv-gade-point/membershiptiers"
 	"gade/srv-gade-point/models"
 	"gade/srv-gade-point/pointhistories"
+	"gade/srv-gade-point/segments"
 	"gade/srv-gade-point/vouchers"
 	"io"
 	"io/ioutil"
@@ -39,14 +42,19 @@ type voucherUseCase struct {
 	voucherRepo    vouchers.Repository
 	campaignRepo   campaigns.Repository
 	pHistoriesRepo pointhistories.Repository
+	segmentUC      segments.UseCase
+	tierUC         membershiptiers.UseCase
 }
 
 // NewVoucherUseCase will create new an voucherUseCase object representation of vouchers.UseCase interface
-func NewVoucherUseCase(vchrRepo vouchers.Repository, campgnRepo campaigns.Repository, pHistoriesRepo pointhistories.Repository) vouchers.UseCase {
+func NewVoucherUseCase(vchrRepo vouchers.Repository, campgnRepo campaigns.Repository, pHistoriesRepo pointhistories.Repository,
+	segmentUC segments.UseCase, tierUC membershiptiers.UseCase) vouchers.UseCase {
 	return &voucherUseCase{
 		voucherRepo:    vchrRepo,
 		campaignRepo:   campgnRepo,
 		pHistoriesRepo: pHistoriesRepo,
+		segmentUC:      segmentUC,
+		tierUC:         tierUC,
 	}
 }
 
@@ -56,6 +64,13 @@ func (vchr *voucherUseCase) CreateVoucher(c echo.Context, voucher *models.Vouche
 	logger := models.RequestLogger{}
 	requestLogger := logger.GetRequestLogger(c, nil)
 
+	// make sure the voucher rules and formula is well formed before storing anything
+	if err := voucher.Validators.Check(); err != nil {
+		requestLogger.Debug(err)
+
+		return err
+	}
+
 	err := vchr.voucherRepo.CreateVoucher(c, voucher)
 
 	if err != nil {
@@ -357,6 +372,24 @@ func (vchr *voucherUseCase) VoucherBuy(ech echo.Context, payload *models.Payload
 		return nil, models.ErrVoucherExpired
 	}
 
+	// check the customer segments of the voucher
+	err = vchr.segmentUC.CheckSegments(ech, voucherDetail.Validators, payload.CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
+	// check the membership tiers of a tier-only voucher
+	err = vchr.tierUC.CheckMembershipTiers(ech, voucherDetail.Validators, payload.CIF)
+
+	if err != nil {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
+
 	// check voucher limit per user
 	payloadPC := map[string]interface{}{
 		"CIF":       payload.CIF,
@@ -415,14 +448,6 @@ func (vchr *voucherUseCase) VoucherBuy(ech echo.Context, payload *models.Payload
 		return nil, err
 	}
 
-	voucherCode, err := vchr.voucherRepo.UpdatePromoCodeBought(ech, payload.VoucherID, payload.CIF)
-
-	if err != nil {
-		requestLogger.Debug(models.ErrUpdatePromoCodes)
-
-		return nil, models.ErrUpdatePromoCodes
-	}
-
 	// Parse interface to float
 	parseFloat, err := getFloat(voucherDetail.Point)
 
@@ -440,21 +465,28 @@ func (vchr *voucherUseCase) VoucherBuy(ech echo.Context, payload *models.Payload
 
 	pointAmount := math.Floor(parseFloat)
 
-	campaignTrx := &models.CampaignTrx{
+	pointHistory := &models.PointHistory{
 		CIF:             payload.CIF,
 		PointAmount:     &pointAmount,
 		TransactionType: models.TransactionPointTypeKredit,
 		TransactionDate: &now,
-		VoucherCode:     voucherCode,
-		CreatedAt:       &now,
+		Status:          &models.PointHistoryStatusSuccess,
 	}
 
-	err = vchr.campaignRepo.SavePoint(ech, campaignTrx)
+	// the code is bought along with the point history, the ledger rejects both when a concurrent purchase
+	// has spent the points in the meantime
+	voucherCode, err := vchr.voucherRepo.UpdatePromoCodeBought(ech, payload.VoucherID, payload.CIF, pointHistory)
+
+	if err == models.ErrPointDeficit {
+		requestLogger.Debug(err)
+
+		return nil, err
+	}
 
 	if err != nil {
-		requestLogger.Debug(models.ErrStoreCampaignTrx)
+		requestLogger.Debug(models.ErrUpdatePromoCodes)
 
-		return nil, models.ErrStoreCampaignTrx
+		return nil, models.ErrUpdatePromoCodes
 	}
 
 	voucherCode.Voucher = &models.Voucher{
@@ -493,7 +525,14 @@ func (vchr *voucherUseCase) VoucherGive(ech echo.Context, payload *models.Payloa
 		return nil, models.ErrVoucherExpired
 	}
 
-	voucherCode, err := vchr.voucherRepo.UpdatePromoCodeBought(ech, payload.VoucherID, payload.CIF)
+	// the voucher code is reserved until the reward transaction is succeeded
+	voucherCode, err := vchr.voucherRepo.UpdatePromoCodeReserved(ech, payload.VoucherID, payload.CIF, payload.RefID)
+
+	if err == sql.ErrNoRows {
+		requestLogger.Debug(models.ErrVoucherOutOfStock)
+
+		return nil, models.ErrVoucherOutOfStock
+	}
 
 	if err != nil {
 		requestLogger.Debug(models.ErrUpdatePromoCodes)
@@ -594,20 +633,29 @@ func (vchr *voucherUseCase) BadaiEmasGift(c echo.Context, plValidator *models.Pa
 
 	// validate voucher by loan amount
 	validVouchers := []*models.Voucher{}
+	var validationErrs models.ValidationErrors
 
 	for _, voucher := range vouchers {
 		err = voucher.Validators.Validate(plValidator)
 
 		if err == nil {
 			validVouchers = append(validVouchers, voucher)
+
+			continue
 		}
+
+		validationErrs = append(validationErrs, models.GetValidationErrors(err)...)
 	}
 
 	if len(validVouchers) < 1 {
 		// no valid voucher available
 		requestLogger.Debug(err)
 
-		return nil, models.ErrVoucherUnavailable
+		if len(validationErrs) == 0 {
+			return nil, models.ErrVoucherUnavailable
+		}
+
+		return nil, &models.ValidationFailure{Err: models.ErrVoucherUnavailable, Errors: validationErrs}
 	}
 
 	// get latest voucher